@@ -1,6 +1,11 @@
 package alert_source
 
-import "main/pkg/types"
+import (
+	"context"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"main/pkg/utils/normalize"
+)
 
 type Prefixes struct {
 	PaginatedFiringAlerts string
@@ -8,7 +13,22 @@ type Prefixes struct {
 
 type AlertSource interface {
 	Enabled() bool
-	GetAlertingRules() (types.GrafanaAlertGroups, error)
+	GetAlertingRules(ctx context.Context) (types.GrafanaAlertGroups, error)
 	Name() string
 	Prefixes() Prefixes
 }
+
+// PausableAlertSource is an alert source which rules evaluation can be paused and resumed.
+// Only Grafana-managed rules support that.
+type PausableAlertSource interface {
+	AlertSource
+	SetAlertRulePaused(ctx context.Context, uid string, paused bool) error
+}
+
+func NewPrefixes(name string) Prefixes {
+	prefix := normalize.NormalizeCommand(name) + "_"
+
+	return Prefixes{
+		PaginatedFiringAlerts: prefix + constants.PaginatedFiringAlertsListSuffix,
+	}
+}
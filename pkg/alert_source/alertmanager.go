@@ -0,0 +1,73 @@
+package alert_source
+
+import (
+	"context"
+	"main/pkg/config"
+	"main/pkg/http"
+	"main/pkg/types"
+	"net/url"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+type Alertmanager struct {
+	Config *config.AlertmanagerConfig
+	Logger zerolog.Logger
+	Client *http.Client
+}
+
+func InitAlertmanager(config *config.AlertmanagerConfig, logger *zerolog.Logger) *Alertmanager {
+	return &Alertmanager{
+		Config: config,
+		Logger: logger.With().Str("component", "alertmanager").Logger(),
+		Client: http.NewClient(logger, "alertmanager:"+config.GetName(), config.GetHTTPConfig()),
+	}
+}
+
+func (a *Alertmanager) Enabled() bool {
+	return a.Config != nil && a.Config.Alerts != nil
+}
+
+func (a *Alertmanager) Name() string {
+	return a.Config.GetName()
+}
+
+func (a *Alertmanager) Prefixes() Prefixes {
+	return NewPrefixes(a.Name())
+}
+
+func (a *Alertmanager) GetAuth() *http.Auth {
+	if a.Config == nil || a.Config.User == "" || a.Config.Password == "" {
+		return nil
+	}
+
+	return &http.Auth{Username: a.Config.User, Password: a.Config.Password}
+}
+
+func (a *Alertmanager) GetAlertsQueryString() string {
+	query := url.Values{}
+	query.Add("active", strconv.FormatBool(a.Config.Alerts.Active.Bool))
+	query.Add("silenced", strconv.FormatBool(a.Config.Alerts.Silenced.Bool))
+	query.Add("inhibited", strconv.FormatBool(a.Config.Alerts.Inhibited.Bool))
+
+	if a.Config.Alerts.Receiver != "" {
+		query.Add("receiver", a.Config.Alerts.Receiver)
+	}
+
+	return query.Encode()
+}
+
+func (a *Alertmanager) GetAlertingRules(ctx context.Context) (types.GrafanaAlertGroups, error) {
+	if !a.Enabled() {
+		return types.GrafanaAlertGroups{}, nil
+	}
+
+	alerts := types.AlertmanagerAlerts{}
+	err := a.Client.Get(ctx, a.Config.URL+"/api/v2/alerts?"+a.GetAlertsQueryString(), &alerts, a.GetAuth())
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts.ToAlertGroups(), nil
+}
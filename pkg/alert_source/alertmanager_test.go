@@ -0,0 +1,124 @@
+package alert_source
+
+import (
+	"context"
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	loggerPkg "main/pkg/logger"
+	"testing"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertmanagerBasic(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	config := &configPkg.AlertmanagerConfig{
+		URL:    "http://localhost:9093",
+		Alerts: &configPkg.AlertmanagerAlertsConfig{},
+	}
+	client := InitAlertmanager(config, logger)
+
+	require.True(t, client.Enabled())
+	require.Equal(t, "Alertmanager", client.Name())
+	require.Equal(t, "alertmanager:Alertmanager", client.Client.Querier)
+	require.Equal(t, "alertmanager_paginated_firing_alerts_list_", client.Prefixes().PaginatedFiringAlerts)
+}
+
+func TestAlertmanagerAlertsDisabled(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+
+	require.False(t, InitAlertmanager(nil, logger).Enabled())
+
+	client := InitAlertmanager(&configPkg.AlertmanagerConfig{URL: "http://localhost:9093"}, logger)
+	require.False(t, client.Enabled())
+
+	alertingRules, err := client.GetAlertingRules(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, alertingRules)
+}
+
+func TestAlertmanagerGetAlertsQueryString(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	config := &configPkg.AlertmanagerConfig{
+		URL: "http://localhost:9093",
+		Alerts: &configPkg.AlertmanagerAlertsConfig{
+			Active:    null.BoolFrom(true),
+			Silenced:  null.BoolFrom(true),
+			Inhibited: null.BoolFrom(false),
+			Receiver:  "team-(payments|infra)",
+		},
+	}
+	client := InitAlertmanager(config, logger)
+
+	require.Equal(
+		t,
+		"active=true&inhibited=false&receiver=team-%28payments%7Cinfra%29&silenced=true",
+		client.GetAlertsQueryString(),
+	)
+}
+
+//nolint:paralleltest
+func TestAlertmanagerGetAlertingRulesFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := &configPkg.AlertmanagerConfig{
+		URL:    "https://example.com",
+		Alerts: &configPkg.AlertmanagerAlertsConfig{Active: null.BoolFrom(true)},
+	}
+	client := InitAlertmanager(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/v2/alerts?active=true&inhibited=false&silenced=false",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	alertingRules, err := client.GetAlertingRules(context.Background())
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Empty(t, alertingRules)
+}
+
+//nolint:paralleltest
+func TestAlertmanagerGetAlertingRulesOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := &configPkg.AlertmanagerConfig{
+		URL:      "https://example.com",
+		User:     "admin",
+		Password: "admin",
+		Alerts: &configPkg.AlertmanagerAlertsConfig{
+			Active:   null.BoolFrom(true),
+			Silenced: null.BoolFrom(true),
+		},
+	}
+	client := InitAlertmanager(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/v2/alerts?active=true&inhibited=false&silenced=true",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-alerts.json")),
+	)
+
+	alertingRules, err := client.GetAlertingRules(context.Background())
+	require.NoError(t, err)
+	require.Len(t, alertingRules, 1)
+	require.Equal(t, "SlinkyTimeSinceLatestUpdate", alertingRules[0].Name)
+	require.Len(t, alertingRules[0].Rules, 1)
+	require.Len(t, alertingRules[0].Rules[0].Alerts, 1)
+	// the alert is silenced, so it is not considered firing
+	require.Equal(t, "suppressed", alertingRules[0].Rules[0].State)
+	require.Equal(t, "suppressed", alertingRules[0].Rules[0].Alerts[0].State)
+}
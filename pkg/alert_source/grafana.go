@@ -1,9 +1,9 @@
 package alert_source
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/config"
-	"main/pkg/constants"
 	"main/pkg/http"
 	"main/pkg/types"
 
@@ -17,15 +17,19 @@ type Grafana struct {
 }
 
 func InitGrafana(config config.GrafanaConfig, logger *zerolog.Logger) *Grafana {
+	client := http.NewClient(logger, "grafana:"+config.GetName(), config.HTTPConfig)
+	// Without it, rules updated via the provisioning API cannot be edited in Grafana UI anymore.
+	client.Headers = map[string]string{"X-Disable-Provenance": "true"}
+
 	return &Grafana{
 		Config: config,
 		Logger: logger.With().Str("component", "grafana").Logger(),
-		Client: http.NewClient(logger, "grafana"),
+		Client: client,
 	}
 }
 
 func (g *Grafana) Name() string {
-	return "Grafana"
+	return g.Config.GetName()
 }
 
 func (g *Grafana) Enabled() bool {
@@ -45,22 +49,36 @@ func (g *Grafana) GetAuth() *http.Auth {
 }
 
 func (g *Grafana) Prefixes() Prefixes {
-	return Prefixes{
-		PaginatedFiringAlerts: constants.GrafanaPaginatedFiringAlertsList,
-	}
+	return NewPrefixes(g.Name())
 }
 
 func (g *Grafana) RelativeLink(url string) string {
 	return fmt.Sprintf("%s%s", g.Config.URL, url)
 }
 
-func (g *Grafana) GetAlertingRules() (types.GrafanaAlertGroups, error) {
+func (g *Grafana) GetAlertingRules(ctx context.Context) (types.GrafanaAlertGroups, error) {
 	rules := types.GrafanaAlertRulesResponse{}
 	url := g.RelativeLink("/api/prometheus/grafana/api/v1/rules")
-	err := g.Client.Get(url, &rules, g.GetAuth())
+	err := g.Client.Get(ctx, url, &rules, g.GetAuth())
 	if err != nil {
 		return nil, err
 	}
 
 	return rules.Data.Groups, nil
 }
+
+// SetAlertRulePaused pauses or resumes the rule evaluation. The provisioning API
+// expects the whole rule, so it is fetched first and sent back as is, except isPaused.
+func (g *Grafana) SetAlertRulePaused(ctx context.Context, uid string, paused bool) error {
+	url := g.RelativeLink("/api/v1/provisioning/alert-rules/" + uid)
+
+	rule := map[string]interface{}{}
+	if err := g.Client.Get(ctx, url, &rule, g.GetAuth()); err != nil {
+		return err
+	}
+
+	rule["isPaused"] = paused
+
+	updatedRule := map[string]interface{}{}
+	return g.Client.Put(ctx, url, rule, &updatedRule, g.GetAuth())
+}
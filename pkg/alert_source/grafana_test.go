@@ -1,6 +1,7 @@
 package alert_source
 
 import (
+	"context"
 	"errors"
 	"main/assets"
 	configPkg "main/pkg/config"
@@ -25,6 +26,7 @@ func TestGrafanaBasic(t *testing.T) {
 
 	require.True(t, client.Enabled())
 	require.Equal(t, "Grafana", client.Name())
+	require.Equal(t, "grafana:Grafana", client.Client.Querier)
 }
 
 //nolint:paralleltest
@@ -41,7 +43,7 @@ func TestGrafanaGetAlertingRulesFail(t *testing.T) {
 		"https://example.com/api/prometheus/grafana/api/v1/rules",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	rules, err := client.GetAlertingRules()
+	rules, err := client.GetAlertingRules(context.Background())
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, rules)
@@ -61,7 +63,76 @@ func TestGrafanaGetAlertingRulesOk(t *testing.T) {
 		"https://example.com/api/prometheus/grafana/api/v1/rules",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
 
-	rules, err := client.GetAlertingRules()
+	rules, err := client.GetAlertingRules(context.Background())
 	require.NoError(t, err)
 	require.NotEmpty(t, rules)
 }
+
+//nolint:paralleltest
+func TestGrafanaSetAlertRulePausedGetFail(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/v1/provisioning/alert-rules/disk-full",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	err := client.SetAlertRulePaused(context.Background(), "disk-full", true)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+}
+
+//nolint:paralleltest
+func TestGrafanaSetAlertRulePausedUpdateFail(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/v1/provisioning/alert-rules/disk-full",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alert-rule-ok.json")))
+
+	httpmock.RegisterResponder(
+		"PUT",
+		"https://example.com/api/v1/provisioning/alert-rules/disk-full",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	err := client.SetAlertRulePaused(context.Background(), "disk-full", true)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+}
+
+//nolint:paralleltest
+func TestGrafanaSetAlertRulePausedOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/v1/provisioning/alert-rules/disk-full",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alert-rule-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"PUT",
+		"https://example.com/api/v1/provisioning/alert-rules/disk-full",
+		httpmock.BodyContainsString(`"isPaused":true`).
+			And(httpmock.BodyContainsString(`"title":"DiskFull"`)).
+			And(httpmock.HeaderIs("X-Disable-Provenance", "true")),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alert-rule-ok.json")))
+
+	err := client.SetAlertRulePaused(context.Background(), "disk-full", true)
+	require.NoError(t, err)
+}
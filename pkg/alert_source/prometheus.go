@@ -1,8 +1,8 @@
 package alert_source
 
 import (
+	"context"
 	"main/pkg/config"
-	"main/pkg/constants"
 	"main/pkg/http"
 	"main/pkg/types"
 
@@ -19,7 +19,7 @@ func InitPrometheus(config *config.PrometheusConfig, logger *zerolog.Logger) *Pr
 	return &Prometheus{
 		Config: config,
 		Logger: logger.With().Str("component", "prometheus").Logger(),
-		Client: http.NewClient(logger, "prometheus"),
+		Client: http.NewClient(logger, "prometheus:"+config.GetName(), config.GetHTTPConfig()),
 	}
 }
 
@@ -28,13 +28,11 @@ func (p *Prometheus) Enabled() bool {
 }
 
 func (p *Prometheus) Name() string {
-	return "Prometheus"
+	return p.Config.GetName()
 }
 
-func (g *Prometheus) Prefixes() Prefixes {
-	return Prefixes{
-		PaginatedFiringAlerts: constants.PrometheusPaginatedFiringAlertsList,
-	}
+func (p *Prometheus) Prefixes() Prefixes {
+	return NewPrefixes(p.Name())
 }
 
 func (p *Prometheus) GetAuth() *http.Auth {
@@ -45,13 +43,13 @@ func (p *Prometheus) GetAuth() *http.Auth {
 	return &http.Auth{Username: p.Config.User, Password: p.Config.Password}
 }
 
-func (p *Prometheus) GetAlertingRules() (types.GrafanaAlertGroups, error) {
+func (p *Prometheus) GetAlertingRules(ctx context.Context) (types.GrafanaAlertGroups, error) {
 	if !p.Enabled() {
 		return types.GrafanaAlertGroups{}, nil
 	}
 
 	rules := types.GrafanaAlertRulesResponse{}
-	err := p.Client.Get(p.Config.URL+"/api/v1/rules", &rules, p.GetAuth())
+	err := p.Client.Get(ctx, p.Config.URL+"/api/v1/rules", &rules, p.GetAuth())
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package alert_source
 
 import (
+	"context"
 	"errors"
 	"main/assets"
 	configPkg "main/pkg/config"
@@ -21,6 +22,19 @@ func TestPrometheusBasic(t *testing.T) {
 
 	require.True(t, client.Enabled())
 	require.Equal(t, "Prometheus", client.Name())
+	require.Equal(t, "prometheus_paginated_firing_alerts_list_", client.Prefixes().PaginatedFiringAlerts)
+}
+
+func TestPrometheusCustomName(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	config := &configPkg.PrometheusConfig{Name: "EU Prometheus", URL: "http://localhost:9090"}
+	client := InitPrometheus(config, logger)
+
+	require.Equal(t, "EU Prometheus", client.Name())
+	require.Equal(t, "prometheus:EU Prometheus", client.Client.Querier)
+	require.Equal(t, "eu_prometheus_paginated_firing_alerts_list_", client.Prefixes().PaginatedFiringAlerts)
 }
 
 func TestPrometheusGetAlertingRulesDisabled(t *testing.T) {
@@ -29,7 +43,7 @@ func TestPrometheusGetAlertingRulesDisabled(t *testing.T) {
 	logger := loggerPkg.GetNopLogger()
 	client := InitPrometheus(nil, logger)
 
-	alertingRules, err := client.GetAlertingRules()
+	alertingRules, err := client.GetAlertingRules(context.Background())
 	require.NoError(t, err)
 	require.Empty(t, alertingRules)
 }
@@ -48,7 +62,7 @@ func TestPrometheusGetAlertingRulesFailed(t *testing.T) {
 		"https://example.com/api/v1/rules",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	alertingRules, err := client.GetAlertingRules()
+	alertingRules, err := client.GetAlertingRules(context.Background())
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, alertingRules)
@@ -69,7 +83,7 @@ func TestPrometheusGetAlertingRulesOkWithoutAuth(t *testing.T) {
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")),
 	)
 
-	alertingRules, err := client.GetAlertingRules()
+	alertingRules, err := client.GetAlertingRules(context.Background())
 	require.NoError(t, err)
 	require.NotEmpty(t, alertingRules)
 }
@@ -89,7 +103,7 @@ func TestPrometheusGetAlertingRulesOkWithAuth(t *testing.T) {
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")),
 	)
 
-	alertingRules, err := client.GetAlertingRules()
+	alertingRules, err := client.GetAlertingRules(context.Background())
 	require.NoError(t, err)
 	require.NotEmpty(t, alertingRules)
 }
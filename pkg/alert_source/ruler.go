@@ -0,0 +1,77 @@
+package alert_source
+
+import (
+	"context"
+	"main/pkg/config"
+	"main/pkg/http"
+	"main/pkg/types"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Ruler is an alert source for any ruler exposing the Prometheus-compatible
+// rules API under a path prefix, like Loki (/prometheus), Mimir (/prometheus),
+// Cortex (/api/prom) or vmalert (no prefix).
+type Ruler struct {
+	Config *config.RulerConfig
+	Logger zerolog.Logger
+	Client *http.Client
+}
+
+func InitRuler(config *config.RulerConfig, logger *zerolog.Logger) *Ruler {
+	return &Ruler{
+		Config: config,
+		Logger: logger.With().Str("component", "ruler").Str("name", config.GetName()).Logger(),
+		Client: http.NewClient(logger, "ruler:"+config.GetName(), config.HTTPConfig),
+	}
+}
+
+func (r *Ruler) Enabled() bool {
+	return r.Config != nil
+}
+
+func (r *Ruler) Name() string {
+	return r.Config.GetName()
+}
+
+func (r *Ruler) Prefixes() Prefixes {
+	return NewPrefixes(r.Name())
+}
+
+func (r *Ruler) GetAuth() *http.Auth {
+	if r.Config.User == "" && r.Config.Password == "" && r.Config.Token == "" && r.Config.TenantID == "" {
+		return nil
+	}
+
+	return &http.Auth{
+		Username: r.Config.User,
+		Password: r.Config.Password,
+		Token:    r.Config.Token,
+		TenantID: r.Config.TenantID,
+	}
+}
+
+func (r *Ruler) GetRulesURL() string {
+	url := strings.TrimSuffix(r.Config.URL, "/")
+
+	if prefix := strings.Trim(r.Config.PathPrefix, "/"); prefix != "" {
+		url += "/" + prefix
+	}
+
+	return url + "/api/v1/rules"
+}
+
+func (r *Ruler) GetAlertingRules(ctx context.Context) (types.GrafanaAlertGroups, error) {
+	if !r.Enabled() {
+		return types.GrafanaAlertGroups{}, nil
+	}
+
+	rules := types.GrafanaAlertRulesResponse{}
+	err := r.Client.Get(ctx, r.GetRulesURL(), &rules, r.GetAuth())
+	if err != nil {
+		return nil, err
+	}
+
+	return rules.Data.Groups, nil
+}
@@ -0,0 +1,96 @@
+package alert_source
+
+import (
+	"context"
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	loggerPkg "main/pkg/logger"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulerBasic(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	config := &configPkg.RulerConfig{Name: "Loki", URL: "http://localhost:3100"}
+	client := InitRuler(config, logger)
+
+	require.True(t, client.Enabled())
+	require.Equal(t, "Loki", client.Name())
+	require.Equal(t, "ruler:Loki", client.Client.Querier)
+	require.Equal(t, "loki_paginated_firing_alerts_list_", client.Prefixes().PaginatedFiringAlerts)
+	require.Nil(t, client.GetAuth())
+}
+
+func TestRulerGetRulesURL(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+
+	require.Equal(
+		t,
+		"http://vmalert:8880/api/v1/rules",
+		InitRuler(&configPkg.RulerConfig{URL: "http://vmalert:8880/"}, logger).GetRulesURL(),
+	)
+	require.Equal(
+		t,
+		"http://loki:3100/prometheus/api/v1/rules",
+		InitRuler(&configPkg.RulerConfig{URL: "http://loki:3100", PathPrefix: "/prometheus/"}, logger).GetRulesURL(),
+	)
+}
+
+//nolint:paralleltest
+func TestRulerGetAlertingRulesFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := &configPkg.RulerConfig{URL: "https://example.com", PathPrefix: "/prometheus"}
+	client := InitRuler(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/prometheus/api/v1/rules",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	alertingRules, err := client.GetAlertingRules(context.Background())
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Empty(t, alertingRules)
+}
+
+//nolint:paralleltest
+func TestRulerGetAlertingRulesOkWithTenant(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := &configPkg.RulerConfig{
+		URL:        "https://example.com",
+		PathPrefix: "/prometheus",
+		TenantID:   "team-payments",
+		User:       "admin",
+		Password:   "admin",
+	}
+	client := InitRuler(config, logger)
+
+	httpmock.RegisterMatcherResponder(
+		"GET",
+		"https://example.com/prometheus/api/v1/rules",
+		httpmock.NewMatcher("tenant", func(req *http.Request) bool {
+			username, password, ok := req.BasicAuth()
+			return ok && username == "admin" && password == "admin" &&
+				req.Header.Get("X-Scope-OrgID") == "team-payments"
+		}),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")),
+	)
+
+	alertingRules, err := client.GetAlertingRules(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, alertingRules)
+}
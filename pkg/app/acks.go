@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"main/pkg/utils"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// GetAckButton returns a button to acknowledge the alert, with the alert info stored in cache.
+func (a *App) GetAckButton(menu *tele.ReplyMarkup, text string, ack types.AlertAck) tele.Btn {
+	key := a.Cache.SetObject(constants.AlertToAckCachePrefix+types.GetLabelsHash(ack.Labels), ack)
+	return menu.Data(text, constants.AckAlertPrefix, key)
+}
+
+func (a *App) HandleAckAlertFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got ack alert callback")
+
+	ack := types.AlertAck{}
+	if !a.Cache.GetObject(callback.Data, &ack) {
+		return c.Reply("Alert was not found!")
+	}
+
+	hash := types.GetLabelsHash(ack.Labels)
+	formatDate := utils.FormatDate(a.TemplateManager.Timezone)
+
+	var existingAck *types.AlertAck
+
+	a.UpdateAlertAcks(func(acks types.AlertAcks) {
+		if existingAck = acks.Get(hash); existingAck != nil {
+			return
+		}
+
+		ack.AckedBy = GetUserDisplayName(c.Sender())
+		ack.AckedAt = time.Now()
+		acks[hash] = ack
+	})
+
+	if existingAck != nil {
+		return c.Reply(fmt.Sprintf(
+			"Alert is already acked by %s at %s.",
+			existingAck.AckedBy,
+			formatDate(existingAck.AckedAt),
+		))
+	}
+
+	a.RemoveKeyboardItemByCallback(c, callback)
+
+	if callback.Message == nil {
+		return nil
+	}
+
+	// Appending the ack info to the message, keeping its formatting.
+	text := fmt.Sprintf(
+		"%s\n\n👀 %s acked by %s at %s",
+		callback.Message.Text,
+		ack.AlertName,
+		ack.AckedBy,
+		formatDate(ack.AckedAt),
+	)
+
+	if _, err := a.Bot.Edit(callback.Message, text, &tele.SendOptions{
+		Entities:              callback.Message.Entities,
+		ReplyMarkup:           callback.Message.ReplyMarkup,
+		DisableWebPagePreview: true,
+	}); err != nil {
+		a.Logger.Error().Err(err).Msg("Error updating message when acking an alert")
+		return err
+	}
+
+	return nil
+}
+
+func (a *App) HandleListAcks(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got list acks query")
+
+	a.ExpireStaleAlertAcks(context.Background())
+
+	return a.ReplyRender(c, "acks_list", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data:    types.AlertAcksListStruct{Acks: a.GetAlertAcks().ToSortedList()},
+	})
+}
+
+// ExpireStaleAlertAcks removes acks for the alerts that are not firing anymore.
+// Alert sources that could not be queried are skipped, so their acks are kept.
+func (a *App) ExpireStaleAlertAcks(ctx context.Context) {
+	firingAlertsSnapshots := make(map[string]types.FiringAlertsSnapshot)
+
+	for _, alertSourceWithSilenceManager := range a.AlertSourcesWithSilenceManager {
+		alertSource := alertSourceWithSilenceManager.AlertSource
+		if !alertSource.Enabled() {
+			continue
+		}
+
+		alerts, err := alertSource.GetAlertingRules(ctx)
+		if err != nil {
+			a.Logger.Warn().
+				Err(err).
+				Str("alert_source", alertSource.Name()).
+				Msg("Error fetching alerts when expiring acks")
+			continue
+		}
+
+		firingAlertsSnapshots[alertSource.Name()] = alerts.FilterFiringOrPendingAlertGroups(false).ToFiringAlertsSnapshot()
+	}
+
+	a.ExpireAlertAcks(firingAlertsSnapshots)
+}
+
+// ExpireAlertAcks removes acks for the alerts that are not in the firing alerts snapshot
+// of their alert source or became active after being acked, meaning they were resolved
+// and fired again, and the ones with TTL passed, like the acks for webhook alerts,
+// which are not tied to any alert source. Acks for alert sources without a snapshot are kept.
+func (a *App) ExpireAlertAcks(firingAlertsSnapshots map[string]types.FiringAlertsSnapshot) {
+	now := time.Now()
+
+	a.UpdateAlertAcks(func(acks types.AlertAcks) {
+		for hash, ack := range acks {
+			if ack.IsExpired(now) {
+				delete(acks, hash)
+				continue
+			}
+
+			if snapshot, found := firingAlertsSnapshots[ack.AlertSourceName]; found {
+				if alert, firing := snapshot[hash]; !firing || alert.Alert.ActiveAt.After(ack.AckedAt) {
+					delete(acks, hash)
+				}
+			}
+		}
+	})
+}
+
+// ExpireAlertAck removes the alert ack once the alert is resolved, returning it, if any.
+func (a *App) ExpireAlertAck(labels map[string]string) *types.AlertAck {
+	var ack *types.AlertAck
+
+	hash := types.GetLabelsHash(labels)
+	if a.GetAlertAcks().Get(hash) == nil {
+		return nil
+	}
+
+	a.UpdateAlertAcks(func(acks types.AlertAcks) {
+		ack = acks.Get(hash)
+		delete(acks, hash)
+	})
+
+	return ack
+}
+
+func (a *App) GetAlertAcks() types.AlertAcks {
+	a.AlertAcksMutex.Lock()
+	defer a.AlertAcksMutex.Unlock()
+
+	acks := types.AlertAcks{}
+	a.Cache.GetObject(constants.AlertAcksCacheKey, &acks)
+
+	return acks
+}
+
+// UpdateAlertAcks loads alerts acks from cache, lets the callback
+// modify them and saves them back, so concurrent updates are not lost.
+func (a *App) UpdateAlertAcks(update func(acks types.AlertAcks)) {
+	a.AlertAcksMutex.Lock()
+	defer a.AlertAcksMutex.Unlock()
+
+	acks := types.AlertAcks{}
+	a.Cache.GetObject(constants.AlertAcksCacheKey, &acks)
+
+	update(acks)
+
+	a.Cache.SetObject(constants.AlertAcksCacheKey, acks)
+}
+
+func GetUserDisplayName(user *tele.User) string {
+	if user.Username != "" {
+		return "@" + user.Username
+	}
+
+	return user.FirstName
+}
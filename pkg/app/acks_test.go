@@ -0,0 +1,223 @@
+package app
+
+import (
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+)
+
+//nolint:paralleltest // disabled
+func TestAppAckAlertNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Alert was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAckAlertFromCallback(newTestCallbackContext(app, constants.AckAlertPrefix, "not-existing", "Alert is firing"))
+	require.NoError(t, err)
+	require.Empty(t, app.GetAlertAcks())
+}
+
+//nolint:paralleltest // disabled
+func TestAppAckAlertAlreadyAcked(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	labels := map[string]string{"alertname": "HighLatency"}
+	hash := types.GetLabelsHash(labels)
+
+	app.UpdateAlertAcks(func(acks types.AlertAcks) {
+		acks[hash] = types.AlertAck{
+			AlertSourceName: "Prometheus",
+			AlertName:       "HighLatency",
+			Labels:          labels,
+			AckedBy:         "@anotheruser",
+			AckedAt:         time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		}
+	})
+
+	menu := &tele.ReplyMarkup{}
+	button := app.GetAckButton(menu, "👀 Ack", types.AlertAck{
+		AlertSourceName: "Prometheus",
+		AlertName:       "HighLatency",
+		Labels:          labels,
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Alert is already acked by @anotheruser at Mon, 01 Jan 2024 12:00:00 GMT."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAckAlertFromCallback(newTestCallbackContext(app, constants.AckAlertPrefix, button.Data, "Alert is firing"))
+	require.NoError(t, err)
+	require.Equal(t, "@anotheruser", app.GetAlertAcks()[hash].AckedBy)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAckAlertOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	labels := map[string]string{"alertname": "HighLatency"}
+	hash := types.GetLabelsHash(labels)
+
+	menu := &tele.ReplyMarkup{}
+	button := app.GetAckButton(menu, "👀 Ack", types.AlertAck{
+		AlertSourceName: "Prometheus",
+		AlertName:       "HighLatency",
+		Labels:          labels,
+	})
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageText",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAckAlertFromCallback(newTestCallbackContext(app, constants.AckAlertPrefix, button.Data, "Alert is firing"))
+	require.NoError(t, err)
+
+	acks := app.GetAlertAcks()
+	require.Len(t, acks, 1)
+	require.Equal(t, "@testuser", acks[hash].AckedBy)
+	require.Equal(t, "Prometheus", acks[hash].AlertSourceName)
+
+	callsInfo := httpmock.GetCallCountInfo()
+	require.Equal(t, 1, callsInfo["POST https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup"])
+	require.Equal(t, 1, callsInfo["POST https://api.telegram.org/botxxx:yyy/editMessageText"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppListAcksEmpty(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-empty.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("No acked alerts."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/acks",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleListAcks(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppListAcksExpiresResolved(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Prometheus = []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}}
+	})
+
+	app.UpdateAlertAcks(func(acks types.AlertAcks) {
+		// Acks for the alert sources that were queried successfully should expire.
+		acks["resolved"] = types.AlertAck{
+			AlertSourceName: "Prometheus",
+			AlertName:       "Resolved",
+			AckedBy:         "@testuser",
+			AckedAt:         time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		}
+		// Acks for the alert sources that were not queried should stay.
+		acks["unknown"] = types.AlertAck{
+			AlertSourceName: "Alertmanager",
+			AlertName:       "Unknown",
+			AckedBy:         "@testuser",
+			AckedAt:         time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-empty.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("👀 <strong>Acked alerts:</strong>\n- Alertmanager: Unknown, acked by @testuser at Mon, 01 Jan 2024 12:00:00 GMT"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/acks",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleListAcks(ctx)
+	require.NoError(t, err)
+
+	acks := app.GetAlertAcks()
+	require.Len(t, acks, 1)
+	require.NotNil(t, acks.Get("unknown"))
+}
+
+//nolint:paralleltest // disabled
+func TestAppExpireAlertAck(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	labels := map[string]string{"alertname": "HighLatency"}
+
+	require.Nil(t, app.ExpireAlertAck(labels))
+
+	app.UpdateAlertAcks(func(acks types.AlertAcks) {
+		acks[types.GetLabelsHash(labels)] = types.AlertAck{AlertName: "HighLatency", AckedBy: "@testuser"}
+	})
+
+	ack := app.ExpireAlertAck(labels)
+	require.NotNil(t, ack)
+	require.Equal(t, "@testuser", ack.AckedBy)
+	require.Empty(t, app.GetAlertAcks())
+}
+
+func TestGetUserDisplayName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "@testuser", GetUserDisplayName(&tele.User{Username: "testuser", FirstName: "Test"}))
+	require.Equal(t, "Test", GetUserDisplayName(&tele.User{FirstName: "Test"}))
+}
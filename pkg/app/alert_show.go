@@ -1,9 +1,13 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"main/pkg/constants"
 	"main/pkg/types"
 	"main/pkg/types/render"
+	"main/pkg/utils/generic"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,20 +27,95 @@ func (a *App) HandleSingleAlert(c tele.Context) error {
 		return c.Reply("Usage: /alert <alert name>")
 	}
 
-	rules, err := a.GetAllAlertingRules()
+	rules, err := a.GetAllAlertingRules(context.Background())
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error querying alerts: %s", err))
 	}
 
-	rule, found := rules.FindAlertRuleByName(args[0])
+	results := rules.SearchAlertRulesByName(args[0])
+	if results.IsAmbiguous() {
+		return a.ReplyAlertRuleChoice(c, results.Top(constants.SearchCandidatesInOneMessage), args[0])
+	}
+
+	rule, found := results.Best()
 	if !found {
 		return c.Reply("Could not find alert. See /alerts for alerting rules.")
 	}
 
+	return a.ShowAlertRule(c, rule.Rule)
+}
+
+// ReplyAlertRuleChoice asks to choose one of the alert rules matching the /alert query,
+// if none of them matches it clearly better than the others.
+func (a *App) ReplyAlertRuleChoice(
+	c tele.Context,
+	rules []types.GrafanaAlertRuleWithGroup,
+	query string,
+) error {
+	choice := types.AlertRuleChoice{
+		ChatID:    c.Chat().ID,
+		MessageID: c.Message().ID,
+		Rules: generic.Map(rules, func(rule types.GrafanaAlertRuleWithGroup) types.AlertRuleReference {
+			return types.AlertRuleReference{GroupName: rule.GroupName, RuleName: rule.Rule.Name}
+		}),
+	}
+
+	key := a.Cache.SetObject(constants.AlertRuleChoiceCachePrefix+choice.GetHash(), choice)
+
+	menu := GenerateChoiceMenu(
+		rules,
+		func(rule types.GrafanaAlertRuleWithGroup) string { return rule.GroupName + " / " + rule.Rule.Name },
+		constants.ShowAlertRulePrefix,
+		func(rule types.GrafanaAlertRuleWithGroup, index int) string { return fmt.Sprintf("%s %d", key, index) },
+	)
+
+	return c.Reply(fmt.Sprintf("Found multiple alerts matching \"%s\", choose one:", query), menu)
+}
+
+func (a *App) HandleShowAlertRuleFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got show alert rule callback")
+
+	data := strings.SplitN(callback.Data, " ", 2)
+	if len(data) != 2 {
+		return c.Reply("Invalid callback provided!")
+	}
+
+	choice := types.AlertRuleChoice{}
+	if !a.Cache.GetObject(data[0], &choice) {
+		return c.Reply("Alert query was not found!")
+	}
+
+	index, err := strconv.Atoi(data[1])
+	if err != nil || index < 0 || index >= len(choice.Rules) {
+		return c.Reply("Invalid alert provided!")
+	}
+
+	rules, err := a.GetAllAlertingRules(context.Background())
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying alerts: %s", err))
+	}
+
+	rule, found := rules.FindAlertRule(choice.Rules[index].GroupName, choice.Rules[index].RuleName)
+	if !found {
+		return c.Reply("Alert rule was not found!")
+	}
+
+	a.Cache.Delete(data[0])
+	_ = a.ClearKeyboard(c)
+
+	return a.ShowAlertRule(c, *rule)
+}
+
+func (a *App) ShowAlertRule(c tele.Context, rule types.GrafanaAlertRule) error {
 	return a.ReplyRender(c, "alert", render.RenderStruct{
 		Grafana: a.Grafana,
 		Data: types.SingleAlertStruct{
-			Alert:      rule,
+			Alert:      &rule,
 			RenderTime: time.Now(),
 		},
 	})
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"main/assets"
 	configPkg "main/pkg/config"
+	"main/pkg/constants"
 	"main/pkg/fs"
 	"main/pkg/types"
 	"main/pkg/types/render"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,7 +28,7 @@ func TestAppShowAlertInvalidInvocation(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -65,7 +68,7 @@ func TestAppShowAlertFailedToFetchAlerts(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -110,7 +113,7 @@ func TestAppShowAlertRuleNotFound(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -155,7 +158,7 @@ func TestAppShowAlertRuleOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -199,7 +202,7 @@ func TestAppShowAlertRenderOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -251,3 +254,151 @@ func TestAppShowAlertRenderOk(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+//nolint:paralleltest // disabled
+func TestAppShowAlertAmbiguous(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	key := constants.AlertRuleChoiceCachePrefix + types.AlertRuleChoice{ChatID: 2}.GetHash()
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasTextAndMarkup(
+			"Found multiple alerts matching \"cosmosnode\", choose one:",
+			types.TelegramInlineKeyboardResponse{InlineKeyboard: [][]types.TelegramInlineKeyboard{
+				{{
+					Unique:       constants.ShowAlertRulePrefix,
+					Text:         "CosmosNodeExporter / CosmosNodeNotLatestBinary",
+					CallbackData: "\f" + constants.ShowAlertRulePrefix + "|" + key + " 0",
+				}},
+				{{
+					Unique:       constants.ShowAlertRulePrefix,
+					Text:         "CosmosNodeExporter / CosmosNodeNotLatestBinary2",
+					CallbackData: "\f" + constants.ShowAlertRulePrefix + "|" + key + " 1",
+				}},
+				{{
+					Unique:       constants.ClearKeyboardPrefix,
+					Text:         "❌Cancel",
+					CallbackData: "\f" + constants.ClearKeyboardPrefix,
+				}},
+			}},
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSingleAlert(newTestContext(app, "/alert cosmosnode"))
+	require.NoError(t, err)
+
+	choice := types.AlertRuleChoice{}
+	require.True(t, app.Cache.GetObject(key, &choice))
+	require.Equal(t, []types.AlertRuleReference{
+		{GroupName: "CosmosNodeExporter", RuleName: "CosmosNodeNotLatestBinary"},
+		{GroupName: "CosmosNodeExporter", RuleName: "CosmosNodeNotLatestBinary2"},
+	}, choice.Rules)
+}
+
+//nolint:paralleltest // disabled
+func TestAppShowAlertFromCallbackInvalidIndex(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	choice := types.AlertRuleChoice{ChatID: 2, Rules: []types.AlertRuleReference{
+		{GroupName: "CosmosNodeExporter", RuleName: "CosmosNodeNotLatestBinary"},
+	}}
+	key := app.Cache.SetObject(constants.AlertRuleChoiceCachePrefix+choice.GetHash(), choice)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid alert provided!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleShowAlertRuleFromCallback(newTestCallbackContext(app, constants.ShowAlertRulePrefix, key+" abc", "Found multiple alerts matching \"cosmos\", choose one:"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppShowAlertFromCallbackRuleDeleted(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	choice := types.AlertRuleChoice{ChatID: 2, Rules: []types.AlertRuleReference{
+		{GroupName: "CosmosNodeExporter", RuleName: "Deleted"},
+	}}
+	key := app.Cache.SetObject(constants.AlertRuleChoiceCachePrefix+choice.GetHash(), choice)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Alert rule was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleShowAlertRuleFromCallback(newTestCallbackContext(app, constants.ShowAlertRulePrefix, key+" 0", "Found multiple alerts matching \"cosmos\", choose one:"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppShowAlertFromCallbackOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	choice := types.AlertRuleChoice{ChatID: 2, Rules: []types.AlertRuleReference{
+		{GroupName: "CosmosNodeExporter", RuleName: "CosmosNodeNotLatestBinary"},
+		{GroupName: "CosmosNodeExporter", RuleName: "CosmosNodeNotLatestBinary2"},
+	}}
+	key := app.Cache.SetObject(constants.AlertRuleChoiceCachePrefix+choice.GetHash(), choice)
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	var text string
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		func(req *http.Request) (*http.Response, error) {
+			var response types.TelegramResponse
+			if err := json.NewDecoder(req.Body).Decode(&response); err != nil {
+				return nil, err
+			}
+
+			text = response.Text
+			return httpmock.NewBytesResponse(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")), nil
+		})
+
+	err := app.HandleShowAlertRuleFromCallback(newTestCallbackContext(app, constants.ShowAlertRulePrefix, key+" 1", "Found multiple alerts matching \"cosmos\", choose one:"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(text, "<strong>Alert rule: </strong> CosmosNodeNotLatestBinary2\n"))
+	require.False(t, app.Cache.GetObject(key, &choice))
+}
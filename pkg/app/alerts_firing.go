@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/alert_source"
 	"main/pkg/constants"
@@ -95,25 +96,47 @@ func (a *App) HandleListFiringAlertsWithPagination(
 		a.ClearAllKeyboardCache(c)
 	}
 
-	alerts, err := alertSource.GetAlertingRules()
+	alerts, err := alertSource.GetAlertingRules(context.Background())
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error fetching alerts: %s!\n", err))
 	}
 
-	firingAlertsAll := alerts.FilterFiringOrPendingAlertGroups(false).ToFiringAlerts()
+	firingAlertGroups := alerts.FilterFiringOrPendingAlertGroups(false)
+	firingAlertsAll := firingAlertGroups.ToFiringAlerts()
 	firingAlerts, totalPages := generic.Paginate(firingAlertsAll, page, constants.AlertsInOneMessage)
 
-	menu := GenerateMenuWithPagination(
+	// Expiring acks for this alert source with the alerts we already have, so alerts
+	// that were resolved and fired again do not show acks when the watcher is disabled.
+	a.ExpireAlertAcks(map[string]types.FiringAlertsSnapshot{
+		alertSource.Name(): firingAlertGroups.ToFiringAlertsSnapshot(),
+	})
+
+	acks := a.GetAlertAcks()
+
+	menu := GenerateMenuWithPaginationAndButtons(
 		firingAlerts,
-		func(elt types.FiringAlert, index int) string { return fmt.Sprintf("🔇Silence alert #%d", index+1) },
-		silenceManager.Prefixes().PrepareSilence,
-		func(elt types.FiringAlert) string {
-			key := a.Cache.Set(elt.Alert.GetHash(), elt.Alert.SerializeLabels())
-			return key
+		func(menu *tele.ReplyMarkup, elt types.FiringAlert, index int) []tele.Btn {
+			buttons := []tele.Btn{menu.Data(
+				fmt.Sprintf("🔇Silence alert #%d", index+1),
+				silenceManager.Prefixes().PrepareSilence,
+				a.Cache.Set(elt.Alert.GetHash(), elt.Alert.SerializeLabels()),
+			)}
+
+			if acks.Get(elt.Alert.GetHash()) == nil {
+				buttons = append(buttons, a.GetAckButton(
+					menu,
+					fmt.Sprintf("👀 Ack alert #%d", index+1),
+					elt.ToAlertAck(alertSource.Name()),
+				))
+			}
+
+			return buttons
 		},
 		alertSource.Prefixes().PaginatedFiringAlerts,
 		page,
 		totalPages,
+		DefaultPrevPagePrefix,
+		DefaultNextPagePrefix,
 	)
 
 	templateData := render.RenderStruct{
@@ -124,6 +147,7 @@ func (a *App) HandleListFiringAlertsWithPagination(
 			AlertsCount:     len(firingAlertsAll),
 			Start:           page*constants.AlertsInOneMessage + 1,
 			End:             page*constants.AlertsInOneMessage + len(firingAlerts),
+			Acks:            acks,
 			RenderTime:      time.Now(),
 		},
 	}
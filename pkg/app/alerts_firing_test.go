@@ -5,7 +5,6 @@ import (
 	"errors"
 	"main/assets"
 	configPkg "main/pkg/config"
-	"main/pkg/constants"
 	"main/pkg/fs"
 	"main/pkg/types"
 	"main/pkg/types/render"
@@ -27,10 +26,10 @@ func TestAppFiringAlertsNoAlertSources(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:    "https://example.com",
 			Alerts: null.BoolFrom(false),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -70,9 +69,9 @@ func TestAppFiringAlertsMultipleAlertSources(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Alerts: null.BoolFrom(true)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(true)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -130,9 +129,9 @@ func TestAppFiringAlertsAlertSourceFail(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Alerts: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -175,9 +174,9 @@ func TestAppFiringAlertsAlertLastPage(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Alerts: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -206,7 +205,7 @@ func TestAppFiringAlertsAlertLastPage(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.PrometheusPaginatedFiringAlertsList,
+			Unique: "\fprometheus_paginated_firing_alerts_list_",
 			Data:   "1",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -232,9 +231,9 @@ func TestAppAlertsFiringRenderOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Alerts: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -264,7 +263,7 @@ func TestAppAlertsFiringRenderOk(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.PrometheusPaginatedFiringAlertsList,
+			Unique: "\fprometheus_paginated_firing_alerts_list_",
 			Data:   "1",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -308,9 +307,9 @@ func TestAppFiringAlertsAlertFirstPage(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Alerts: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -352,9 +351,9 @@ func TestAppFiringAlertsAlertInvalidCallback(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Alerts: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -379,7 +378,7 @@ func TestAppFiringAlertsAlertInvalidCallback(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.PrometheusPaginatedFiringAlertsList,
+			Unique: "\fprometheus_paginated_firing_alerts_list_",
 			Data:   "not-a-number",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -405,8 +404,8 @@ func TestAppFiringAlertsAlertAlertSourceDisabled(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Alerts: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
 		Prometheus:   nil,
 	}
 
@@ -432,7 +431,7 @@ func TestAppFiringAlertsAlertAlertSourceDisabled(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.PrometheusPaginatedFiringAlertsList,
+			Unique: "\fprometheus_paginated_firing_alerts_list_",
 			Data:   "0",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -458,9 +457,9 @@ func TestAppFiringAlertsAlertNoAlerts(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Alerts: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -490,7 +489,7 @@ func TestAppFiringAlertsAlertNoAlerts(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.PrometheusPaginatedFiringAlertsList,
+			Unique: "\fprometheus_paginated_firing_alerts_list_",
 			Data:   "0",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -506,3 +505,98 @@ func TestAppFiringAlertsAlertNoAlerts(t *testing.T) {
 	)(ctx)
 	require.NoError(t, err)
 }
+
+//nolint:paralleltest // disabled
+func TestAppFiringAlertsExpiresRefiredAlertAcks(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:     "Etc/GMT",
+		Log:          configPkg.LogConfig{LogLevel: "info"},
+		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	var keyboard types.TelegramInlineKeyboardResponse
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		captureKeyboard(&keyboard),
+	)
+
+	alertRulesRaw := assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")
+	var alertRules types.GrafanaAlertRulesResponse
+	err := json.Unmarshal(alertRulesRaw, &alertRules)
+	require.NoError(t, err)
+
+	alerts := alertRules.Data.Groups.FilterFiringOrPendingAlertGroups(false).ToFiringAlerts()
+	require.Len(t, alerts, 3)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+
+	// The first alert was acked, then resolved and fired again,
+	// the second one is firing since before it was acked.
+	refiredHash := alerts[0].Alert.GetHash()
+	ackedHash := alerts[1].Alert.GetHash()
+
+	app.UpdateAlertAcks(func(acks types.AlertAcks) {
+		acks[refiredHash] = types.AlertAck{
+			AlertSourceName: "Prometheus",
+			Labels:          alerts[0].Alert.Labels,
+			AckedBy:         "@testuser",
+			AckedAt:         alerts[0].Alert.ActiveAt.Add(-time.Hour),
+		}
+		acks[ackedHash] = types.AlertAck{
+			AlertSourceName: "Prometheus",
+			Labels:          alerts[1].Alert.Labels,
+			AckedBy:         "@testuser",
+			AckedAt:         alerts[1].Alert.ActiveAt.Add(time.Hour),
+		}
+	})
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/firing",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err = app.HandleListFiringAlertsWithPagination(
+		ctx,
+		app.AlertSourcesWithSilenceManager[1].AlertSource,
+		app.AlertSourcesWithSilenceManager[1].SilenceManager,
+		0,
+		false,
+	)
+	require.NoError(t, err)
+
+	acks := app.GetAlertAcks()
+	require.Len(t, acks, 1)
+	require.Nil(t, acks.Get(refiredHash))
+	require.NotNil(t, acks.Get(ackedHash))
+
+	buttons := make([]string, 0)
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			buttons = append(buttons, button.Text)
+		}
+	}
+
+	require.Contains(t, buttons, "👀 Ack alert #1")
+	require.NotContains(t, buttons, "👀 Ack alert #2")
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/types"
 	"main/pkg/types/render"
@@ -21,7 +22,7 @@ func (a *App) HandleListAlerts(c tele.Context) error {
 			continue
 		}
 
-		alertGroups, err := alertSource.AlertSource.GetAlertingRules()
+		alertGroups, err := alertSource.AlertSource.GetAlertingRules(context.Background())
 		if err != nil {
 			return c.Reply(fmt.Sprintf("Error querying alerts: %s", err))
 		}
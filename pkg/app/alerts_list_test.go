@@ -23,10 +23,10 @@ func TestAppListAlertsNoAlertSources(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:    "https://example.com",
 			Alerts: null.BoolFrom(false),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -66,10 +66,10 @@ func TestAppListAlertsAlertSourceFail(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:    "https://example.com",
 			Alerts: null.BoolFrom(true),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -114,12 +114,12 @@ func TestAppListAlertsOk(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:    "https://example.com",
 			Alerts: null.BoolFrom(true),
-		},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "https://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "https://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -157,3 +157,32 @@ func TestAppListAlertsOk(t *testing.T) {
 	err := app.HandleListAlerts(ctx)
 	require.NoError(t, err)
 }
+
+//nolint:paralleltest // disabled
+func TestAppListAlertsWithPausedRules(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"<strong>Grafana alerts:</strong>\n"+
+				"- 🟢 Disks -> DiskFull\n"+
+				"- 🟢 Disks -> DiskSlow\n"+
+				"Paused rules:\n"+
+				"- ⏸ Disks -> DiskSlow",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	err := app.HandleListAlerts(newTestContext(app, "/alerts"))
+	require.NoError(t, err)
+}
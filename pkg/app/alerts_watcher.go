@@ -0,0 +1,190 @@
+package app
+
+import (
+	"context"
+	"main/pkg/alert_source"
+	"main/pkg/constants"
+	"main/pkg/silence_manager"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"slices"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) StartAlertsWatcher(ctx context.Context) {
+	if a.Config.Notifications == nil {
+		a.Logger.Debug().Msg("Notifications are not configured, not starting alerts watcher")
+		return
+	}
+
+	a.Logger.Info().
+		Dur("interval", a.Config.Notifications.Interval).
+		Msg("Starting alerts watcher")
+
+	ticker := time.NewTicker(a.Config.Notifications.Interval)
+	defer ticker.Stop()
+
+	a.CheckFiringAlerts(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.Logger.Info().Msg("Stopping alerts watcher")
+			return
+		case <-ticker.C:
+			a.CheckFiringAlerts(ctx)
+		}
+	}
+}
+
+// CheckFiringAlerts notifies about started and resolved alerts, and expires
+// the acks of the alerts that are not firing anymore, as it has them already.
+func (a *App) CheckFiringAlerts(ctx context.Context) {
+	firingAlertsSnapshots := make(map[string]types.FiringAlertsSnapshot)
+
+	for _, alertSourceWithSilenceManager := range a.AlertSourcesWithSilenceManager {
+		alertSource := alertSourceWithSilenceManager.AlertSource
+		if !alertSource.Enabled() {
+			continue
+		}
+
+		if snapshot, ok := a.CheckFiringAlertsForAlertSource(
+			ctx,
+			alertSource,
+			alertSourceWithSilenceManager.SilenceManager,
+		); ok {
+			firingAlertsSnapshots[alertSource.Name()] = snapshot
+		}
+	}
+
+	a.ExpireAlertAcks(firingAlertsSnapshots)
+}
+
+// CheckFiringAlertsForAlertSource notifies about the alert source started and resolved alerts,
+// returning the current firing alerts snapshot, or false if the alerts could not be fetched.
+func (a *App) CheckFiringAlertsForAlertSource(
+	ctx context.Context,
+	alertSource alert_source.AlertSource,
+	silenceManager silence_manager.SilenceManager,
+) (types.FiringAlertsSnapshot, bool) {
+	alerts, err := alertSource.GetAlertingRules(ctx)
+	if err != nil {
+		a.Logger.Warn().
+			Err(err).
+			Str("alert_source", alertSource.Name()).
+			Msg("Error fetching alerts when checking for firing alerts")
+		return nil, false
+	}
+
+	cacheKey := constants.FiringAlertsSnapshotCachePrefix + alertSource.Name()
+	currentSnapshot := alerts.FilterFiringOrPendingAlertGroups(false).ToFiringAlertsSnapshot()
+
+	var previousSnapshot types.FiringAlertsSnapshot
+	previousSnapshotFound := a.Cache.GetObject(cacheKey, &previousSnapshot)
+	a.Cache.SetObject(cacheKey, currentSnapshot)
+
+	// On the first run there's nothing to compare with, and notifying about
+	// everything that is firing at the moment would just spam the chats.
+	if !previousSnapshotFound {
+		a.Logger.Debug().
+			Str("alert_source", alertSource.Name()).
+			Int("alerts", len(currentSnapshot)).
+			Msg("No previous firing alerts snapshot, saving the current one")
+		return currentSnapshot, true
+	}
+
+	started, resolved := currentSnapshot.Diff(previousSnapshot)
+
+	a.Logger.Debug().
+		Str("alert_source", alertSource.Name()).
+		Int("started", len(started)).
+		Int("resolved", len(resolved)).
+		Msg("Checked for firing alerts")
+
+	for _, alert := range started {
+		a.SendAlertNotification(alertSource, silenceManager, alert, false)
+	}
+
+	for _, alert := range resolved {
+		a.SendAlertNotification(alertSource, silenceManager, alert, true)
+	}
+
+	return currentSnapshot, true
+}
+
+func (a *App) SendAlertNotification(
+	alertSource alert_source.AlertSource,
+	silenceManager silence_manager.SilenceManager,
+	alert types.FiringAlert,
+	resolved bool,
+) {
+	var ack *types.AlertAck
+	if resolved {
+		ack = a.ExpireAlertAck(alert.Alert.Labels)
+	}
+
+	templateData := render.RenderStruct{
+		Grafana: a.Grafana,
+		Data: types.AlertNotificationStruct{
+			AlertSourceName: alertSource.Name(),
+			Alert:           alert,
+			Resolved:        resolved,
+			Ack:             ack,
+			RenderTime:      time.Now(),
+		},
+	}
+
+	opts := []interface{}{}
+	menu := &tele.ReplyMarkup{ResizeKeyboard: true}
+	buttons := []tele.Btn{}
+
+	if silenceManager.Enabled() {
+		buttons = append(buttons, menu.Data(
+			"🔇Silence",
+			silenceManager.Prefixes().PrepareSilence,
+			a.Cache.Set(alert.Alert.GetHash(), alert.Alert.SerializeLabels()),
+		))
+	}
+
+	if !resolved {
+		buttons = append(buttons, a.GetAckButton(menu, "👀 Ack", alert.ToAlertAck(alertSource.Name())))
+	}
+
+	if len(buttons) > 0 {
+		menu.Inline(menu.Row(buttons...))
+		opts = append(opts, menu)
+	}
+
+	for _, chatID := range a.GetAlertNotificationChats(alert) {
+		if err := a.SendRender(chatID, "alert_notification", templateData, opts...); err != nil {
+			a.Logger.Error().
+				Err(err).
+				Int64("chat_id", chatID).
+				Str("alert_source", alertSource.Name()).
+				Msg("Error sending alert notification")
+		}
+	}
+}
+
+// GetAlertNotificationChats returns the chats to notify about the alert: the ones
+// that get all notifications, and the ones with subscriptions matching the alert.
+func (a *App) GetAlertNotificationChats(alert types.FiringAlert) []int64 {
+	labels := make(map[string]string, len(alert.Alert.Labels)+1)
+	labels["alertname"] = alert.AlertRuleName
+
+	for key, value := range alert.Alert.Labels {
+		labels[key] = value
+	}
+
+	chats := slices.Clone(a.Config.Notifications.Chats)
+
+	for _, chatID := range a.GetSubscriptions().GetMatchingChats(labels) {
+		if !slices.Contains(chats, chatID) {
+			chats = append(chats, chatID)
+		}
+	}
+
+	return chats
+}
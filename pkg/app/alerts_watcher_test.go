@@ -0,0 +1,260 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/constants"
+	"main/pkg/fs"
+	"main/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // disabled
+func TestAppAlertsWatcherNotConfigured(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:     "Etc/GMT",
+		Log:          configPkg.LogConfig{LogLevel: "info"},
+		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(true)}},
+		Alertmanager: nil,
+		Prometheus:   nil,
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	app.StartAlertsWatcher(context.Background())
+
+	require.Zero(t, httpmock.GetCallCountInfo()["GET https://example.com/api/prometheus/grafana/api/v1/rules"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppAlertsWatcherStartAndStop(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:      "Etc/GMT",
+		Log:           configPkg.LogConfig{LogLevel: "info"},
+		Telegram:      configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:       []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(true)}},
+		Alertmanager:  nil,
+		Prometheus:    nil,
+		Notifications: &configPkg.NotificationsConfig{Interval: time.Millisecond, Chats: []int64{1}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	app.StartAlertsWatcher(ctx)
+
+	require.Positive(t, httpmock.GetCallCountInfo()["GET https://example.com/api/prometheus/grafana/api/v1/rules"])
+	require.Zero(t, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppAlertsWatcherFetchError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:      "Etc/GMT",
+		Log:           configPkg.LogConfig{LogLevel: "info"},
+		Telegram:      configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:       []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(true)}},
+		Alertmanager:  nil,
+		Prometheus:    nil,
+		Notifications: &configPkg.NotificationsConfig{Interval: time.Minute, Chats: []int64{1}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	app.CheckFiringAlerts(context.Background())
+
+	_, found := app.Cache.Get(constants.FiringAlertsSnapshotCachePrefix + "Grafana")
+	require.False(t, found)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAlertsWatcherSendNotifications(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:      "Etc/GMT",
+		Log:           configPkg.LogConfig{LogLevel: "info"},
+		Telegram:      configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:       []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(true), Silences: null.BoolFrom(true)}},
+		Alertmanager:  nil,
+		Prometheus:    nil,
+		Notifications: &configPkg.NotificationsConfig{Interval: time.Minute, Chats: []int64{1, 2}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+
+	// first run only saves the snapshot
+	app.CheckFiringAlerts(context.Background())
+	require.Zero(t, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+
+	// second run without changes sends nothing
+	app.CheckFiringAlerts(context.Background())
+	require.Zero(t, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+
+	var snapshot types.FiringAlertsSnapshot
+	require.True(t, app.Cache.GetObject(constants.FiringAlertsSnapshotCachePrefix+"Grafana", &snapshot))
+
+	for hash := range snapshot {
+		delete(snapshot, hash)
+		break
+	}
+
+	snapshot["resolved"] = types.FiringAlert{
+		GroupName:     "group",
+		AlertRuleName: "rule",
+		Alert:         types.GrafanaAlert{Labels: map[string]string{"alertname": "rule"}, State: "firing"},
+	}
+	app.Cache.SetObject(constants.FiringAlertsSnapshotCachePrefix+"Grafana", snapshot)
+
+	app.UpdateAlertAcks(func(acks types.AlertAcks) {
+		// Acks for the alerts that are not firing anymore should expire,
+		// even if the resolved notification was missed.
+		acks["missing"] = types.AlertAck{AlertSourceName: "Grafana", AlertName: "Missing"}
+		// Acks with TTL passed, like webhook ones, should expire.
+		acks["webhook-expired"] = types.AlertAck{AlertSourceName: "telegram", AckedAt: time.Now().Add(-time.Hour), TTL: time.Minute}
+		acks["webhook"] = types.AlertAck{AlertSourceName: "telegram", AckedAt: time.Now(), TTL: time.Hour}
+	})
+
+	// one started and one resolved alert, sent into 2 chats each
+	app.CheckFiringAlerts(context.Background())
+	require.Equal(t, 4, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+
+	acks := app.GetAlertAcks()
+	require.Len(t, acks, 1)
+	require.NotNil(t, acks.Get("webhook"))
+}
+
+//nolint:paralleltest // disabled
+func TestAppAlertsWatcherSendNotificationFail(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:      "Etc/GMT",
+		Log:           configPkg.LogConfig{LogLevel: "info"},
+		Telegram:      configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:       []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(true), Silences: null.BoolFrom(false)}},
+		Alertmanager:  nil,
+		Prometheus:    nil,
+		Notifications: &configPkg.NotificationsConfig{Interval: time.Minute, Chats: []int64{1}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	app.Cache.SetObject(constants.FiringAlertsSnapshotCachePrefix+"Grafana", types.FiringAlertsSnapshot{})
+
+	app.CheckFiringAlerts(context.Background())
+	require.Positive(t, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppGetAlertNotificationChats(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:      "Etc/GMT",
+		Log:           configPkg.LogConfig{LogLevel: "info"},
+		Telegram:      configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:       []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(true)}},
+		Notifications: &configPkg.NotificationsConfig{Interval: time.Minute, Chats: []int64{1}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	app.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+		subscriptions.Add(1, types.QueryMatcherFromKeyValueString("team=payments"))
+		subscriptions.Add(2, types.QueryMatcherFromKeyValueString("team=payments severity=~critical|warning"))
+		subscriptions.Add(3, types.QueryMatcherFromKeyValueString("HighCPU"))
+		subscriptions.Add(4, types.QueryMatcherFromKeyValueString("team=infra"))
+	})
+
+	chats := app.GetAlertNotificationChats(types.FiringAlert{
+		AlertRuleName: "HighCPU",
+		Alert: types.GrafanaAlert{
+			Labels: map[string]string{"team": "payments", "severity": "critical"},
+		},
+	})
+	require.Equal(t, []int64{1, 2, 3}, chats)
+}
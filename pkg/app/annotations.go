@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"main/pkg/constants"
+	"main/pkg/silence_manager"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"main/pkg/utils"
+	"main/pkg/utils/generic"
+	"strconv"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+const AnnotationsInList = 10
+
+func (a *App) HandleAnnotate(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got annotate query")
+
+	opts, text := utils.ParseCommandOptions(c.Text(), "dashboard", "tags")
+	if text == "" {
+		return c.Reply("Usage: /annotate [dashboard=<name>] [tags=tag1,tag2] <text>")
+	}
+
+	annotation := types.GrafanaAnnotation{
+		Time: time.Now().UnixMilli(),
+		Tags: types.ParseAnnotationTags(opts["tags"]),
+		Text: fmt.Sprintf("%s (by %s)", text, GetUserDisplayName(c.Sender())),
+	}
+
+	var dashboard *types.GrafanaDashboardInfo
+
+	if dashboardName, ok := opts["dashboard"]; ok {
+		dashboards, err := a.DashboardIndex.GetAllDashboards(context.Background())
+		if err != nil {
+			return c.Reply(fmt.Sprintf("Error querying dashboards: %s", err))
+		}
+
+		results := dashboards.SearchDashboardsByName(dashboardName)
+		if results.IsAmbiguous() {
+			return a.ReplyAnnotationDashboardChoice(
+				c,
+				results.Top(constants.SearchCandidatesInOneMessage),
+				annotation,
+				dashboardName,
+			)
+		}
+
+		found := false
+		if dashboard, found = results.Best(); !found {
+			return c.Reply("Could not find dashboard. See /dashboards for dashboards list.")
+		}
+	}
+
+	return a.CreateAnnotation(c, annotation, dashboard)
+}
+
+// ReplyAnnotationDashboardChoice asks to choose one of the dashboards matching the dashboard
+// name passed to /annotate, if none of them matches it clearly better than the others.
+func (a *App) ReplyAnnotationDashboardChoice(
+	c tele.Context,
+	dashboards []types.GrafanaDashboardInfo,
+	annotation types.GrafanaAnnotation,
+	dashboardName string,
+) error {
+	choice := types.AnnotationDashboardChoice{
+		ChatID:     c.Chat().ID,
+		MessageID:  c.Message().ID,
+		Annotation: annotation,
+		Dashboards: dashboards,
+	}
+
+	key := a.Cache.SetObject(constants.AnnotationChoiceCachePrefix+choice.GetHash(), choice)
+
+	menu := GenerateChoiceMenu(
+		dashboards,
+		GetDashboardChoiceText,
+		constants.AnnotateChooseDashboardPrefix,
+		func(dashboard types.GrafanaDashboardInfo, index int) string { return fmt.Sprintf("%s %d", key, index) },
+	)
+
+	return c.Reply(fmt.Sprintf("Found multiple dashboards matching \"%s\", choose one:", dashboardName), menu)
+}
+
+func (a *App) HandleAnnotateChooseDashboardFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got annotate dashboard choice callback")
+
+	data := strings.SplitN(callback.Data, " ", 2)
+	if len(data) != 2 {
+		return c.Reply("Invalid callback provided!")
+	}
+
+	choice := types.AnnotationDashboardChoice{}
+	if !a.Cache.GetObject(data[0], &choice) {
+		return c.Reply("Annotation was not found!")
+	}
+
+	index, err := strconv.Atoi(data[1])
+	if err != nil || index < 0 || index >= len(choice.Dashboards) {
+		return c.Reply("Invalid dashboard provided!")
+	}
+
+	a.Cache.Delete(data[0])
+	_ = a.ClearKeyboard(c)
+
+	return a.CreateAnnotation(c, choice.Annotation, &choice.Dashboards[index])
+}
+
+// CreateAnnotation creates the annotation, on the dashboard if it is passed,
+// and replies with the created annotation.
+func (a *App) CreateAnnotation(
+	c tele.Context,
+	annotation types.GrafanaAnnotation,
+	dashboard *types.GrafanaDashboardInfo,
+) error {
+	if dashboard != nil {
+		annotation.DashboardUID = dashboard.UID
+	}
+
+	annotationID, err := a.Grafana.CreateAnnotation(context.Background(), annotation)
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error creating annotation: %s", err))
+	}
+
+	annotation.ID = annotationID
+
+	return a.ReplyRender(c, "annotation_created", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data: types.AnnotationWithDashboard{
+			Annotation: annotation,
+			Dashboard:  dashboard,
+		},
+	})
+}
+
+func (a *App) HandleListAnnotations(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got annotations query")
+
+	annotations, err := a.Grafana.GetAnnotations(context.Background(), AnnotationsInList)
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying annotations: %s", err))
+	}
+
+	var dashboards types.GrafanaDashboardsInfo
+
+	// Dashboards are only needed to display links to them.
+	if _, found := generic.Find(annotations, func(annotation types.GrafanaAnnotation) bool {
+		return annotation.DashboardUID != ""
+	}); found {
+		if dashboards, err = a.DashboardIndex.GetAllDashboards(context.Background()); err != nil {
+			return c.Reply(fmt.Sprintf("Error querying dashboards: %s", err))
+		}
+	}
+
+	annotationsWithDashboards := generic.Map(annotations, func(annotation types.GrafanaAnnotation) types.AnnotationWithDashboard {
+		dashboard, _ := generic.Find(dashboards, func(dashboard types.GrafanaDashboardInfo) bool {
+			return dashboard.UID == annotation.DashboardUID
+		})
+
+		return types.AnnotationWithDashboard{Annotation: annotation, Dashboard: dashboard}
+	})
+
+	return a.ReplyRender(c, "annotations_list", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data:    annotationsWithDashboards,
+	})
+}
+
+// AnnotateSilence adds a Grafana annotation about a created silence, if enabled,
+// so graphs show when alerts were muted. Errors are only logged, as the silence
+// itself is created already.
+func (a *App) AnnotateSilence(
+	c tele.Context,
+	silenceManager silence_manager.SilenceManager,
+	silence types.Silence,
+	duration time.Duration,
+) {
+	if a.Config.Annotations == nil || !a.Config.Annotations.Silences {
+		return
+	}
+
+	matchers := generic.Map(silence.Matchers, func(matcher *types.SilenceMatcher) string {
+		return matcher.Serialize()
+	})
+
+	annotation := types.GrafanaAnnotation{
+		Time: silence.StartsAt.UnixMilli(),
+		Tags: a.Config.Annotations.SilenceTags,
+		Text: fmt.Sprintf(
+			"Silence created in %s by %s for %s: %s",
+			silenceManager.Name(),
+			GetUserDisplayName(c.Sender()),
+			utils.FormatDuration(duration),
+			strings.Join(matchers, ", "),
+		),
+	}
+
+	if _, err := a.Grafana.CreateAnnotation(context.Background(), annotation); err != nil {
+		a.Logger.Error().
+			Err(err).
+			Str("silence_manager", silenceManager.Name()).
+			Str("silence_id", silence.ID).
+			Msg("Error creating silence annotation")
+	}
+}
@@ -0,0 +1,392 @@
+package app
+
+import (
+	"errors"
+	"main/assets"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateInvalidInvocation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Usage: /annotate [dashboard=<name>] [tags=tag1,tag2] <text>"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotate(newTestContext(app, "/annotate tags=incident"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateDashboardsFetchError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error querying dashboards: Get \"https://example.com/api/search?type=dash-db\": custom error"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotate(newTestContext(app, "/annotate dashboard=disk Disk replaced"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateDashboardNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Could not find dashboard. See /dashboards for dashboards list."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotate(newTestContext(app, "/annotate dashboard=unknown Disk replaced"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateCreateError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/annotations",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error creating annotation: Post \"https://example.com/api/annotations\": custom error"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotate(newTestContext(app, "/annotate Deploy started"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/annotations",
+		httpmock.BodyContainsString(`"dashboardUID":"disk-graphs"`).
+			And(httpmock.BodyContainsString(`"tags":["incident","disk"]`)).
+			And(httpmock.BodyContainsString(`"text":"Disk replaced (by @testuser)"`)),
+		httpmock.NewStringResponder(200, `{"id":2,"message":"Annotation added"}`))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"✅ Annotation created on dashboard <a href='https://example.com/d/disk-graphs/node-exporter-disk-graphs'>node-exporter disk graphs</a>: Disk replaced (by @testuser)\n"+
+				"Tags: <code>incident</code>, <code>disk</code>",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotate(newTestContext(app, "/annotate dashboard=disk tags=incident,disk, Disk replaced"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateQuotedDashboardOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/annotations",
+		httpmock.BodyContainsString(`"dashboardUID":"disk-graphs"`).
+			And(httpmock.BodyContainsString(`"text":"Disk replaced (by @testuser)"`)),
+		httpmock.NewStringResponder(200, `{"id":2,"message":"Annotation added"}`))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"✅ Annotation created on dashboard <a href='https://example.com/d/disk-graphs/node-exporter-disk-graphs'>node-exporter disk graphs</a>: Disk replaced (by @testuser)",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotate(newTestContext(app, "/annotate dashboard=\"node-exporter disk graphs\" Disk replaced"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateAmbiguousDashboard(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+
+	key := constants.AnnotationChoiceCachePrefix + types.AnnotationDashboardChoice{ChatID: 2}.GetHash()
+
+	button := func(text string, index string) []types.TelegramInlineKeyboard {
+		return []types.TelegramInlineKeyboard{{
+			Unique:       constants.AnnotateChooseDashboardPrefix,
+			Text:         text,
+			CallbackData: "\f" + constants.AnnotateChooseDashboardPrefix + "|" + key + " " + index,
+		}}
+	}
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasTextAndMarkup(
+			"Found multiple dashboards matching \"unifi poller\", choose one:",
+			types.TelegramInlineKeyboardResponse{InlineKeyboard: [][]types.TelegramInlineKeyboard{
+				button("UniFi-Poller: Client DPI - Prometheus", "0"),
+				button("UniFi-Poller: Client Insights - Prometheus", "1"),
+				button("UniFi-Poller: UAP Insights - Prometheus", "2"),
+				{{
+					Unique:       constants.ClearKeyboardPrefix,
+					Text:         "❌Cancel",
+					CallbackData: "\f" + constants.ClearKeyboardPrefix,
+				}},
+			}},
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotate(newTestContext(app, "/annotate dashboard=\"unifi poller\" tags=upgrade Controller upgraded"))
+	require.NoError(t, err)
+
+	// The annotation is only created when the dashboard is chosen.
+	choice := types.AnnotationDashboardChoice{}
+	require.True(t, app.Cache.GetObject(key, &choice))
+	require.Equal(t, "Controller upgraded (by @testuser)", choice.Annotation.Text)
+	require.Equal(t, []string{"upgrade"}, choice.Annotation.Tags)
+	require.Len(t, choice.Dashboards, 3)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateChooseDashboardNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Annotation was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotateChooseDashboardFromCallback(newTestCallbackContext(app, constants.AnnotateChooseDashboardPrefix, "not-existing 0", "Found multiple dashboards matching \"unifi poller\", choose one:"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateChooseDashboardInvalidIndex(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	choice := types.AnnotationDashboardChoice{ChatID: 2, Dashboards: []types.GrafanaDashboardInfo{{UID: "unpoller-dpi"}}}
+	key := app.Cache.SetObject(constants.AnnotationChoiceCachePrefix+choice.GetHash(), choice)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid dashboard provided!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotateChooseDashboardFromCallback(newTestCallbackContext(app, constants.AnnotateChooseDashboardPrefix, key+" 1", "Found multiple dashboards matching \"unifi poller\", choose one:"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAnnotateChooseDashboardOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	choice := types.AnnotationDashboardChoice{
+		ChatID:     2,
+		Annotation: types.GrafanaAnnotation{Time: 1704110400000, Text: "Controller upgraded (by @testuser)"},
+		Dashboards: []types.GrafanaDashboardInfo{
+			{UID: "unpoller-dpi", Title: "UniFi-Poller: Client DPI - Prometheus", URL: "/d/unpoller-dpi/unifi-poller-client-dpi-prometheus"},
+			{UID: "unpoller-uap", Title: "UniFi-Poller: UAP Insights - Prometheus", URL: "/d/unpoller-uap/unifi-poller-uap-insights-prometheus"},
+		},
+	}
+	key := app.Cache.SetObject(constants.AnnotationChoiceCachePrefix+choice.GetHash(), choice)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/annotations",
+		httpmock.BodyContainsString(`"dashboardUID":"unpoller-uap"`).
+			And(httpmock.BodyContainsString(`"text":"Controller upgraded (by @testuser)"`)),
+		httpmock.NewStringResponder(200, `{"id":2,"message":"Annotation added"}`))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"✅ Annotation created on dashboard <a href='https://example.com/d/unpoller-uap/unifi-poller-uap-insights-prometheus'>UniFi-Poller: UAP Insights - Prometheus</a>: Controller upgraded (by @testuser)",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleAnnotateChooseDashboardFromCallback(newTestCallbackContext(app, constants.AnnotateChooseDashboardPrefix, key+" 1", "Found multiple dashboards matching \"unifi poller\", choose one:"))
+	require.NoError(t, err)
+
+	require.False(t, app.Cache.GetObject(key, &choice))
+}
+
+//nolint:paralleltest // disabled
+func TestAppListAnnotationsError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/annotations?type=annotation&limit=10",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error querying annotations: Get \"https://example.com/api/annotations?type=annotation&limit=10\": custom error"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleListAnnotations(newTestContext(app, "/annotations"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppListAnnotationsDashboardsError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/annotations?type=annotation&limit=10",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-annotations-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error querying dashboards: Get \"https://example.com/api/search?type=dash-db\": custom error"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleListAnnotations(newTestContext(app, "/annotations"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppListAnnotationsEmpty(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/annotations?type=annotation&limit=10",
+		httpmock.NewStringResponder(200, "[]"))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("<strong>Latest annotations</strong>\nNo annotations."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleListAnnotations(newTestContext(app, "/annotations"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppListAnnotationsOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/annotations?type=annotation&limit=10",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-annotations-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"<strong>Latest annotations</strong>\n"+
+				"- Mon, 01 Jan 2024 12:01:00 GMT by admin on <a href='https://example.com/d/disk-graphs/node-exporter-disk-graphs'>node-exporter disk graphs</a>: Disk replaced &lt;sda&gt; (<code>incident</code>, <code>disk</code>)\n"+
+				"- Mon, 01 Jan 2024 12:00:00 GMT: Deploy started",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleListAnnotations(newTestContext(app, "/annotations"))
+	require.NoError(t, err)
+}
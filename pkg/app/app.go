@@ -1,25 +1,35 @@
 package app
 
 import (
+	"context"
 	"main/pkg/alert_source"
+	"main/pkg/audit"
 	"main/pkg/cache"
 	"main/pkg/clients"
 	configPkg "main/pkg/config"
 	"main/pkg/constants"
+	"main/pkg/dashboard_index"
 	"main/pkg/fs"
 	loggerPkg "main/pkg/logger"
 	"main/pkg/silence_manager"
 	"main/pkg/templates"
+	"main/pkg/types"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	tele "gopkg.in/telebot.v3"
-	"gopkg.in/telebot.v3/middleware"
 	templatesList "main/templates"
 )
 
-const MaxMessageSize = 4096
+const (
+	MaxMessageSize = 4096
+	MaxCaptionSize = 1024
+	ChartWidth     = 1000
+	ChartHeight    = 500
+)
 
 type AlertSourceWithSilenceManager struct {
 	AlertSource    alert_source.AlertSource
@@ -29,13 +39,30 @@ type AlertSourceWithSilenceManager struct {
 type App struct {
 	Config          *configPkg.Config
 	Grafana         *clients.Grafana
+	DashboardIndex  *dashboard_index.DashboardIndex
+	Prometheus      *clients.Prometheus
+	Loki            *clients.Loki
 	TemplateManager *templates.TemplateManager
 	Logger          *zerolog.Logger
 	Bot             *tele.Bot
 	Version         string
 	Cache           *cache.Cache
+	AuditLog        *audit.Log
+	WebhookServer   *http.Server
+	MetricsServer   *http.Server
+
+	TrackedSilencesMutex sync.Mutex
+	SubscriptionsMutex   sync.Mutex
+	AlertAcksMutex       sync.Mutex
+
+	// Subscriptions are loaded from cache on first use and kept with their
+	// matchers compiled, so matching alerts does not recompile the regexes.
+	Subscriptions types.Subscriptions
 
 	AlertSourcesWithSilenceManager []AlertSourceWithSilenceManager
+	// SilenceManagers are all the configured silence managers, each one once,
+	// as the same silence manager can be paired with multiple alert sources.
+	SilenceManagers []silence_manager.SilenceManager
 
 	StopChannel chan bool
 }
@@ -44,7 +71,7 @@ func NewApp(config *configPkg.Config, filesystem fs.FS, version string) *App {
 	timezone, _ := time.LoadLocation(config.Timezone)
 
 	logger := loggerPkg.GetLogger(config.Log)
-	grafana := clients.InitGrafana(config.Grafana, logger)
+	grafana := clients.InitGrafana(config.GetMainGrafana(), logger)
 	templateManager := templates.NewTemplateManager(timezone, templatesList.Templates)
 
 	bot, err := tele.NewBot(tele.Settings{
@@ -58,96 +85,250 @@ func NewApp(config *configPkg.Config, filesystem fs.FS, version string) *App {
 		logger.Panic().Err(err).Msg("Could not start Telegram bot")
 	}
 
-	if len(config.Telegram.Admins) > 0 {
-		logger.Debug().Msg("Using admins whitelist")
-		bot.Use(middleware.Whitelist(config.Telegram.Admins...))
+	alertSourcesWithSilenceManagers := []AlertSourceWithSilenceManager{}
+	silenceManagers := []silence_manager.SilenceManager{}
+
+	// Built-in Grafana alerting and silences, one pair per Grafana instance
+	for _, grafanaConfig := range config.GetGrafanas() {
+		silenceManager := silence_manager.InitGrafana(grafanaConfig, logger)
+
+		silenceManagers = append(silenceManagers, silenceManager)
+		alertSourcesWithSilenceManagers = append(alertSourcesWithSilenceManagers, AlertSourceWithSilenceManager{
+			AlertSource:    alert_source.InitGrafana(grafanaConfig, logger),
+			SilenceManager: silenceManager,
+		})
 	}
 
-	alertSourcesWithSilenceManagers := []AlertSourceWithSilenceManager{
-		// Built-in Grafana alerting and silences
-		{
-			AlertSource:    alert_source.InitGrafana(config.Grafana, logger),
-			SilenceManager: silence_manager.InitGrafana(config.Grafana, logger),
-		},
-		// External Prometheus alerts source and Alertmanager silence manager
-		{
-			AlertSource:    alert_source.InitPrometheus(config.Prometheus, logger),
-			SilenceManager: silence_manager.InitAlertmanager(config.Alertmanager, logger),
-		},
+	// External Alertmanager silence managers. If there are none, a disabled one
+	// is still used, so its commands are registered and reply that it is disabled.
+	alertmanagerSilenceManagers := make(map[string]silence_manager.SilenceManager, len(config.Alertmanager))
+	disabledAlertmanager := silence_manager.InitAlertmanager(nil, logger)
+
+	for index := range config.Alertmanager {
+		alertmanagerConfig := &config.Alertmanager[index]
+		silenceManager := silence_manager.InitAlertmanager(alertmanagerConfig, logger)
+
+		silenceManagers = append(silenceManagers, silenceManager)
+		alertmanagerSilenceManagers[alertmanagerConfig.GetName()] = silenceManager
+	}
+
+	if len(config.Alertmanager) == 0 {
+		silenceManagers = append(silenceManagers, disabledAlertmanager)
+	}
+
+	getAlertmanagerSilenceManager := func(name string) silence_manager.SilenceManager {
+		if silenceManager, found := alertmanagerSilenceManagers[name]; found {
+			return silenceManager
+		}
+
+		return disabledAlertmanager
+	}
+
+	// External Prometheus alerts sources, paired with the Alertmanager they send
+	// alerts to as a silence manager. If there are none, a disabled one is still used,
+	// so the commands using it reply that it is disabled.
+	for index := range config.Prometheus {
+		prometheusConfig := &config.Prometheus[index]
+
+		alertSourcesWithSilenceManagers = append(alertSourcesWithSilenceManagers, AlertSourceWithSilenceManager{
+			AlertSource:    alert_source.InitPrometheus(prometheusConfig, logger),
+			SilenceManager: getAlertmanagerSilenceManager(config.GetPrometheusSilenceManager(prometheusConfig)),
+		})
+	}
+
+	if len(config.Prometheus) == 0 {
+		alertSourcesWithSilenceManagers = append(alertSourcesWithSilenceManagers, AlertSourceWithSilenceManager{
+			AlertSource:    alert_source.InitPrometheus(nil, logger),
+			SilenceManager: getAlertmanagerSilenceManager(config.GetPrometheusSilenceManager(&configPkg.PrometheusConfig{})),
+		})
+	}
+
+	// Loki, Mimir, Cortex and vmalert rulers, paired with the Alertmanager they send
+	// alerts to as a silence manager, or with a disabled one, if it is not set.
+	for index := range config.Rulers {
+		rulerConfig := &config.Rulers[index]
+
+		alertSourcesWithSilenceManagers = append(alertSourcesWithSilenceManagers, AlertSourceWithSilenceManager{
+			AlertSource:    alert_source.InitRuler(rulerConfig, logger),
+			SilenceManager: getAlertmanagerSilenceManager(rulerConfig.SilenceManager),
+		})
 	}
 
-	return &App{
+	// Alertmanagers used as alert sources, paired with themselves as silence managers.
+	for index := range config.Alertmanager {
+		alertmanagerConfig := &config.Alertmanager[index]
+		if alertmanagerConfig.Alerts == nil {
+			continue
+		}
+
+		alertSourcesWithSilenceManagers = append(alertSourcesWithSilenceManagers, AlertSourceWithSilenceManager{
+			AlertSource:    alert_source.InitAlertmanager(alertmanagerConfig, logger),
+			SilenceManager: getAlertmanagerSilenceManager(alertmanagerConfig.GetName()),
+		})
+	}
+
+	app := &App{
 		Config:                         config,
 		Logger:                         logger,
 		Grafana:                        grafana,
+		DashboardIndex:                 dashboard_index.NewDashboardIndex(grafana, &config.DashboardIndex, logger),
+		Prometheus:                     initPrometheusQuerier(config, logger),
+		Loki:                           initLokiQuerier(config, logger),
 		TemplateManager:                templateManager,
 		AlertSourcesWithSilenceManager: alertSourcesWithSilenceManagers,
+		SilenceManagers:                silenceManagers,
 		Bot:                            bot,
 		Version:                        version,
 		Cache:                          cache.NewCache(logger, filesystem, config.CachePath),
+		AuditLog:                       audit.NewLog(logger, filesystem, config.AuditLogPath),
 		StopChannel:                    make(chan bool),
 	}
+
+	if config.Webhook != nil {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/webhook", app.HandleWebhook)
+
+		app.WebhookServer = &http.Server{
+			Addr:              config.Webhook.ListenAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+	}
+
+	if config.Metrics != nil {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", app.HandleMetrics)
+		mux.HandleFunc("/healthz", app.HandleHealthz)
+		mux.HandleFunc("/readyz", app.HandleReadyz)
+
+		app.MetricsServer = &http.Server{
+			Addr:              config.Metrics.ListenAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+	}
+
+	return app
 }
 
 func (a *App) Start() {
 	a.Cache.Load()
 
 	// Commands
-	a.Bot.Handle("/start", a.HandleHelp)
-	a.Bot.Handle("/help", a.HandleHelp)
-	a.Bot.Handle("/dashboards", a.HandleListDashboards)
-	a.Bot.Handle("/dashboard", a.HandleShowDashboard)
-	a.Bot.Handle("/render", a.HandleRenderPanel)
-	a.Bot.Handle("/datasources", a.HandleListDatasources)
-	a.Bot.Handle("/alerts", a.HandleListAlerts)
-	a.Bot.Handle("/firing", a.HandleChooseAlertSourceForListFiringAlerts)
-	a.Bot.Handle("/alert", a.HandleSingleAlert)
-	a.Bot.Handle("/silences", a.HandleChooseSilenceManagerForListSilences)
+	a.Handle("/start", a.HandleHelp, types.RoleViewer)
+	a.Handle("/help", a.HandleHelp, types.RoleViewer)
+	a.Handle("/dashboards", a.HandleListDashboards, types.RoleViewer)
+	a.Handle("/dashboard", a.HandleShowDashboard, types.RoleViewer)
+	a.Handle("/render", a.HandleRenderPanel, types.RoleViewer)
+	a.Handle("/render_dashboard", a.HandleRenderDashboard, types.RoleViewer)
+	a.Handle("/datasources", a.HandleListDatasources, types.RoleViewer)
+	a.Handle("/alerts", a.HandleListAlerts, types.RoleViewer)
+	a.Handle("/firing", a.HandleChooseAlertSourceForListFiringAlerts, types.RoleViewer)
+	a.Handle("/alert", a.HandleSingleAlert, types.RoleViewer)
+	a.Handle("/silences", a.HandleChooseSilenceManagerForListSilences, types.RoleViewer)
+	a.Handle("/audit", a.HandleListAuditLog, types.RoleAdmin)
+	a.Handle("/subscribe", a.HandleSubscribe, types.RoleViewer)
+	a.Handle("/unsubscribe", a.HandleUnsubscribe, types.RoleViewer)
+	a.Handle("/acks", a.HandleListAcks, types.RoleViewer)
+	a.Handle("/query", a.HandleQuery, types.RoleViewer)
+	a.Handle("/query_range", a.HandleQueryRange, types.RoleViewer)
+	a.Handle("/ds_query", a.HandleDatasourceQuery, types.RoleViewer)
+	a.Handle("/logs", a.HandleLogs, types.RoleViewer)
+	a.Handle("/annotate", a.HandleAnnotate, types.RoleSilencer)
+	a.Handle("/annotations", a.HandleListAnnotations, types.RoleViewer)
+	a.Handle("/pause_rule", a.HandlePauseRule, types.RoleAdmin)
+	a.Handle("/resume_rule", a.HandleResumeRule, types.RoleAdmin)
 
 	// Callbacks
-	a.Bot.Handle("\f"+constants.GrafanaRenderChooseDashboardPrefix, a.HandleRenderChooseDashboardFromCallback)
-	a.Bot.Handle("\f"+constants.GrafanaRenderChoosePanelPrefix, a.HandleRenderPanelChoosePanelFromCallback)
-	a.Bot.Handle("\f"+constants.GrafanaRenderRenderPanelPrefix, a.HandleRenderPanelFromCallback)
-	a.Bot.Handle("\f"+constants.ClearKeyboardPrefix, a.ClearKeyboard)
+	a.Handle("\f"+constants.GrafanaRenderChooseDashboardPrefix, a.HandleRenderChooseDashboardFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.GrafanaRenderChoosePanelPrefix, a.HandleRenderPanelChoosePanelFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.GrafanaRenderRenderPanelPrefix, a.HandleRenderPanelFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.GrafanaRenderChooseVariablePrefix, a.HandleRenderChooseVariableFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.GrafanaRenderVariablesPagePrefix, a.HandleRenderVariablesPageFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.GrafanaRenderAllPrefix, a.HandleRenderAllFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.ClearKeyboardPrefix, a.ClearKeyboard, types.RoleViewer)
+	a.Handle("\f"+constants.PaginatedAuditLogPrefix, a.HandleListAuditLogFromCallback, types.RoleAdmin)
+	a.Handle("\f"+constants.UnsubscribePrefix, a.HandleUnsubscribeFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.AckAlertPrefix, a.HandleAckAlertFromCallback, types.RoleSilencer)
+	a.Handle("\f"+constants.LogsMorePrefix, a.HandleLogsMoreFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.PauseRulePrefix, a.HandleSetRulePausedFromCallback, types.RoleAdmin)
+	a.Handle("\f"+constants.GrafanaRenderChooseFoundPanelPrefix, a.HandleRenderChooseFoundPanelFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.ShowDashboardPrefix, a.HandleShowDashboardFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.ShowAlertRulePrefix, a.HandleShowAlertRuleFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.RenderChooseFoundDashboardPrefix, a.HandleRenderChooseFoundDashboardFromCallback, types.RoleViewer)
+	a.Handle("\f"+constants.AnnotateChooseDashboardPrefix, a.HandleAnnotateChooseDashboardFromCallback, types.RoleSilencer)
 
 	for _, alertSourceWithSilenceManager := range a.AlertSourcesWithSilenceManager {
 		alertSource := alertSourceWithSilenceManager.AlertSource
 		silenceManager := alertSourceWithSilenceManager.SilenceManager
-		alertSourcePrefixes := alertSourceWithSilenceManager.AlertSource.Prefixes()
+
+		a.Handle("\f"+alertSource.Prefixes().PaginatedFiringAlerts, a.HandleListFiringAlertsFromCallback(alertSource, silenceManager), types.RoleViewer)
+	}
+
+	for _, silenceManager := range a.SilenceManagers {
 		silencesPrefixes := silenceManager.Prefixes()
 
 		// Commands
-		a.Bot.Handle("/"+silencesPrefixes.ListSilencesCommand, a.HandleListSilences(silenceManager))
-		a.Bot.Handle("/"+silencesPrefixes.SilenceCommand, a.HandleNewSilenceViaCommand(silenceManager))
-		a.Bot.Handle("/"+silencesPrefixes.UnsilenceCommand, a.HandleDeleteSilenceViaCommand(silenceManager))
+		a.Handle("/"+silencesPrefixes.ListSilencesCommand, a.HandleListSilences(silenceManager), types.RoleViewer)
+		a.Handle("/"+silencesPrefixes.SilenceCommand, a.HandleNewSilenceViaCommand(silenceManager), types.RoleSilencer)
+		a.Handle("/"+silencesPrefixes.UnsilenceCommand, a.HandleDeleteSilenceViaCommand(silenceManager), types.RoleAdmin)
+		a.Handle("/"+silencesPrefixes.EditSilenceCommand, a.HandleEditSilenceViaCommand(silenceManager), types.RoleSilencer)
 
 		// Callbacks
-		a.Bot.Handle("\f"+alertSourcePrefixes.PaginatedFiringAlerts, a.HandleListFiringAlertsFromCallback(alertSource, silenceManager))
-		a.Bot.Handle("\f"+silencesPrefixes.PaginatedSilencesList, a.HandleListSilencesFromCallback(silenceManager))
-		a.Bot.Handle("\f"+silencesPrefixes.Unsilence, a.HandleCallbackDeleteSilence(silenceManager))
-		a.Bot.Handle("\f"+silencesPrefixes.PrepareSilence, a.HandlePrepareNewSilenceFromCallback(silenceManager, alertSource))
-		a.Bot.Handle("\f"+silencesPrefixes.Silence, a.HandleCallbackNewSilence(silenceManager, alertSource))
+		a.Handle("\f"+silencesPrefixes.PaginatedSilencesList, a.HandleListSilencesFromCallback(silenceManager), types.RoleViewer)
+		a.Handle("\f"+silencesPrefixes.Unsilence, a.HandleCallbackDeleteSilence(silenceManager), types.RoleAdmin)
+		a.Handle("\f"+silencesPrefixes.PrepareSilence, a.HandlePrepareNewSilenceFromCallback(silenceManager), types.RoleSilencer)
+		a.Handle("\f"+silencesPrefixes.Silence, a.HandleCallbackNewSilence(silenceManager), types.RoleSilencer)
+		a.Handle("\f"+silencesPrefixes.ExtendSilence, a.HandleCallbackExtendSilence(silenceManager), types.RoleSilencer)
+		a.Handle("\f"+silencesPrefixes.PrepareEditSilence, a.HandlePrepareEditSilenceFromCallback(silenceManager), types.RoleSilencer)
+		a.Handle("\f"+silencesPrefixes.EditSilence, a.HandleCallbackEditSilence(silenceManager), types.RoleSilencer)
 	}
 
 	a.Logger.Info().Msg("Telegram bot listening")
 
+	ctx, cancel := context.WithCancel(context.Background())
+
 	go a.Bot.Start()
+	go a.StartAlertsWatcher(ctx)
+	go a.StartSilenceRemindersWatcher(ctx)
+	go a.StartReportsScheduler(ctx)
+	go a.DashboardIndex.Start(ctx)
+	go a.StartWebhookServer()
+	go a.StartMetricsServer()
 
 	<-a.StopChannel
 	a.Logger.Info().Msg("Shutting down...")
+	cancel()
+	a.StopWebhookServer()
+	a.StopMetricsServer()
 	a.Bot.Stop()
 }
 
 func (a *App) BotReply(c tele.Context, msg string, opts ...interface{}) error {
-	msgsByNewline := strings.Split(msg, "\n")
+	opts = append(opts, tele.ModeHTML, tele.NoPreview)
 
-	var sb strings.Builder
+	return a.BotSendInChunks(msg, func(chunk string) error {
+		return c.Reply(chunk, opts...)
+	})
+}
 
+func (a *App) BotSend(chatID int64, msg string, opts ...interface{}) error {
 	opts = append(opts, tele.ModeHTML, tele.NoPreview)
 
+	return a.BotSendInChunks(msg, func(chunk string) error {
+		_, err := a.Bot.Send(tele.ChatID(chatID), chunk, opts...)
+		return err
+	})
+}
+
+func (a *App) BotSendInChunks(msg string, send func(chunk string) error) error {
+	msgsByNewline := strings.Split(msg, "\n")
+
+	var sb strings.Builder
+
 	for _, line := range msgsByNewline {
 		if sb.Len()+len(line) > MaxMessageSize {
-			if err := c.Reply(sb.String(), opts...); err != nil {
+			if err := send(sb.String()); err != nil {
 				a.Logger.Error().Err(err).Msg("Could not send Telegram message")
 				return err
 			}
@@ -158,7 +339,7 @@ func (a *App) BotReply(c tele.Context, msg string, opts ...interface{}) error {
 		sb.WriteString(line + "\n")
 	}
 
-	if err := c.Reply(strings.TrimSpace(sb.String()), opts...); err != nil {
+	if err := send(strings.TrimSpace(sb.String())); err != nil {
 		a.Logger.Error().Err(err).Msg("Could not send Telegram message")
 		return err
 	}
@@ -166,6 +347,13 @@ func (a *App) BotReply(c tele.Context, msg string, opts ...interface{}) error {
 	return nil
 }
 
+// Handle registers a command or callback handler, checking whether the user
+// has the role required and recording metrics. The role is checked first,
+// so the calls denied are not counted as handled.
+func (a *App) Handle(endpoint string, handler tele.HandlerFunc, role types.Role) {
+	a.Bot.Handle(endpoint, handler, a.RequireRole(role), a.RecordMetrics(endpoint))
+}
+
 func (a *App) Stop() {
 	a.StopChannel <- true
 }
@@ -5,12 +5,15 @@ import (
 	"main/assets"
 	configPkg "main/pkg/config"
 	"main/pkg/fs"
+	"main/pkg/silence_manager"
+	"main/pkg/utils/generic"
 	"strings"
 	"sync"
 	"testing"
 
 	tele "gopkg.in/telebot.v3"
 
+	"github.com/guregu/null/v5"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 )
@@ -30,7 +33,7 @@ func TestAppCannotFetchBot(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -48,7 +51,7 @@ func TestAppStartsOkay(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -81,6 +84,122 @@ func TestAppStartsOkay(t *testing.T) {
 	wg.Wait()
 }
 
+//nolint:paralleltest // disabled
+func TestAppMultipleInstances(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana: []configPkg.GrafanaConfig{
+			{URL: "https://example.com", User: "admin", Password: "admin"},
+			{Name: "EU Grafana", URL: "https://eu.example.com", User: "admin", Password: "admin"},
+		},
+		Prometheus: []configPkg.PrometheusConfig{
+			{Name: "EU Prometheus", URL: "https://prometheus-eu.com", SilenceManager: "EU"},
+			{Name: "US Prometheus", URL: "https://prometheus-us.com", SilenceManager: "US"},
+		},
+		Alertmanager: []configPkg.AlertmanagerConfig{
+			{Name: "US", URL: "https://alertmanager-us.com"},
+			{Name: "EU", URL: "https://alertmanager-eu.com"},
+		},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	require.Len(t, app.AlertSourcesWithSilenceManager, 4)
+
+	require.Equal(t, "Grafana", app.AlertSourcesWithSilenceManager[0].AlertSource.Name())
+	require.Equal(t, "grafana_silences", app.AlertSourcesWithSilenceManager[0].SilenceManager.Prefixes().ListSilencesCommand)
+
+	require.Equal(t, "EU Grafana", app.AlertSourcesWithSilenceManager[1].AlertSource.Name())
+	require.Equal(t, "eu_grafana_silences", app.AlertSourcesWithSilenceManager[1].SilenceManager.Prefixes().ListSilencesCommand)
+
+	// Prometheus instances are paired with Alertmanagers by name, not by position.
+	require.Equal(t, "EU Prometheus", app.AlertSourcesWithSilenceManager[2].AlertSource.Name())
+	require.Equal(t, "EU", app.AlertSourcesWithSilenceManager[2].SilenceManager.Name())
+	require.Equal(t, "eu_silences", app.AlertSourcesWithSilenceManager[2].SilenceManager.Prefixes().ListSilencesCommand)
+
+	require.Equal(t, "US Prometheus", app.AlertSourcesWithSilenceManager[3].AlertSource.Name())
+	require.Equal(t, "US", app.AlertSourcesWithSilenceManager[3].SilenceManager.Name())
+
+	// Each silence manager is used once, even if it is paired with multiple alert sources.
+	silenceManagersNames := generic.Map(app.SilenceManagers, func(s silence_manager.SilenceManager) string {
+		return s.Name()
+	})
+	require.Equal(t, []string{"Grafana", "EU Grafana", "US", "EU"}, silenceManagersNames)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPrometheusWithoutAlertmanager(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:   "Etc/GMT",
+		Log:        configPkg.LogConfig{LogLevel: "info"},
+		Telegram:   configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:    []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+		Prometheus: []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	require.Len(t, app.AlertSourcesWithSilenceManager, 2)
+	require.True(t, app.AlertSourcesWithSilenceManager[1].AlertSource.Enabled())
+	require.False(t, app.AlertSourcesWithSilenceManager[1].SilenceManager.Enabled())
+
+	// The disabled Alertmanager is still registered, so its commands reply it is disabled.
+	require.Len(t, app.SilenceManagers, 2)
+	require.Len(t, app.GetSilenceManagers(), 1)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAlertmanagerAsAlertSource(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:   "Etc/GMT",
+		Log:        configPkg.LogConfig{LogLevel: "info"},
+		Telegram:   configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:    []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Prometheus: []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
+		Alertmanager: []configPkg.AlertmanagerConfig{
+			{URL: "https://alertmanager.com", Alerts: &configPkg.AlertmanagerAlertsConfig{}},
+		},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	require.Len(t, app.AlertSourcesWithSilenceManager, 3)
+
+	require.Equal(t, "Prometheus", app.AlertSourcesWithSilenceManager[1].AlertSource.Name())
+	require.Equal(t, "Alertmanager", app.AlertSourcesWithSilenceManager[1].SilenceManager.Name())
+
+	require.Equal(t, "Alertmanager", app.AlertSourcesWithSilenceManager[2].AlertSource.Name())
+	require.True(t, app.AlertSourcesWithSilenceManager[2].AlertSource.Enabled())
+	require.Equal(t, "Alertmanager", app.AlertSourcesWithSilenceManager[2].SilenceManager.Name())
+
+	silenceManagers := app.GetSilenceManagers()
+	require.Len(t, silenceManagers, 1)
+	require.Equal(t, "Alertmanager", silenceManagers[0].Name())
+}
+
 //nolint:paralleltest // disabled
 func TestAppBotSendMultilineFail(t *testing.T) {
 	httpmock.Activate()
@@ -90,7 +209,7 @@ func TestAppBotSendMultilineFail(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -131,7 +250,7 @@ func TestAppBotSendMultilineOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -159,3 +278,38 @@ func TestAppBotSendMultilineOk(t *testing.T) {
 	err := app.BotReply(ctx, strings.Repeat("a", 5000))
 	require.NoError(t, err)
 }
+
+//nolint:paralleltest // disabled
+func TestAppRulersAsAlertSources(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com"}},
+		Alertmanager: []configPkg.AlertmanagerConfig{
+			{Name: "Mimir Alertmanager", URL: "https://mimir.com/alertmanager"},
+		},
+		Rulers: []configPkg.RulerConfig{
+			{Name: "Loki", URL: "https://loki.com", PathPrefix: "/prometheus"},
+			{Name: "Mimir", URL: "https://mimir.com", PathPrefix: "/prometheus", SilenceManager: "Mimir Alertmanager"},
+		},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	require.Len(t, app.AlertSourcesWithSilenceManager, 4)
+
+	require.Equal(t, "Loki", app.AlertSourcesWithSilenceManager[2].AlertSource.Name())
+	require.False(t, app.AlertSourcesWithSilenceManager[2].SilenceManager.Enabled())
+
+	require.Equal(t, "Mimir", app.AlertSourcesWithSilenceManager[3].AlertSource.Name())
+	require.True(t, app.AlertSourcesWithSilenceManager[3].SilenceManager.Enabled())
+	require.Equal(t, "Mimir Alertmanager", app.AlertSourcesWithSilenceManager[3].SilenceManager.Name())
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"main/pkg/constants"
+	"main/pkg/silence_manager"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"main/pkg/utils/generic"
+	"strconv"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) RecordAuditEntry(
+	c tele.Context,
+	action string,
+	silenceManager silence_manager.SilenceManager,
+	silence types.Silence,
+	duration time.Duration,
+) {
+	entry := types.AuditEntry{
+		Time:           time.Now(),
+		Action:         action,
+		SilenceManager: silenceManager.Name(),
+		Matchers:       silence.Matchers,
+		SilenceID:      silence.ID,
+	}
+
+	if duration > 0 {
+		entry.Duration = duration.String()
+	}
+
+	if sender := c.Sender(); sender != nil {
+		entry.UserID = sender.ID
+		entry.Username = sender.Username
+	}
+
+	if chat := c.Chat(); chat != nil {
+		entry.ChatID = chat.ID
+	}
+
+	if err := a.AuditLog.Append(entry); err != nil {
+		a.Logger.Error().
+			Err(err).
+			Str("action", action).
+			Str("silence_id", silence.ID).
+			Msg("Error writing audit log entry")
+	}
+}
+
+func (a *App) HandleListAuditLog(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got audit log query")
+
+	return a.HandleListAuditLogWithPagination(c, 0, false)
+}
+
+func (a *App) HandleListAuditLogFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got audit log query via callback")
+
+	page, err := strconv.Atoi(callback.Data)
+	if err != nil {
+		return c.Reply("Failed to parse page number from callback!")
+	}
+
+	return a.HandleListAuditLogWithPagination(c, page, true)
+}
+
+func (a *App) HandleListAuditLogWithPagination(c tele.Context, page int, editPrevious bool) error {
+	if !a.AuditLog.Enabled() {
+		return c.Reply("Audit log is disabled.")
+	}
+
+	allEntries, err := a.AuditLog.GetEntries()
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error fetching audit log: %s\n", err))
+	}
+
+	entries, totalPages := generic.Paginate(allEntries, page, constants.AuditEntriesInOneMessage)
+
+	templateData := render.RenderStruct{
+		Grafana: a.Grafana,
+		Data: types.AuditLogStruct{
+			Entries:      entries,
+			Start:        page*constants.AuditEntriesInOneMessage + 1,
+			End:          page*constants.AuditEntriesInOneMessage + len(entries),
+			EntriesCount: len(allEntries),
+		},
+	}
+
+	menu := GenerateMenuWithPagination(
+		entries,
+		nil,
+		"",
+		nil,
+		constants.PaginatedAuditLogPrefix,
+		page,
+		totalPages,
+	)
+
+	if editPrevious {
+		return a.EditRender(c, "audit_log", templateData, menu)
+	}
+
+	return a.ReplyRender(c, "audit_log", templateData, menu)
+}
@@ -0,0 +1,225 @@
+package app
+
+import (
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/fs"
+	"main/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+)
+
+func getAuditTestConfig(auditLogPath string) *configPkg.Config {
+	return &configPkg.Config{
+		Timezone:     "Etc/GMT",
+		Log:          configPkg.LogConfig{LogLevel: "info"},
+		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com"}},
+		AuditLogPath: auditLogPath,
+	}
+}
+
+//nolint:paralleltest // disabled
+func TestAppAuditLogDisabled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Audit log is disabled."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(getAuditTestConfig(""), &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/audit",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleListAuditLog(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAuditLogOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasBytes(assets.GetBytesOrPanic("responses/audit-log-ok.html")),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(getAuditTestConfig("audit-log.jsonl"), &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/audit",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleListAuditLog(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAuditLogFromCallbackInvalidPage(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Failed to parse page number from callback!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(getAuditTestConfig("audit-log.jsonl"), &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\fpaginated_audit_log_",
+			Data:   "not-a-number",
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Text:   "/audit",
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.HandleListAuditLogFromCallback(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppAuditLogFromCallbackOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageText",
+		types.TelegramResponseHasBytes(assets.GetBytesOrPanic("responses/audit-log-ok.html")),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(getAuditTestConfig("audit-log.jsonl"), &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\fpaginated_audit_log_",
+			Data:   "0",
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Text:   "/audit",
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.HandleListAuditLogFromCallback(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRecordAuditEntry(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getAuditTestConfig(t.TempDir()+"/audit.jsonl"), &fs.OsFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{ID: 1, Username: "testuser"},
+			Text:   "/grafana_silence 48h host=test",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	silence := types.Silence{
+		ID:       "silence",
+		Matchers: types.SilenceMatchers{{Name: "host", Value: "test", IsEqual: true}},
+	}
+
+	silenceManager := app.AlertSourcesWithSilenceManager[0].SilenceManager
+	app.RecordAuditEntry(ctx, types.AuditActionCreateSilence, silenceManager, silence, 48*time.Hour)
+
+	entries, err := app.AuditLog.GetEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, types.AuditActionCreateSilence, entries[0].Action)
+	require.Equal(t, int64(1), entries[0].UserID)
+	require.Equal(t, "testuser", entries[0].Username)
+	require.Equal(t, int64(2), entries[0].ChatID)
+	require.Equal(t, "Grafana", entries[0].SilenceManager)
+	require.Equal(t, "48h0m0s", entries[0].Duration)
+	require.Equal(t, "silence", entries[0].SilenceID)
+	require.Len(t, entries[0].Matchers, 1)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRecordAuditEntryFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	filesystem := &fs.TestFS{WriteError: errors.New("custom error")}
+	app := NewApp(getAuditTestConfig("audit.jsonl"), filesystem, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{ID: 1, Username: "testuser"},
+			Text:   "/grafana_unsilence silence",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	silenceManager := app.AlertSourcesWithSilenceManager[0].SilenceManager
+	app.RecordAuditEntry(ctx, types.AuditActionDeleteSilence, silenceManager, types.Silence{ID: "silence"}, 0)
+}
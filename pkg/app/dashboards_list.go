@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/types/render"
 
@@ -13,7 +14,7 @@ func (a *App) HandleListDashboards(c tele.Context) error {
 		Str("text", c.Text()).
 		Msg("Got dashboards query")
 
-	dashboards, err := a.Grafana.GetAllDashboards()
+	dashboards, err := a.DashboardIndex.GetAllDashboards(context.Background())
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error querying dashboards: %s", err))
 	}
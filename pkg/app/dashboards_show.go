@@ -1,7 +1,9 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"main/pkg/constants"
 	"main/pkg/types"
 	"main/pkg/types/render"
 	"main/pkg/utils/generic"
@@ -23,17 +25,58 @@ func (a *App) HandleShowDashboard(c tele.Context) error {
 		return c.Reply("Usage: /dashboard <dashboard>")
 	}
 
-	dashboards, err := a.Grafana.GetAllDashboards()
+	dashboards, err := a.DashboardIndex.GetAllDashboards(context.Background())
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error querying for dashboards: %s", err))
 	}
 
-	dashboard, found := dashboards.FindDashboardByName(args[0])
+	results := dashboards.SearchDashboardsByName(args[0])
+	if results.IsAmbiguous() {
+		menu := GenerateChoiceMenu(
+			results.Top(constants.SearchCandidatesInOneMessage),
+			GetDashboardChoiceText,
+			constants.ShowDashboardPrefix,
+			func(dashboard types.GrafanaDashboardInfo, index int) string { return dashboard.UID },
+		)
+
+		return c.Reply(fmt.Sprintf("Found multiple dashboards matching \"%s\", choose one:", args[0]), menu)
+	}
+
+	dashboard, found := results.Best()
 	if !found {
 		return c.Reply("Could not find dashboard. See /dashboards for dashboards list.")
 	}
 
-	dashboardEnriched, err := a.Grafana.GetDashboard(dashboard.UID)
+	return a.ShowDashboard(c, *dashboard)
+}
+
+func (a *App) HandleShowDashboardFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got show dashboard callback")
+
+	dashboards, err := a.DashboardIndex.GetAllDashboards(context.Background())
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying for dashboards: %s", err))
+	}
+
+	dashboard, found := generic.Find(dashboards, func(dashboard types.GrafanaDashboardInfo) bool {
+		return dashboard.UID == callback.Data
+	})
+	if !found {
+		return c.Reply("Dashboard was not found!")
+	}
+
+	_ = a.ClearKeyboard(c)
+
+	return a.ShowDashboard(c, *dashboard)
+}
+
+func (a *App) ShowDashboard(c tele.Context, dashboard types.GrafanaDashboardInfo) error {
+	dashboardEnriched, err := a.DashboardIndex.GetDashboard(context.Background(), dashboard.UID)
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Could not get dashboard: %s", err))
 	}
@@ -41,14 +84,18 @@ func (a *App) HandleShowDashboard(c tele.Context) error {
 	return a.ReplyRender(c, "dashboards_show", render.RenderStruct{
 		Grafana: a.Grafana,
 		Data: types.DashboardStruct{
-			Dashboard: *dashboard,
-			Panels: generic.Map(dashboardEnriched.Dashboard.Panels, func(p types.GrafanaPanel) types.PanelStruct {
-				return types.PanelStruct{
-					DashboardURL: dashboard.URL,
-					PanelID:      p.ID,
-					Name:         p.Title,
-				}
-			}),
+			Dashboard: dashboard,
+			Panels:    dashboardEnriched.GetPanels(),
 		},
 	})
 }
+
+// GetDashboardChoiceText returns the dashboard title with its folder title, if any,
+// to tell the dashboards with the same title apart in choice menus.
+func GetDashboardChoiceText(dashboard types.GrafanaDashboardInfo) string {
+	if dashboard.FolderTitle == "" {
+		return dashboard.Title
+	}
+
+	return dashboard.FolderTitle + " / " + dashboard.Title
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"main/assets"
 	configPkg "main/pkg/config"
+	"main/pkg/constants"
 	"main/pkg/fs"
 	"main/pkg/types"
 	"testing"
@@ -22,7 +23,7 @@ func TestAppShowDashboardInvalidInvocation(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -62,7 +63,7 @@ func TestAppShowDashboardDashboardsListFail(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -107,7 +108,7 @@ func TestAppShowDashboardDashboardNotFound(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -152,7 +153,7 @@ func TestAppShowDashboardDashboardFetchError(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -202,7 +203,7 @@ func TestAppShowDashboardOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -242,3 +243,100 @@ func TestAppShowDashboardOk(t *testing.T) {
 	err := app.HandleShowDashboard(ctx)
 	require.NoError(t, err)
 }
+
+//nolint:paralleltest // disabled
+func TestAppShowDashboardAmbiguous(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+
+	button := func(text, uid string) []types.TelegramInlineKeyboard {
+		return []types.TelegramInlineKeyboard{{
+			Unique:       constants.ShowDashboardPrefix,
+			Text:         text,
+			CallbackData: "\f" + constants.ShowDashboardPrefix + "|" + uid,
+		}}
+	}
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasTextAndMarkup(
+			"Found multiple dashboards matching \"unifi poller\", choose one:",
+			types.TelegramInlineKeyboardResponse{InlineKeyboard: [][]types.TelegramInlineKeyboard{
+				button("UniFi-Poller: Client DPI - Prometheus", "unpoller-dpi"),
+				button("UniFi-Poller: Client Insights - Prometheus", "unpoller-clients"),
+				button("UniFi-Poller: UAP Insights - Prometheus", "unpoller-uap"),
+				{{
+					Unique:       constants.ClearKeyboardPrefix,
+					Text:         "❌Cancel",
+					CallbackData: "\f" + constants.ClearKeyboardPrefix,
+				}},
+			}},
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleShowDashboard(newTestContext(app, "/dashboard unifi poller"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppShowDashboardFromCallbackNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Dashboard was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleShowDashboardFromCallback(newTestCallbackContext(app, constants.ShowDashboardPrefix, "not-existing", "Found multiple dashboards matching \"alert\", choose one:"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppShowDashboardFromCallbackOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/alertmanager",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasBytes(assets.GetBytesOrPanic("responses/dashboard-show-ok.html")),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleShowDashboardFromCallback(newTestCallbackContext(app, constants.ShowDashboardPrefix, "alertmanager", "Found multiple dashboards matching \"alert\", choose one:"))
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup"])
+}
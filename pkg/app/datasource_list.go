@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/types/render"
 
@@ -13,7 +14,7 @@ func (a *App) HandleListDatasources(c tele.Context) error {
 		Str("text", c.Text()).
 		Msg("Got alerts query")
 
-	datasources, err := a.Grafana.GetDatasources()
+	datasources, err := a.Grafana.GetDatasources(context.Background())
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error querying datasources: %s", err))
 	}
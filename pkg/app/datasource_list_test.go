@@ -22,7 +22,7 @@ func TestAppDatasourceListFailedToFetch(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -67,7 +67,7 @@ func TestAppDatasourceListOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
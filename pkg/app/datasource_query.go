@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"main/pkg/utils"
+	"strings"
+	"unicode"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) HandleDatasourceQuery(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got datasource query")
+
+	opts, text := utils.ParseCommandOptions(c.Text(), "from", "to")
+	if text == "" {
+		return c.Reply("Usage: /ds_query [from=now-1h] [to=now] <datasource name> <query>")
+	}
+
+	datasources, err := a.Grafana.GetDatasources(context.Background())
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying datasources: %s", err))
+	}
+
+	datasource, query, found := FindDatasourceInQuery(datasources, text)
+	if !found {
+		return c.Reply("Could not find datasource!")
+	}
+
+	if query == "" {
+		return c.Reply("Usage: /ds_query [from=now-1h] [to=now] <datasource name> <query>")
+	}
+
+	from, ok := opts["from"]
+	if !ok {
+		from = "now-1h"
+	}
+
+	to, ok := opts["to"]
+	if !ok {
+		to = "now"
+	}
+
+	frames, err := a.Grafana.QueryDatasource(context.Background(), *datasource, query, from, to)
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying datasource: %s", err))
+	}
+
+	result := types.DatasourceQueryResultStruct{
+		Datasource: *datasource,
+		Query:      query,
+		Frames:     make([]types.DatasourceQueryFrame, 0, len(frames)),
+	}
+
+	for _, frame := range frames {
+		headers, rows := frame.ToTable(a.TemplateManager.Timezone)
+		hiddenRows := 0
+
+		if len(rows) > types.DatasourceQueryMaxRows {
+			hiddenRows = len(rows) - types.DatasourceQueryMaxRows
+			rows = rows[:types.DatasourceQueryMaxRows]
+		}
+
+		result.Frames = append(result.Frames, types.DatasourceQueryFrame{
+			Name:       frame.Schema.Name,
+			RowsCount:  frame.RowsCount(),
+			HiddenRows: hiddenRows,
+			Table:      utils.FormatTable(headers, rows),
+		})
+	}
+
+	return a.ReplyRender(c, "datasource_query", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data:    result,
+	})
+}
+
+// FindDatasourceInQuery finds the datasource which name or UID the text starts with,
+// so datasource names with spaces are supported, and returns the rest of the text.
+// If multiple datasources match, the one with the longest name is chosen.
+func FindDatasourceInQuery(
+	datasources []types.GrafanaDatasource,
+	text string,
+) (*types.GrafanaDatasource, string, bool) {
+	var found *types.GrafanaDatasource
+	matchedLength := 0
+
+	for index, datasource := range datasources {
+		for _, reference := range []string{datasource.Name, datasource.UID} {
+			if reference == "" || len(reference) <= matchedLength {
+				continue
+			}
+
+			// the datasource name can be followed by a space or a newline
+			if text == reference || (strings.HasPrefix(text, reference) &&
+				strings.IndexFunc(text[len(reference):], unicode.IsSpace) == 0) {
+				found = &datasources[index]
+				matchedLength = len(reference)
+			}
+		}
+	}
+
+	if found == nil {
+		return nil, "", false
+	}
+
+	return found, strings.TrimSpace(text[matchedLength:]), true
+}
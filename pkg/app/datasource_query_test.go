@@ -0,0 +1,238 @@
+package app
+
+import (
+	"errors"
+	"main/assets"
+	"main/pkg/types"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // disabled
+func TestAppDatasourceQueryInvalidInvocation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Usage: /ds_query [from=now-1h] [to=now] <datasource name> <query>"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleDatasourceQuery(newTestContext(app, "/ds_query from=now-2h"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppDatasourceQueryNoQuery(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-multiple.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Usage: /ds_query [from=now-1h] [to=now] <datasource name> <query>"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleDatasourceQuery(newTestContext(app, "/ds_query Loki"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppDatasourceQueryDatasourcesFetchError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error querying datasources: Get \"https://example.com/api/datasources\": custom error"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleDatasourceQuery(newTestContext(app, "/ds_query Loki {app=\"api\"}"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppDatasourceQueryDatasourceNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-multiple.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Could not find datasource!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleDatasourceQuery(newTestContext(app, "/ds_query Elasticsearch *"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppDatasourceQueryUnsupportedType(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-multiple.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error querying datasource: datasource type 'tempo' is not supported"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleDatasourceQuery(newTestContext(app, "/ds_query Tempo {}"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppDatasourceQueryError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-multiple.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/ds/query",
+		httpmock.BodyContainsString(`"rawSql":"select * from users"`),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-ds-query-error.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error querying datasource: db query error: pq: relation \"users\" does not exist"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleDatasourceQuery(newTestContext(app, "/ds_query Postgres Main select * from users"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppDatasourceQueryPrometheusOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-multiple.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/ds/query",
+		httpmock.BodyContainsString(`"expr":"up"`).
+			And(httpmock.BodyContainsString(`"from":"now-2h"`)).
+			And(httpmock.BodyContainsString(`"uid":"prometheus"`)),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-ds-query-prometheus.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"<strong>Datasource:</strong> <a href='https://example.com/datasources/edit/prometheus'>Prometheus</a>\n"+
+				"<strong>Query:</strong> <code>up</code>\n\n"+
+				"<strong>Result</strong> (2 rows):\n"+
+				"<code>Time                 __name__  instance        job         Value</code>\n"+
+				"<code>2024-01-01 12:00:00  up        localhost:9090  prometheus  1</code>\n"+
+				"<code>2024-01-01 12:00:00  up        localhost:9100  node        0</code>",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleDatasourceQuery(newTestContext(app, "/ds_query from=now-2h Prometheus up"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppDatasourceQueryLokiOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-multiple.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/ds/query",
+		httpmock.BodyContainsString(`"queryType":"range"`),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-ds-query-loki.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"<strong>Datasource:</strong> <a href='https://example.com/datasources/edit/loki'>Loki</a>\n"+
+				"<strong>Query:</strong> <code>{app=&#34;api&#34;}</code>\n\n"+
+				"<strong>{app=&#34;api&#34;}</strong> (2 rows):\n"+
+				"<code>labels   Time                 Line                       tsNs                 id</code>\n"+
+				"<code>app=api  2024-01-01 12:01:00  request failed at handler  1704110460000000000  1704110460000000000_1</code>\n"+
+				"<code>app=api  2024-01-01 12:00:00  request ok                 1704110400000000000  1704110400000000000_2</code>",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleDatasourceQuery(newTestContext(app, "/ds_query loki {app=\"api\"}"))
+	require.NoError(t, err)
+}
+
+func TestFindDatasourceInQuery(t *testing.T) {
+	t.Parallel()
+
+	datasources := []types.GrafanaDatasource{
+		{Name: "Postgres", UID: "postgres"},
+		{Name: "Postgres Main", UID: "postgres-main"},
+	}
+
+	datasource, query, found := FindDatasourceInQuery(datasources, "Postgres Main select 1")
+	require.True(t, found)
+	require.Equal(t, "postgres-main", datasource.UID)
+	require.Equal(t, "select 1", query)
+
+	datasource, query, found = FindDatasourceInQuery(datasources, "Postgres\nselect *\n  from users")
+	require.True(t, found)
+	require.Equal(t, "postgres", datasource.UID)
+	require.Equal(t, "select *\n  from users", query)
+
+	_, _, found = FindDatasourceInQuery(datasources, "PostgresMain select 1")
+	require.False(t, found)
+}
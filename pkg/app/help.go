@@ -1,6 +1,7 @@
 package app
 
 import (
+	"main/pkg/types"
 	"main/pkg/types/render"
 
 	tele "gopkg.in/telebot.v3"
@@ -12,8 +13,24 @@ func (a *App) HandleHelp(c tele.Context) error {
 		Str("text", c.Text()).
 		Msg("Got help query")
 
+	silenceManagers := []types.SilenceManagerCommands{}
+
+	for _, silenceManager := range a.GetSilenceManagers() {
+		prefixes := silenceManager.Prefixes()
+		silenceManagers = append(silenceManagers, types.SilenceManagerCommands{
+			Name:                silenceManager.Name(),
+			ListSilencesCommand: prefixes.ListSilencesCommand,
+			SilenceCommand:      prefixes.SilenceCommand,
+			UnsilenceCommand:    prefixes.UnsilenceCommand,
+			EditSilenceCommand:  prefixes.EditSilenceCommand,
+		})
+	}
+
 	return a.ReplyRender(c, "help", render.RenderStruct{
 		Grafana: a.Grafana,
-		Data:    a.Version,
+		Data: types.HelpStruct{
+			Version:         a.Version,
+			SilenceManagers: silenceManagers,
+		},
 	})
 }
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"main/pkg/clients"
+	configPkg "main/pkg/config"
+	"main/pkg/constants"
+	"main/pkg/http"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"main/pkg/utils"
+	"main/pkg/utils/generic"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	tele "gopkg.in/telebot.v3"
+)
+
+const (
+	DefaultLogsSince = 15 * time.Minute
+	DefaultLogsLimit = 50
+	MaxLogsLimit     = 1000
+)
+
+func (a *App) HandleLogs(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got logs query")
+
+	opts, query := utils.ParseCommandOptions(c.Text(), "datasource", "since", "limit")
+	if query == "" {
+		return c.Reply("Usage: /logs [datasource=<name>] [since=15m] [limit=50] <LogQL query>")
+	}
+
+	since := DefaultLogsSince
+	if sinceRaw, ok := opts["since"]; ok {
+		parsedSince, err := time.ParseDuration(sinceRaw)
+		if err != nil || parsedSince <= 0 {
+			return c.Reply(fmt.Sprintf("Invalid since: %s", sinceRaw))
+		}
+
+		since = parsedSince
+	}
+
+	limit := DefaultLogsLimit
+	if limitRaw, ok := opts["limit"]; ok {
+		parsedLimit, err := strconv.Atoi(limitRaw)
+		if err != nil || parsedLimit <= 0 || parsedLimit > MaxLogsLimit {
+			return c.Reply(fmt.Sprintf("Invalid limit: %s, should be from 1 to %d", limitRaw, MaxLogsLimit))
+		}
+
+		limit = parsedLimit
+	}
+
+	now := time.Now()
+
+	return a.ReplyLogs(c, types.LogsQuery{
+		Datasource: opts["datasource"],
+		Query:      query,
+		Limit:      limit,
+		Start:      now.Add(-since),
+	}, now)
+}
+
+func (a *App) HandleLogsMoreFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got logs more callback")
+
+	key, cursorRaw, _ := strings.Cut(callback.Data, "|")
+
+	logsQuery := types.LogsQuery{}
+	if !a.Cache.GetObject(key, &logsQuery) {
+		return c.Reply("Logs query was not found!")
+	}
+
+	cursor, err := strconv.ParseInt(cursorRaw, 10, 64)
+	if err != nil {
+		return c.Reply("Invalid logs cursor!")
+	}
+
+	a.RemoveKeyboardItemByCallback(c, callback)
+
+	return a.ReplyLogs(c, logsQuery, time.Unix(0, cursor))
+}
+
+// ReplyLogs queries the logs up to the end time and sends them, either as a message,
+// or as a text file if they do not fit into one. If there might be more logs,
+// a button is added to fetch the previous ones, using the oldest line time
+// as the end of the next query, as Loki does not include the range end.
+func (a *App) ReplyLogs(c tele.Context, logsQuery types.LogsQuery, end time.Time) error {
+	loki, err := a.GetLokiQuerier(logsQuery.Datasource)
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error finding datasource: %s", err))
+	}
+
+	lines, err := loki.QueryRange(context.Background(), logsQuery.Query, logsQuery.Start, end, logsQuery.Limit)
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying Loki: %s", err))
+	}
+
+	logs := types.LogsStruct{
+		Query: logsQuery.Query,
+		Lines: make([]types.LogLineStruct, len(lines)),
+	}
+
+	for index, line := range lines {
+		logs.Lines[index] = types.LogLineStruct{
+			Time: line.Time.In(a.TemplateManager.Timezone).Format(time.DateTime),
+			Line: line.Line,
+		}
+	}
+
+	opts := []interface{}{}
+
+	if len(lines) >= logsQuery.Limit {
+		key := a.Cache.SetObject(constants.LogsQueryCachePrefix+logsQuery.GetHash(), logsQuery)
+		cursor := strconv.FormatInt(lines[0].Time.UnixNano(), 10)
+
+		menu := &tele.ReplyMarkup{ResizeKeyboard: true}
+		menu.Inline(menu.Row(menu.Data("⬅️ More", constants.LogsMorePrefix, key, cursor)))
+		opts = append(opts, menu)
+	}
+
+	template, err := a.TemplateManager.Render("logs", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data:    logs,
+	})
+	if err != nil {
+		a.Logger.Error().Str("template", "logs").Err(err).Msg("Error rendering template")
+		return c.Reply(fmt.Sprintf("Error rendering template: %s", err))
+	}
+
+	if len(template) <= MaxMessageSize {
+		return a.BotReply(c, template, opts...)
+	}
+
+	caption := fmt.Sprintf(
+		"<strong>Logs:</strong> <code>%s</code>\n%d lines",
+		html.EscapeString(logsQuery.Query),
+		len(lines),
+	)
+	if len(caption) > MaxCaptionSize {
+		caption = fmt.Sprintf("%d lines", len(lines))
+	}
+
+	return c.Reply(&tele.Document{
+		File:     tele.FromReader(strings.NewReader(logs.ToText())),
+		FileName: "logs.txt",
+		Caption:  caption,
+	}, append(opts, tele.ModeHTML)...)
+}
+
+// GetLokiQuerier returns a client to query either a Grafana Loki datasource
+// with the given name via the datasource proxy, or, if it's not passed,
+// Loki instance from config, or the first Grafana Loki datasource.
+func (a *App) GetLokiQuerier(datasourceName string) (*clients.Loki, error) {
+	if datasourceName == "" && a.Loki != nil {
+		return a.Loki, nil
+	}
+
+	datasources, err := a.Grafana.GetDatasources(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	datasource, found := generic.Find(datasources, func(ds types.GrafanaDatasource) bool {
+		if datasourceName == "" {
+			return ds.Type == "loki"
+		}
+
+		return ds.UID == datasourceName || ds.Name == datasourceName
+	})
+
+	if !found && datasourceName == "" {
+		return nil, errors.New("no Loki datasources found")
+	} else if !found {
+		return nil, fmt.Errorf("datasource '%s' is not found", datasourceName)
+	}
+
+	if datasource.Type != "loki" {
+		return nil, fmt.Errorf("datasource '%s' is not a Loki datasource", datasource.Name)
+	}
+
+	return a.Grafana.GetLokiDatasource(datasource.UID), nil
+}
+
+// initLokiQuerier returns a client for the Loki instance from config, created once
+// so its connections are reused between queries, or nil if it is not configured.
+func initLokiQuerier(config *configPkg.Config, logger *zerolog.Logger) *clients.Loki {
+	if config.Loki == nil {
+		return nil
+	}
+
+	lokiConfig := config.Loki
+
+	var auth *http.Auth
+	if lokiConfig.User != "" || lokiConfig.Password != "" || lokiConfig.Token != "" || lokiConfig.TenantID != "" {
+		auth = &http.Auth{
+			Username: lokiConfig.User,
+			Password: lokiConfig.Password,
+			Token:    lokiConfig.Token,
+			TenantID: lokiConfig.TenantID,
+		}
+	}
+
+	return clients.InitLoki(lokiConfig.URL, auth, lokiConfig.HTTPConfig, logger)
+}
@@ -0,0 +1,329 @@
+package app
+
+import (
+	"encoding/json"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func logsTestQueryHas(params map[string]string) httpmock.Matcher {
+	return httpmock.NewMatcher("QueryHas", func(req *http.Request) bool {
+		for key, value := range params {
+			if req.URL.Query().Get(key) != value {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsInvalidInvocation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Usage: /logs [datasource=<name>] [since=15m] [limit=50] <LogQL query>"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogs(newTestContext(app, "/logs since=1h"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsInvalidSince(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid since: 1y"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogs(newTestContext(app, "/logs since=1y {app=\"api\"}"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsInvalidLimit(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid limit: 5000, should be from 1 to 1000"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogs(newTestContext(app, "/logs limit=5000 {app=\"api\"}"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsNoLokiDatasource(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error finding datasource: no Loki datasources found"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogs(newTestContext(app, "/logs {app=\"api\"}"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsNotLokiDatasource(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-multiple.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error finding datasource: datasource 'Prometheus' is not a Loki datasource"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogs(newTestContext(app, "/logs datasource=Prometheus {app=\"api\"}"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsMetricQuery(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Loki = &configPkg.LokiConfig{URL: "https://loki.com"}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://loki.com/loki/api/v1/query_range",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("loki-query-matrix.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error querying Loki: expected a log query, got matrix result"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogs(newTestContext(app, "/logs count_over_time({app=\"api\"}[1m])"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsViaLokiOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Loki = &configPkg.LokiConfig{URL: "https://loki.com", TenantID: "tenant"}
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"GET",
+		"https://loki.com/loki/api/v1/query_range",
+		httpmock.HeaderIs("X-Scope-OrgID", "tenant"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("loki-query-streams.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"<strong>Logs:</strong> <code>{app=&#34;api&#34;}</code>\n"+
+				"<code>2024-01-01 12:00:00</code> starting\n"+
+				"<code>2024-01-01 12:01:00</code> request failed: &lt;timeout&gt;\n"+
+				"<code>2024-01-01 12:02:00</code> request ok",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogs(newTestContext(app, "/logs {app=\"api\"}"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsViaGrafanaWithMoreButton(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-multiple.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"GET",
+		"https://example.com/api/datasources/proxy/uid/loki/loki/api/v1/query_range",
+		logsTestQueryHas(map[string]string{"limit": "3", "direction": "backward"}),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("loki-query-streams.json")))
+
+	logsQuery := types.LogsQuery{}
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewMatcher("HasMoreButton", func(req *http.Request) bool {
+			response := types.TelegramResponse{}
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&response))
+
+			keyboard := types.TelegramInlineKeyboardResponse{}
+			require.NoError(t, json.Unmarshal([]byte(response.ReplyMarkup), &keyboard))
+			require.Len(t, keyboard.InlineKeyboard, 1)
+			require.Len(t, keyboard.InlineKeyboard[0], 1)
+
+			button := keyboard.InlineKeyboard[0][0]
+			require.Equal(t, "⬅️ More", button.Text)
+
+			data := strings.Split(button.CallbackData, "|")
+			require.Len(t, data, 3)
+			require.Equal(t, "\f"+constants.LogsMorePrefix, data[0])
+			require.Equal(t, "1704110400000000000", data[2])
+			require.True(t, app.Cache.GetObject(data[1], &logsQuery))
+			return true
+		}),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogs(newTestContext(app, "/logs limit=3 since=1h {app=\"api\"}"))
+	require.NoError(t, err)
+	require.Equal(t, "{app=\"api\"}", logsQuery.Query)
+	require.Equal(t, 3, logsQuery.Limit)
+	require.WithinDuration(t, time.Now().Add(-time.Hour), logsQuery.Start, time.Minute)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsTooLong(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Loki = &configPkg.LokiConfig{URL: "https://loki.com"}
+	})
+
+	values := make([]string, 100)
+	for index := range values {
+		values[index] = `["1704110400000000000", "` + strings.Repeat("x", 100) + `"]`
+	}
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://loki.com/loki/api/v1/query_range",
+		httpmock.NewStringResponder(200, `{"status":"success","data":{"resultType":"streams","result":[`+
+			`{"stream":{"app":"api"},"values":[`+strings.Join(values, ",")+`]}]}}`))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendDocument",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogs(newTestContext(app, "/logs limit=200 {app=\"api\"}"))
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendDocument"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsMoreNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Logs query was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogsMoreFromCallback(newTestCallbackContext(app, constants.LogsMorePrefix, "logs_query_xxx|1704110400000000000", "Logs"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsMoreInvalidCursor(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+	key := app.Cache.SetObject(constants.LogsQueryCachePrefix+"xxx", types.LogsQuery{Query: "{app=\"api\"}", Limit: 3})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid logs cursor!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogsMoreFromCallback(newTestCallbackContext(app, constants.LogsMorePrefix, key+"|abc", "Logs"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppLogsMoreOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Loki = &configPkg.LokiConfig{URL: "https://loki.com"}
+	})
+	key := app.Cache.SetObject(constants.LogsQueryCachePrefix+"xxx", types.LogsQuery{
+		Query: "{app=\"api\"}",
+		Limit: 10,
+		Start: time.Unix(1704106800, 0),
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"GET",
+		"https://loki.com/loki/api/v1/query_range",
+		logsTestQueryHas(map[string]string{"start": "1704106800000000000", "end": "1704110600000000000"}),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("loki-query-streams.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleLogsMoreFromCallback(newTestCallbackContext(app, constants.LogsMorePrefix, key+"|1704110600000000000", "Logs"))
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup"])
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+}
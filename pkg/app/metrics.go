@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"main/pkg/metrics"
+	"main/pkg/types"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	tele "gopkg.in/telebot.v3"
+)
+
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+func (a *App) StartMetricsServer() {
+	if a.MetricsServer == nil {
+		a.Logger.Debug().Msg("Metrics are not configured, not starting metrics server")
+		return
+	}
+
+	a.Logger.Info().
+		Str("address", a.MetricsServer.Addr).
+		Msg("Metrics server listening")
+
+	if err := a.MetricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		a.Logger.Panic().Err(err).Msg("Could not start metrics server")
+	}
+}
+
+func (a *App) StopMetricsServer() {
+	if a.MetricsServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := a.MetricsServer.Shutdown(ctx); err != nil {
+		a.Logger.Error().Err(err).Msg("Error stopping metrics server")
+	}
+}
+
+// RecordMetrics counts the commands and callbacks handled, by the endpoint they are
+// registered with, so users cannot blow up the metrics cardinality with random input.
+func (a *App) RecordMetrics(endpoint string) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			if callback, isCallback := strings.CutPrefix(endpoint, "\f"); isCallback {
+				metrics.CallbacksTotal.WithLabelValues(callback).Inc()
+			} else {
+				metrics.CommandsTotal.WithLabelValues(strings.TrimPrefix(endpoint, "/")).Inc()
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func (a *App) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics.CacheSize.Set(float64(a.Cache.Length()))
+	promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
+func (a *App) HandleHealthz(w http.ResponseWriter, r *http.Request) {
+	a.WriteHealthStatus(w, a.RunHealthChecks(r.Context(), []HealthCheck{a.GetTelegramHealthCheck()}))
+}
+
+func (a *App) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	checks := []HealthCheck{a.GetTelegramHealthCheck()}
+
+	for _, alertSourceWithSilenceManager := range a.AlertSourcesWithSilenceManager {
+		alertSource := alertSourceWithSilenceManager.AlertSource
+		if !alertSource.Enabled() {
+			continue
+		}
+
+		checks = append(checks, HealthCheck{
+			Name: "alert_source:" + alertSource.Name(),
+			Check: func(ctx context.Context) error {
+				_, err := alertSource.GetAlertingRules(ctx)
+				return err
+			},
+		})
+	}
+
+	for _, silenceManager := range a.GetSilenceManagers() {
+		checks = append(checks, HealthCheck{
+			Name: "silence_manager:" + silenceManager.Name(),
+			Check: func(ctx context.Context) error {
+				_, err := silenceManager.GetSilences(ctx)
+				return err
+			},
+		})
+	}
+
+	a.WriteHealthStatus(w, a.RunHealthChecks(r.Context(), checks))
+}
+
+func (a *App) GetTelegramHealthCheck() HealthCheck {
+	return HealthCheck{
+		Name: "telegram",
+		Check: func(ctx context.Context) error {
+			_, err := a.Bot.Raw("getMe", nil)
+			return err
+		},
+	}
+}
+
+func (a *App) RunHealthChecks(ctx context.Context, checks []HealthCheck) types.HealthStatus {
+	results := make([]types.HealthCheckResult, len(checks))
+
+	var wg sync.WaitGroup
+
+	for index, check := range checks {
+		wg.Add(1)
+		go func(index int, check HealthCheck) {
+			defer wg.Done()
+
+			result := types.HealthCheckResult{Name: check.Name, OK: true}
+			if err := check.Check(ctx); err != nil {
+				a.Logger.Warn().Err(err).Str("check", check.Name).Msg("Health check failed")
+				result.OK = false
+				result.Error = err.Error()
+			}
+
+			results[index] = result
+		}(index, check)
+	}
+
+	wg.Wait()
+
+	status := types.HealthStatus{OK: true, Checks: results}
+	for _, result := range results {
+		if !result.OK {
+			status.OK = false
+		}
+	}
+
+	return status
+}
+
+func (a *App) WriteHealthStatus(w http.ResponseWriter, status types.HealthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !status.OK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		a.Logger.Error().Err(err).Msg("Error writing health status")
+	}
+}
@@ -0,0 +1,224 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/fs"
+	"main/pkg/metrics"
+	"main/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+)
+
+func getMetricsTestConfig() *configPkg.Config {
+	return &configPkg.Config{
+		Timezone:     "Etc/GMT",
+		Log:          configPkg.LogConfig{LogLevel: "info"},
+		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(true), Silences: null.BoolFrom(true)}},
+		Alertmanager: nil,
+		Prometheus:   nil,
+		Metrics:      &configPkg.MetricsConfig{ListenAddress: ":9580"},
+	}
+}
+
+//nolint:paralleltest // disabled
+func TestAppMetricsOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getMetricsTestConfig(), &fs.TestFS{}, "1.2.3")
+	require.NotNil(t, app.MetricsServer)
+
+	app.Cache.Set("key", "value")
+
+	recorder := httptest.NewRecorder()
+	app.HandleMetrics(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "grafana_interacter_cache_size 1")
+}
+
+//nolint:paralleltest // disabled
+func TestAppRecordMetrics(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getMetricsTestConfig(), &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/help",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	handler := func(c tele.Context) error { return nil }
+
+	commandsBefore := testutil.ToFloat64(metrics.CommandsTotal.WithLabelValues("metrics_test"))
+	callbacksBefore := testutil.ToFloat64(metrics.CallbacksTotal.WithLabelValues("metrics_test_"))
+
+	require.NoError(t, app.RecordMetrics("/metrics_test")(handler)(ctx))
+	require.NoError(t, app.RecordMetrics("\fmetrics_test_")(handler)(ctx))
+
+	require.InDelta(t, commandsBefore+1, testutil.ToFloat64(metrics.CommandsTotal.WithLabelValues("metrics_test")), 0.001)
+	require.InDelta(t, callbacksBefore+1, testutil.ToFloat64(metrics.CallbacksTotal.WithLabelValues("metrics_test_")), 0.001)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRecordMetricsAccessDenied(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("You are not allowed to do this: it requires the admin role, and your role is viewer."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	config := getMetricsTestConfig()
+	config.Telegram.Roles.Viewers.Users = []int64{3}
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	app.Handle("/metrics_denied_test", func(c tele.Context) error { return nil }, types.RoleAdmin)
+
+	commandsBefore := testutil.ToFloat64(metrics.CommandsTotal.WithLabelValues("metrics_denied_test"))
+
+	app.Bot.ProcessUpdate(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{ID: 3, Username: "testuser"},
+			Text:   "/metrics_denied_test",
+			Chat:   &tele.Chat{ID: 3},
+		},
+	})
+
+	// Handlers run in a goroutine, so waiting for the access denied reply.
+	require.Eventually(t, func() bool {
+		return httpmock.GetTotalCallCount() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	require.InDelta(t, commandsBefore, testutil.ToFloat64(metrics.CommandsTotal.WithLabelValues("metrics_denied_test")), 0.001)
+}
+
+//nolint:paralleltest // disabled
+func TestAppHealthzOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getMetricsTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	recorder := httptest.NewRecorder()
+	app.HandleHealthz(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var status types.HealthStatus
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	require.True(t, status.OK)
+	require.Equal(t, []types.HealthCheckResult{{Name: "telegram", OK: true}}, status.Checks)
+}
+
+//nolint:paralleltest // disabled
+func TestAppReadyzOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silences-ok.json")))
+
+	app := NewApp(getMetricsTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	recorder := httptest.NewRecorder()
+	app.HandleReadyz(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var status types.HealthStatus
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	require.True(t, status.OK)
+	require.Equal(t, []types.HealthCheckResult{
+		{Name: "telegram", OK: true},
+		{Name: "alert_source:Grafana", OK: true},
+		{Name: "silence_manager:Grafana", OK: true},
+	}, status.Checks)
+}
+
+//nolint:paralleltest // disabled
+func TestAppReadyzFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silences-ok.json")))
+
+	app := NewApp(getMetricsTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	recorder := httptest.NewRecorder()
+	app.HandleReadyz(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	var status types.HealthStatus
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	require.False(t, status.OK)
+	require.False(t, status.Checks[1].OK)
+	require.Contains(t, status.Checks[1].Error, "custom error")
+	require.True(t, status.Checks[2].OK)
+}
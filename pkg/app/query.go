@@ -0,0 +1,202 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"main/pkg/clients"
+	configPkg "main/pkg/config"
+	"main/pkg/http"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"main/pkg/utils"
+	"main/pkg/utils/chart"
+	"time"
+
+	"github.com/rs/zerolog"
+	tele "gopkg.in/telebot.v3"
+)
+
+const (
+	DefaultQueryRange = time.Hour
+	// Prometheus refuses to return more than 11000 points per series,
+	// so the default step is calculated to have a reasonable amount of them.
+	DefaultQueryRangePoints = 250
+)
+
+func (a *App) HandleQuery(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got query")
+
+	opts, query := utils.ParseCommandOptions(c.Text(), "datasource")
+	if query == "" {
+		return c.Reply("Usage: /query [datasource=<name>] <PromQL query>")
+	}
+
+	prometheus, err := a.GetPrometheusQuerier(opts["datasource"])
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error finding datasource: %s", err))
+	}
+
+	data, err := prometheus.Query(context.Background(), query, time.Now())
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying Prometheus: %s", err))
+	}
+
+	series, err := data.GetSeries()
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error parsing query result: %s", err))
+	}
+
+	headers, rows := types.PrometheusSeriesToTable(series)
+
+	return a.ReplyRender(c, "query_result", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data: types.QueryResultStruct{
+			Query:       query,
+			ResultType:  data.ResultType,
+			SeriesCount: len(series),
+			Table:       utils.FormatTable(headers, rows),
+		},
+	})
+}
+
+func (a *App) HandleQueryRange(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got query range")
+
+	opts, query := utils.ParseCommandOptions(c.Text(), "datasource", "range", "step")
+	if query == "" {
+		return c.Reply("Usage: /query_range [datasource=<name>] [range=1h] [step=1m] <PromQL query>")
+	}
+
+	queryRange := DefaultQueryRange
+	if rangeRaw, ok := opts["range"]; ok {
+		parsedRange, err := time.ParseDuration(rangeRaw)
+		if err != nil || parsedRange <= 0 {
+			return c.Reply(fmt.Sprintf("Invalid range: %s", rangeRaw))
+		}
+
+		queryRange = parsedRange
+	}
+
+	step := max((queryRange / DefaultQueryRangePoints).Round(time.Second), time.Second)
+	if stepRaw, ok := opts["step"]; ok {
+		parsedStep, err := time.ParseDuration(stepRaw)
+		if err != nil || parsedStep <= 0 {
+			return c.Reply(fmt.Sprintf("Invalid step: %s", stepRaw))
+		}
+
+		step = parsedStep
+	}
+
+	prometheus, err := a.GetPrometheusQuerier(opts["datasource"])
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error finding datasource: %s", err))
+	}
+
+	end := time.Now()
+
+	data, err := prometheus.QueryRange(context.Background(), query, end.Add(-queryRange), end, step)
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying Prometheus: %s", err))
+	}
+
+	series, err := data.GetSeries()
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error parsing query result: %s", err))
+	}
+
+	image, err := chart.Render(PrometheusSeriesToChartSeries(series), chart.Options{
+		Width:    ChartWidth,
+		Height:   ChartHeight,
+		Timezone: a.TemplateManager.Timezone,
+	})
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error drawing chart: %s", err))
+	}
+
+	caption := fmt.Sprintf(
+		"<strong>Query:</strong> <code>%s</code>\n<strong>Range:</strong> last %s, step %s",
+		html.EscapeString(query),
+		utils.FormatDuration(queryRange),
+		utils.FormatDuration(step),
+	)
+	if len(caption) > MaxCaptionSize {
+		caption = ""
+	}
+
+	return c.Reply(&tele.Photo{
+		File:    tele.FromReader(bytes.NewReader(image)),
+		Caption: caption,
+	}, tele.ModeHTML)
+}
+
+// GetPrometheusQuerier returns a client to query either a Grafana datasource
+// with the given name via the datasource proxy, or, if it's not passed,
+// the first Prometheus instance from config, or the default Grafana datasource.
+func (a *App) GetPrometheusQuerier(datasourceName string) (*clients.Prometheus, error) {
+	if datasourceName == "" && a.Prometheus != nil {
+		return a.Prometheus, nil
+	}
+
+	datasource, err := a.Grafana.FindDatasource(context.Background(), datasourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if datasource.Type != "prometheus" {
+		return nil, fmt.Errorf("datasource '%s' is not a Prometheus datasource", datasource.Name)
+	}
+
+	return a.Grafana.GetPrometheusDatasource(datasource.UID), nil
+}
+
+// initPrometheusQuerier returns a client for the first Prometheus instance from config,
+// created once so its connections are reused between queries, or nil if there are none.
+func initPrometheusQuerier(config *configPkg.Config, logger *zerolog.Logger) *clients.Prometheus {
+	if len(config.Prometheus) == 0 {
+		return nil
+	}
+
+	prometheusConfig := config.Prometheus[0]
+
+	var auth *http.Auth
+	if prometheusConfig.User != "" && prometheusConfig.Password != "" {
+		auth = &http.Auth{Username: prometheusConfig.User, Password: prometheusConfig.Password}
+	}
+
+	return clients.InitPrometheus(
+		prometheusConfig.GetName(),
+		prometheusConfig.URL,
+		auth,
+		prometheusConfig.HTTPConfig,
+		logger,
+	)
+}
+
+func PrometheusSeriesToChartSeries(series []types.PrometheusSeries) []chart.Series {
+	chartSeries := make([]chart.Series, len(series))
+
+	for index, s := range series {
+		points := make([]chart.Point, 0, len(s.Values))
+
+		for _, sample := range s.Values {
+			value, err := sample.FloatValue()
+			if err != nil {
+				continue
+			}
+
+			points = append(points, chart.Point{Time: sample.Time, Value: value})
+		}
+
+		chartSeries[index] = chart.Series{Name: s.GetName(), Points: points}
+	}
+
+	return chartSeries
+}
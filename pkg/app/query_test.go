@@ -0,0 +1,215 @@
+package app
+
+import (
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/types"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // disabled
+func TestAppQueryInvalidInvocation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Usage: /query [datasource=<name>] <PromQL query>"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleQuery(newTestContext(app, "/query datasource=Prometheus"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppQueryDatasourceNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error finding datasource: datasource 'Loki' is not found"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleQuery(newTestContext(app, "/query datasource=Loki up"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppQueryError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Prometheus = []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/query",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-query-error.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error querying Prometheus: bad_data: invalid parameter \"query\": 1:3: parse error: unexpected end of input"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleQuery(newTestContext(app, "/query up{"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppQueryViaPrometheusOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Prometheus = []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/query",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-query-vector.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"<strong>Query:</strong> <code>up{job=~&#34;.&#43;&#34;}</code>\n"+
+				"<strong>Result</strong> (vector, 2 series):\n"+
+				"<code>__name__  instance        job         value</code>\n"+
+				"<code>up        localhost:9090  prometheus  1</code>\n"+
+				"<code>up        localhost:9100  node        0</code>",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleQuery(newTestContext(app, "/query up{job=~\".+\"}"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppQueryViaGrafanaOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Prometheus = []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources/proxy/uid/prometheus/api/v1/query",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-query-vector.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleQuery(newTestContext(app, "/query datasource=Prometheus up"))
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["GET https://example.com/api/datasources/proxy/uid/prometheus/api/v1/query"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppQueryRangeInvalidRange(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid range: 1y"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleQueryRange(newTestContext(app, "/query_range range=1y up"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppQueryRangeInvalidStep(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid step: -1m"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleQueryRange(newTestContext(app, "/query_range step=-1m up"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppQueryRangeNoData(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Prometheus = []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/query_range",
+		httpmock.NewStringResponder(200, `{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error drawing chart: no data points to draw"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleQueryRange(newTestContext(app, "/query_range up"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppQueryRangeOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Prometheus = []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/query_range",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-query-range.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendPhoto",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleQueryRange(newTestContext(app, "/query_range range=6h step=1m up"))
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendPhoto"])
+}
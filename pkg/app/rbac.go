@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+	"main/pkg/types"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog"
+	tele "gopkg.in/telebot.v3"
+)
+
+// GetRole returns the highest role the user has, either assigned to the user directly,
+// or to the chat the user is writing in. If no roles are configured, everyone is an admin.
+func (a *App) GetRole(userID, chatID int64) types.Role {
+	telegramConfig := a.Config.Telegram
+	if telegramConfig.IsEmpty() {
+		return types.RoleAdmin
+	}
+
+	switch {
+	case slices.Contains(telegramConfig.Admins, userID),
+		telegramConfig.Roles.Admins.Contains(userID, chatID):
+		return types.RoleAdmin
+	case telegramConfig.Roles.Silencers.Contains(userID, chatID):
+		return types.RoleSilencer
+	case telegramConfig.Roles.Viewers.Contains(userID, chatID):
+		return types.RoleViewer
+	default:
+		return types.RoleNone
+	}
+}
+
+func (a *App) GetRoleFromContext(c tele.Context) types.Role {
+	var userID, chatID int64
+
+	if sender := c.Sender(); sender != nil {
+		userID = sender.ID
+	}
+
+	if chat := c.Chat(); chat != nil {
+		chatID = chat.ID
+	}
+
+	return a.GetRole(userID, chatID)
+}
+
+func (a *App) RequireRole(role types.Role) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			userRole := a.GetRoleFromContext(c)
+			if userRole >= role {
+				return next(c)
+			}
+
+			a.LogAccessDenied(c, userRole).
+				Str("required_role", role.String()).
+				Msg("Access denied")
+
+			return c.Reply(fmt.Sprintf(
+				"You are not allowed to do this: it requires the %s role, and your role is %s.",
+				role,
+				userRole,
+			))
+		}
+	}
+}
+
+// IsSilenceDurationAllowed checks whether the user can create a silence of this duration,
+// as only admins can create silences longer than the configured max duration.
+func (a *App) IsSilenceDurationAllowed(c tele.Context, duration time.Duration) bool {
+	if !a.IsSilenceDurationLimited(duration) {
+		return true
+	}
+
+	return a.GetRoleFromContext(c) >= types.RoleAdmin
+}
+
+// IsSilenceDurationLimited returns true if the silence of this duration
+// is longer than the configured max duration, so only admins can create it.
+func (a *App) IsSilenceDurationLimited(duration time.Duration) bool {
+	maxDuration := a.Config.Telegram.Roles.MaxSilenceDuration
+	return maxDuration != 0 && duration > maxDuration
+}
+
+func (a *App) LogAccessDenied(c tele.Context, role types.Role) *zerolog.Event {
+	event := a.Logger.Warn().Str("role", role.String())
+
+	if sender := c.Sender(); sender != nil {
+		event = event.Int64("sender_id", sender.ID).Str("sender", sender.Username)
+	}
+
+	if chat := c.Chat(); chat != nil {
+		event = event.Int64("chat_id", chat.ID)
+	}
+
+	if callback := c.Callback(); callback != nil {
+		return event.Str("callback", callback.Unique).Str("data", callback.Data)
+	}
+
+	return event.Str("text", c.Text())
+}
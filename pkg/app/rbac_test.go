@@ -0,0 +1,229 @@
+package app
+
+import (
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/fs"
+	"main/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+)
+
+func getRBACTestConfig() *configPkg.Config {
+	return &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{
+			Token:  "xxx:yyy",
+			Admins: []int64{1},
+			Roles: configPkg.RolesConfig{
+				Viewers:            configPkg.RoleConfig{Users: []int64{2}, Chats: []int64{100}},
+				Silencers:          configPkg.RoleConfig{Users: []int64{3}, Chats: []int64{200}},
+				Admins:             configPkg.RoleConfig{Users: []int64{4}},
+				MaxSilenceDuration: 48 * time.Hour,
+			},
+		},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+		Alertmanager: nil,
+		Prometheus:   nil,
+	}
+}
+
+//nolint:paralleltest // disabled
+func TestAppGetRole(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getRBACTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	require.Equal(t, types.RoleAdmin, app.GetRole(1, 0))
+	require.Equal(t, types.RoleAdmin, app.GetRole(4, 0))
+	require.Equal(t, types.RoleViewer, app.GetRole(2, 0))
+	require.Equal(t, types.RoleViewer, app.GetRole(5, 100))
+	require.Equal(t, types.RoleSilencer, app.GetRole(3, 0))
+	require.Equal(t, types.RoleSilencer, app.GetRole(2, 200))
+	require.Equal(t, types.RoleAdmin, app.GetRole(1, 100))
+	require.Equal(t, types.RoleNone, app.GetRole(5, 0))
+}
+
+//nolint:paralleltest // disabled
+func TestAppGetRoleNoRolesConfigured(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	config := getRBACTestConfig()
+	config.Telegram = configPkg.TelegramConfig{Token: "xxx:yyy"}
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	require.Equal(t, types.RoleAdmin, app.GetRole(5, 0))
+}
+
+//nolint:paralleltest // disabled
+func TestAppRequireRoleAllowed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getRBACTestConfig(), &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{ID: 3, Username: "testuser"},
+			Text:   "/grafana_silence",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.RequireRole(types.RoleSilencer)(func(c tele.Context) error {
+		return errors.New("handler called")
+	})(ctx)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "handler called")
+}
+
+//nolint:paralleltest // disabled
+func TestAppRequireRoleDenied(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("You are not allowed to do this: it requires the admin role, and your role is silencer."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(getRBACTestConfig(), &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{ID: 3, Username: "testuser"},
+			Text:   "/grafana_unsilence 123",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.RequireRole(types.RoleAdmin)(func(c tele.Context) error {
+		return errors.New("handler called")
+	})(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRequireRoleDeniedCallback(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("You are not allowed to do this: it requires the viewer role, and your role is none."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(getRBACTestConfig(), &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{ID: 5, Username: "testuser"},
+			Unique: "grafana_paginated_silences_list_",
+			Data:   "1",
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Text:   "/silences",
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.RequireRole(types.RoleViewer)(func(c tele.Context) error {
+		return errors.New("handler called")
+	})(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppCreateSilenceDurationNotAllowed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Only admins can create silences longer than 48h0m0s."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(getRBACTestConfig(), &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{ID: 3, Username: "testuser"},
+			Text:   "/grafana_silence 99999h host=test",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleNewSilenceViaCommand(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppIsSilenceDurationAllowed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getRBACTestConfig(), &fs.TestFS{}, "1.2.3")
+	silencerCtx := app.Bot.NewContext(tele.Update{
+		ID:      1,
+		Message: &tele.Message{Sender: &tele.User{ID: 3}, Chat: &tele.Chat{ID: 2}},
+	})
+	adminCtx := app.Bot.NewContext(tele.Update{
+		ID:      1,
+		Message: &tele.Message{Sender: &tele.User{ID: 1}, Chat: &tele.Chat{ID: 2}},
+	})
+
+	require.True(t, app.IsSilenceDurationAllowed(silencerCtx, 48*time.Hour))
+	require.False(t, app.IsSilenceDurationAllowed(silencerCtx, 49*time.Hour))
+	require.True(t, app.IsSilenceDurationAllowed(adminCtx, 99999*time.Hour))
+}
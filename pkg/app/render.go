@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"main/pkg/constants"
 	"main/pkg/types"
@@ -54,7 +56,7 @@ func (a *App) HandleRenderPanelChooseDashboard(
 		Int("page", page).
 		Msg("Got render query to show dashboards")
 
-	dashboards, err := a.Grafana.GetAllDashboards()
+	dashboards, err := a.DashboardIndex.GetAllDashboards(context.Background())
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error fetching dashboards: %s\n", err))
 	}
@@ -110,7 +112,7 @@ func (a *App) HandleRenderPanelChoosePanelFromCallback(c tele.Context) error {
 		return c.Reply("Failed to parse page number from callback!")
 	}
 
-	dashboard, err := a.Grafana.GetDashboard(data[0])
+	dashboard, err := a.DashboardIndex.GetDashboard(context.Background(), data[0])
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error fetching dashboard: %s\n", err))
 	}
@@ -148,6 +150,24 @@ func (a *App) HandleRenderPanelChoosePanelFromCallback(c tele.Context) error {
 		func(page int) string { return fmt.Sprintf("%s %d", dashboard.Dashboard.UID, page+1) },
 	)
 
+	menu.InlineKeyboard = append(menu.InlineKeyboard, []tele.InlineButton{
+		*menu.Data("🖼 Render all", constants.GrafanaRenderAllPrefix, dashboard.Dashboard.UID).Inline(),
+	})
+
+	for _, row := range dashboard.Dashboard.GetRows() {
+		if len(row.Panels) == 0 {
+			continue
+		}
+
+		menu.InlineKeyboard = append(menu.InlineKeyboard, []tele.InlineButton{
+			*menu.Data(
+				"🖼 Render row "+row.Row.Title,
+				constants.GrafanaRenderAllPrefix,
+				fmt.Sprintf("%s %d", dashboard.Dashboard.UID, row.Row.ID),
+			).Inline(),
+		})
+	}
+
 	return a.EditRender(c, "render_choose_panel", templateData, menu)
 }
 
@@ -164,12 +184,12 @@ func (a *App) HandleRenderPanelFromCallback(c tele.Context) error {
 		return c.Reply("Invalid callback provided!")
 	}
 
-	dashboard, err := a.Grafana.GetDashboard(data[0])
+	dashboard, err := a.DashboardIndex.GetDashboard(context.Background(), data[0])
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error fetching dashboard: %s\n", err))
 	}
 
-	panel, found := generic.Find(dashboard.Dashboard.Panels, func(p types.GrafanaPanel) bool {
+	panel, found := generic.Find(dashboard.Dashboard.GetAllPanels(), func(p types.GrafanaPanel) bool {
 		return strconv.Itoa(p.ID) == data[1]
 	})
 
@@ -177,7 +197,213 @@ func (a *App) HandleRenderPanelFromCallback(c tele.Context) error {
 		return c.Reply("Panel not found!")
 	}
 
-	image, err := a.Grafana.RenderPanel(panel.ID, dashboard.Dashboard.UID, map[string]string{})
+	return a.HandleRenderVariablesGeneric(c, dashboard, *panel, types.RenderVariablesState{
+		DashboardUID: dashboard.Dashboard.UID,
+		PanelID:      panel.ID,
+		ChatID:       callback.Message.Chat.ID,
+		MessageID:    callback.Message.ID,
+		Variables:    map[string]string{},
+	})
+}
+
+func (a *App) HandleRenderChooseVariableFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got render query to choose variable value")
+
+	data := strings.SplitN(callback.Data, " ", 2)
+	if len(data) != 2 {
+		return c.Reply("Invalid callback provided!")
+	}
+
+	state := types.RenderVariablesState{}
+	if !a.Cache.GetObject(data[0], &state) {
+		return c.Reply("Render query was not found!")
+	}
+
+	index, err := strconv.Atoi(data[1])
+	if err != nil || index < 0 || index >= len(state.Options) {
+		return c.Reply("Invalid variable value provided!")
+	}
+
+	state.Variables[state.Variable] = string(state.Options[index].Value)
+
+	dashboard, panel, err := a.GetDashboardAndPanel(state.DashboardUID, state.PanelID)
+	if err != nil {
+		return c.Reply(err.Error())
+	}
+
+	return a.HandleRenderVariablesGeneric(c, dashboard, *panel, state)
+}
+
+func (a *App) HandleRenderVariablesPageFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got render query to show variable values")
+
+	data := strings.SplitN(callback.Data, " ", 2)
+	if len(data) != 2 {
+		return c.Reply("Invalid callback provided!")
+	}
+
+	state := types.RenderVariablesState{}
+	if !a.Cache.GetObject(data[0], &state) {
+		return c.Reply("Render query was not found!")
+	}
+
+	page, err := strconv.Atoi(data[1])
+	if err != nil {
+		return c.Reply("Failed to parse page number from callback!")
+	}
+
+	dashboard, panel, err := a.GetDashboardAndPanel(state.DashboardUID, state.PanelID)
+	if err != nil {
+		return c.Reply(err.Error())
+	}
+
+	return a.HandleRenderChooseVariableWithPagination(c, dashboard, *panel, data[0], state, page)
+}
+
+// HandleRenderVariablesGeneric asks to choose a value for the next dashboard variable
+// that has more than one option, or renders the panel if all of them are chosen.
+func (a *App) HandleRenderVariablesGeneric(
+	c tele.Context,
+	dashboard *types.GrafanaDashboardResponse,
+	panel types.GrafanaPanel,
+	state types.RenderVariablesState,
+) error {
+	for _, variable := range dashboard.Dashboard.Templating.List {
+		if !variable.IsSelectable() {
+			continue
+		}
+
+		if _, resolved := state.Variables[variable.Name]; resolved {
+			continue
+		}
+
+		options, err := a.Grafana.GetVariableOptions(context.Background(), variable, a.GetVariablesValues(dashboard, state))
+		if err != nil {
+			a.Logger.Warn().
+				Err(err).
+				Str("dashboard", dashboard.Dashboard.UID).
+				Str("variable", variable.Name).
+				Msg("Error fetching variable options, using its default value")
+		}
+
+		// Nothing to choose from, so the dashboard default value is used.
+		if err != nil || len(options) <= 1 {
+			state.Variables[variable.Name] = ""
+			continue
+		}
+
+		state.Variable = variable.Name
+		state.Options = options
+
+		cacheKey := a.Cache.SetObject(state.GetHash(), state)
+		return a.HandleRenderChooseVariableWithPagination(c, dashboard, panel, cacheKey, state, 0)
+	}
+
+	a.Cache.Delete(state.GetHash())
+
+	return a.RenderPanelFromCallback(c, dashboard, panel, state.GetParams())
+}
+
+func (a *App) HandleRenderChooseVariableWithPagination(
+	c tele.Context,
+	dashboard *types.GrafanaDashboardResponse,
+	panel types.GrafanaPanel,
+	cacheKey string,
+	state types.RenderVariablesState,
+	page int,
+) error {
+	variable, found := generic.Find(dashboard.Dashboard.Templating.List, func(v types.GrafanaTemplateVariable) bool {
+		return v.Name == state.Variable
+	})
+	if !found {
+		return c.Reply("Variable not found!")
+	}
+
+	chunk, totalPages := generic.Paginate(state.Options, page, constants.VariableOptionsInOneMessage)
+
+	templateData := render.RenderStruct{
+		Grafana: a.Grafana,
+		Data: types.RenderChooseVariableStruct{
+			Dashboard:    dashboard.Dashboard,
+			Panel:        panel,
+			Variable:     *variable,
+			Start:        page*constants.VariableOptionsInOneMessage + 1,
+			End:          page*constants.VariableOptionsInOneMessage + len(chunk),
+			OptionsCount: len(state.Options),
+		},
+	}
+
+	menu := GenerateMenuWithPaginationAndButtons(
+		chunk,
+		func(menu *tele.ReplyMarkup, option types.GrafanaTemplateVariableOption, index int) []tele.Btn {
+			return []tele.Btn{menu.Data(
+				string(option.Text),
+				constants.GrafanaRenderChooseVariablePrefix,
+				fmt.Sprintf("%s %d", cacheKey, page*constants.VariableOptionsInOneMessage+index),
+			)}
+		},
+		constants.GrafanaRenderVariablesPagePrefix,
+		page,
+		totalPages,
+		func(page int) string { return fmt.Sprintf("%s %d", cacheKey, page-1) },
+		func(page int) string { return fmt.Sprintf("%s %d", cacheKey, page+1) },
+	)
+
+	return a.EditRender(c, "render_choose_variable", templateData, menu)
+}
+
+// GetVariablesValues returns the values of all dashboard variables, either chosen
+// or default ones, for substituting them into other variables queries.
+func (a *App) GetVariablesValues(
+	dashboard *types.GrafanaDashboardResponse,
+	state types.RenderVariablesState,
+) map[string]string {
+	values := make(map[string]string, len(dashboard.Dashboard.Templating.List))
+
+	for _, variable := range dashboard.Dashboard.Templating.List {
+		values[variable.Name] = variable.GetValue(state.Variables[variable.Name])
+	}
+
+	return values
+}
+
+func (a *App) GetDashboardAndPanel(
+	dashboardUID string,
+	panelID int,
+) (*types.GrafanaDashboardResponse, *types.GrafanaPanel, error) {
+	dashboard, err := a.DashboardIndex.GetDashboard(context.Background(), dashboardUID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error fetching dashboard: %s\n", err)
+	}
+
+	panel, found := generic.Find(dashboard.Dashboard.GetAllPanels(), func(p types.GrafanaPanel) bool {
+		return p.ID == panelID
+	})
+
+	if !found {
+		return nil, nil, errors.New("Panel not found!")
+	}
+
+	return dashboard, panel, nil
+}
+
+func (a *App) RenderPanelFromCallback(
+	c tele.Context,
+	dashboard *types.GrafanaDashboardResponse,
+	panel types.GrafanaPanel,
+	params map[string]string,
+) error {
+	image, err := a.Grafana.RenderPanel(context.Background(), panel.ID, dashboard.Dashboard.UID, params)
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error rendering panel: %s", err))
 	}
@@ -207,17 +433,22 @@ func (a *App) HandleRenderPanelGeneric(
 	c tele.Context,
 	opts types.RenderOptions,
 ) error {
-	panels, err := a.Grafana.GetAllPanels()
+	panels, err := a.DashboardIndex.GetAllPanels(context.Background())
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error querying for panels: %s", err))
 	}
 
-	panel, found := panels.FindByName(opts.Query)
+	results := panels.SearchByName(opts.Query)
+	if results.IsAmbiguous() {
+		return a.ReplyRenderPanelChoice(c, results.Top(constants.SearchCandidatesInOneMessage), opts)
+	}
+
+	panel, found := results.Best()
 	if !found {
 		return c.Reply("Could not find a panel. See /dashboards for dashboards list, and /dashboard <dashboard name> for its panels.")
 	}
 
-	image, err := a.Grafana.RenderPanel(panel.PanelID, panel.DashboardID, opts.Params)
+	image, err := a.Grafana.RenderPanel(context.Background(), panel.PanelID, panel.DashboardID, opts.Params)
 	if err != nil {
 		return c.Reply(fmt.Sprintf("Error rendering panel: %s", err))
 	}
@@ -231,3 +462,62 @@ func (a *App) HandleRenderPanelGeneric(
 
 	return c.Reply(fileToSend, tele.ModeHTML)
 }
+
+// ReplyRenderPanelChoice asks to choose one of the panels matching the /render query,
+// if none of them matches it clearly better than the others.
+func (a *App) ReplyRenderPanelChoice(
+	c tele.Context,
+	panels []types.PanelStruct,
+	opts types.RenderOptions,
+) error {
+	choice := types.RenderPanelChoice{
+		ChatID:    c.Chat().ID,
+		MessageID: c.Message().ID,
+		Panels:    panels,
+		Params:    opts.Params,
+	}
+
+	key := a.Cache.SetObject(constants.RenderPanelChoiceCachePrefix+choice.GetHash(), choice)
+
+	menu := GenerateChoiceMenu(
+		panels,
+		func(panel types.PanelStruct) string { return panel.DashboardName + " / " + panel.Name },
+		constants.GrafanaRenderChooseFoundPanelPrefix,
+		func(panel types.PanelStruct, index int) string { return fmt.Sprintf("%s %d", key, index) },
+	)
+
+	return c.Reply(fmt.Sprintf("Found multiple panels matching \"%s\", choose one:", opts.Query), menu)
+}
+
+func (a *App) HandleRenderChooseFoundPanelFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got render query to render found panel")
+
+	data := strings.SplitN(callback.Data, " ", 2)
+	if len(data) != 2 {
+		return c.Reply("Invalid callback provided!")
+	}
+
+	choice := types.RenderPanelChoice{}
+	if !a.Cache.GetObject(data[0], &choice) {
+		return c.Reply("Render query was not found!")
+	}
+
+	index, err := strconv.Atoi(data[1])
+	if err != nil || index < 0 || index >= len(choice.Panels) {
+		return c.Reply("Invalid panel provided!")
+	}
+
+	dashboard, panel, err := a.GetDashboardAndPanel(choice.Panels[index].DashboardID, choice.Panels[index].PanelID)
+	if err != nil {
+		return c.Reply(err.Error())
+	}
+
+	a.Cache.Delete(data[0])
+
+	return a.RenderPanelFromCallback(c, dashboard, *panel, choice.Params)
+}
@@ -0,0 +1,258 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"main/pkg/utils"
+	"main/pkg/utils/generic"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) HandleRenderDashboard(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got render dashboard query")
+
+	opts, valid := utils.ParseRenderOptions(c.Text())
+	if !valid {
+		return c.Reply("Usage: /render_dashboard [opts] <dashboard>")
+	}
+
+	dashboards, err := a.DashboardIndex.GetAllDashboards(context.Background())
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying for dashboards: %s", err))
+	}
+
+	results := dashboards.SearchDashboardsByName(opts.Query)
+	if results.IsAmbiguous() {
+		return a.ReplyRenderDashboardChoice(c, results.Top(constants.SearchCandidatesInOneMessage), opts)
+	}
+
+	dashboard, found := results.Best()
+	if !found {
+		return c.Reply("Could not find dashboard. See /dashboards for dashboards list.")
+	}
+
+	image, err := a.Grafana.RenderDashboard(context.Background(), dashboard.UID, opts.Params)
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error rendering dashboard: %s", err))
+	}
+
+	defer image.Close()
+
+	fileToSend := &tele.Photo{
+		File:    tele.FromReader(image),
+		Caption: fmt.Sprintf("Dashboard: %s", a.Grafana.GetDashboardLink(*dashboard)),
+	}
+
+	return c.Reply(fileToSend, tele.ModeHTML)
+}
+
+// ReplyRenderDashboardChoice asks to choose one of the dashboards matching the
+// /render_dashboard query, if none of them matches it clearly better than the others.
+func (a *App) ReplyRenderDashboardChoice(
+	c tele.Context,
+	dashboards []types.GrafanaDashboardInfo,
+	opts types.RenderOptions,
+) error {
+	choice := types.RenderDashboardChoice{
+		ChatID:     c.Chat().ID,
+		MessageID:  c.Message().ID,
+		Dashboards: dashboards,
+		Params:     opts.Params,
+	}
+
+	key := a.Cache.SetObject(constants.RenderDashboardCachePrefix+choice.GetHash(), choice)
+
+	menu := GenerateChoiceMenu(
+		dashboards,
+		GetDashboardChoiceText,
+		constants.RenderChooseFoundDashboardPrefix,
+		func(dashboard types.GrafanaDashboardInfo, index int) string { return fmt.Sprintf("%s %d", key, index) },
+	)
+
+	return c.Reply(fmt.Sprintf("Found multiple dashboards matching \"%s\", choose one:", opts.Query), menu)
+}
+
+func (a *App) HandleRenderChooseFoundDashboardFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got render query to render found dashboard")
+
+	data := strings.SplitN(callback.Data, " ", 2)
+	if len(data) != 2 {
+		return c.Reply("Invalid callback provided!")
+	}
+
+	choice := types.RenderDashboardChoice{}
+	if !a.Cache.GetObject(data[0], &choice) {
+		return c.Reply("Render query was not found!")
+	}
+
+	index, err := strconv.Atoi(data[1])
+	if err != nil || index < 0 || index >= len(choice.Dashboards) {
+		return c.Reply("Invalid dashboard provided!")
+	}
+
+	dashboard := choice.Dashboards[index]
+
+	image, err := a.Grafana.RenderDashboard(context.Background(), dashboard.UID, choice.Params)
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error rendering dashboard: %s", err))
+	}
+
+	defer image.Close()
+
+	a.Cache.Delete(data[0])
+
+	replyTo := c.Message().ReplyTo
+
+	if deleteErr := a.Bot.Delete(c.Message()); deleteErr != nil {
+		a.Logger.Error().Err(deleteErr).Msg("Failed to delete message")
+	}
+
+	_, sendErr := a.Bot.Reply(replyTo, &tele.Photo{
+		File:    tele.FromReader(image),
+		Caption: fmt.Sprintf("Dashboard: %s", a.Grafana.GetDashboardLink(dashboard)),
+	}, tele.ModeHTML)
+	return sendErr
+}
+
+// HandleRenderAllFromCallback renders either the whole dashboard, if only
+// its UID is passed, or all panels of a row, if the row ID is passed as well.
+func (a *App) HandleRenderAllFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got render query to render all panels")
+
+	data := strings.Split(callback.Data, " ")
+	if len(data) != 1 && len(data) != 2 {
+		return c.Reply("Invalid callback provided!")
+	}
+
+	dashboard, err := a.DashboardIndex.GetDashboard(context.Background(), data[0])
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error fetching dashboard: %s\n", err))
+	}
+
+	dashboardInfo := types.GrafanaDashboardInfo{
+		UID:   dashboard.Dashboard.UID,
+		Title: dashboard.Dashboard.Title,
+		URL:   dashboard.Meta.URL,
+	}
+
+	if len(data) == 1 {
+		image, renderErr := a.Grafana.RenderDashboard(context.Background(), dashboard.Dashboard.UID, map[string]string{})
+		if renderErr != nil {
+			return c.Reply(fmt.Sprintf("Error rendering dashboard: %s", renderErr))
+		}
+
+		defer image.Close()
+
+		replyTo := c.Message().ReplyTo
+
+		if deleteErr := a.Bot.Delete(c.Message()); deleteErr != nil {
+			a.Logger.Error().Err(deleteErr).Msg("Failed to delete message")
+		}
+
+		_, sendErr := a.Bot.Reply(replyTo, &tele.Photo{
+			File:    tele.FromReader(image),
+			Caption: fmt.Sprintf("Dashboard: %s", a.Grafana.GetDashboardLink(dashboardInfo)),
+		}, tele.ModeHTML)
+		return sendErr
+	}
+
+	row, found := generic.Find(dashboard.Dashboard.GetRows(), func(r types.GrafanaDashboardRow) bool {
+		return strconv.Itoa(r.Row.ID) == data[1]
+	})
+	if !found {
+		return c.Reply("Row not found!")
+	}
+
+	panels := generic.Filter(row.Panels, func(panel types.GrafanaPanel) bool {
+		return panel.Type != "row"
+	})
+	if len(panels) == 0 {
+		return c.Reply("This row has no panels!")
+	}
+
+	images, err := a.Grafana.RenderPanels(
+		context.Background(),
+		generic.Map(panels, func(p types.GrafanaPanel) int { return p.ID }),
+		dashboard.Dashboard.UID,
+		map[string]string{},
+	)
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error rendering panels: %s", err))
+	}
+
+	defer func() {
+		for _, image := range images {
+			image.Close()
+		}
+	}()
+
+	replyTo := c.Message().ReplyTo
+
+	if deleteErr := a.Bot.Delete(c.Message()); deleteErr != nil {
+		a.Logger.Error().Err(deleteErr).Msg("Failed to delete message")
+	}
+
+	return a.SendRenderedPanels(replyTo, dashboard, panels, images)
+}
+
+// SendRenderedPanels sends the rendered panels as a reply, each with its link as a caption.
+func (a *App) SendRenderedPanels(
+	replyTo *tele.Message,
+	dashboard *types.GrafanaDashboardResponse,
+	panels []types.GrafanaPanel,
+	images []io.ReadCloser,
+) error {
+	photos := make([]*tele.Photo, len(panels))
+
+	for index, panel := range panels {
+		photos[index] = &tele.Photo{
+			File: tele.FromReader(images[index]),
+			Caption: fmt.Sprintf("Panel: %s", a.Grafana.GetPanelLink(types.PanelStruct{
+				PanelID:      panel.ID,
+				DashboardURL: dashboard.Meta.URL,
+				Name:         panel.Title,
+			})),
+		}
+	}
+
+	return a.SendPhotosAlbum(replyTo.Chat, photos, &tele.SendOptions{ReplyTo: replyTo, ParseMode: tele.ModeHTML})
+}
+
+// SendPhotosAlbum sends photos as media albums, splitting them into multiple
+// albums if there are more photos than Telegram allows in one.
+func (a *App) SendPhotosAlbum(to tele.Recipient, photos []*tele.Photo, opts *tele.SendOptions) error {
+	for start := 0; start < len(photos); start += constants.MaxAlbumSize {
+		end := min(start+constants.MaxAlbumSize, len(photos))
+
+		album := make(tele.Album, 0, end-start)
+		for _, photo := range photos[start:end] {
+			album = append(album, photo)
+		}
+
+		if _, err := a.Bot.SendAlbum(to, album, opts); err != nil {
+			a.Logger.Error().Err(err).Msg("Could not send album")
+			return err
+		}
+	}
+
+	return nil
+}
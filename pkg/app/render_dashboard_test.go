@@ -0,0 +1,446 @@
+package app
+
+import (
+	"errors"
+	"main/assets"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+)
+
+func renderDashboardTestCallbackContext(app *App, data string) tele.Context {
+	return app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/render",
+			Chat:   &tele.Chat{ID: 2},
+			ReplyTo: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Text:   "/render",
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\f" + constants.GrafanaRenderAllPrefix,
+			Data:   data,
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Text:   "/render",
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderDashboardInvalidInvocation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Usage: /render_dashboard [opts] <dashboard>"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/render_dashboard",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleRenderDashboard(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderDashboardNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok-single.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Could not find dashboard. See /dashboards for dashboards list."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/render_dashboard unknown",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleRenderDashboard(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderDashboardRenderError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok-single.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d/alertmanager/dashboard?height=-1",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error rendering dashboard: Get \"https://example.com/render/d/alertmanager/dashboard?height=-1\": custom error"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/render_dashboard alertmanager",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleRenderDashboard(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderDashboardOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok-single.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d/alertmanager/dashboard?from=now-1h&height=-1",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendPhoto",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/render_dashboard from=now-1h alertmanager",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleRenderDashboard(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendPhoto"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderAllFromCallbackInvalidInvocation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid callback provided!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	err := app.HandleRenderAllFromCallback(renderDashboardTestCallbackContext(app, "dashboard 1 2"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderAllFromCallbackDashboardOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/dashboard",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d/alertmanager/dashboard?height=-1",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/deleteMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendPhoto",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	err := app.HandleRenderAllFromCallback(renderDashboardTestCallbackContext(app, "dashboard"))
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendPhoto"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderAllFromCallbackRowNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/dashboard",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Row not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	err := app.HandleRenderAllFromCallback(renderDashboardTestCallbackContext(app, "dashboard 999"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderAllFromCallbackRowRenderError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/dashboard",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=118",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=115",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error rendering panels: Get \"https://example.com/render/d-solo/alertmanager/dashboard?panelId=115\": custom error"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	err := app.HandleRenderAllFromCallback(renderDashboardTestCallbackContext(app, "dashboard 113"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderAllFromCallbackRowOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/dashboard",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=118",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=115",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/deleteMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMediaGroup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-media-group-ok.json")),
+	)
+
+	err := app.HandleRenderAllFromCallback(renderDashboardTestCallbackContext(app, "dashboard 113"))
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMediaGroup"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderDashboardAmbiguous(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+
+	key := constants.RenderDashboardCachePrefix + types.RenderDashboardChoice{ChatID: 2}.GetHash()
+
+	button := func(text string, index string) []types.TelegramInlineKeyboard {
+		return []types.TelegramInlineKeyboard{{
+			Unique:       constants.RenderChooseFoundDashboardPrefix,
+			Text:         text,
+			CallbackData: "\f" + constants.RenderChooseFoundDashboardPrefix + "|" + key + " " + index,
+		}}
+	}
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasTextAndMarkup(
+			"Found multiple dashboards matching \"unifi poller\", choose one:",
+			types.TelegramInlineKeyboardResponse{InlineKeyboard: [][]types.TelegramInlineKeyboard{
+				button("UniFi-Poller: Client DPI - Prometheus", "0"),
+				button("UniFi-Poller: Client Insights - Prometheus", "1"),
+				button("UniFi-Poller: UAP Insights - Prometheus", "2"),
+				{{
+					Unique:       constants.ClearKeyboardPrefix,
+					Text:         "❌Cancel",
+					CallbackData: "\f" + constants.ClearKeyboardPrefix,
+				}},
+			}},
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleRenderDashboard(newTestContext(app, "/render_dashboard from=now-1h unifi poller"))
+	require.NoError(t, err)
+
+	choice := types.RenderDashboardChoice{}
+	require.True(t, app.Cache.GetObject(key, &choice))
+	require.Equal(t, map[string]string{"from": "now-1h"}, choice.Params)
+	require.Len(t, choice.Dashboards, 3)
+	require.Equal(t, "unpoller-dpi", choice.Dashboards[0].UID)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseFoundDashboardNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Render query was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleRenderChooseFoundDashboardFromCallback(renderDashboardTestCallbackContext(app, "not-existing 0"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseFoundDashboardInvalidIndex(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	choice := types.RenderDashboardChoice{ChatID: 2, Dashboards: []types.GrafanaDashboardInfo{{UID: "alertmanager"}}}
+	key := app.Cache.SetObject(constants.RenderDashboardCachePrefix+choice.GetHash(), choice)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid dashboard provided!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleRenderChooseFoundDashboardFromCallback(renderDashboardTestCallbackContext(app, key+" 1"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseFoundDashboardOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	// Rendered with the params from the original query.
+	choice := types.RenderDashboardChoice{
+		ChatID: 2,
+		Dashboards: []types.GrafanaDashboardInfo{
+			{UID: "node-exporter", Title: "Node Exporter"},
+			{UID: "alertmanager", Title: "Alertmanager"},
+		},
+		Params: map[string]string{"from": "now-1h"},
+	}
+	key := app.Cache.SetObject(constants.RenderDashboardCachePrefix+choice.GetHash(), choice)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d/alertmanager/dashboard?from=now-1h&height=-1",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/deleteMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendPhoto",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleRenderChooseFoundDashboardFromCallback(renderDashboardTestCallbackContext(app, key+" 1"))
+	require.NoError(t, err)
+
+	require.False(t, app.Cache.GetObject(key, &choice))
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendPhoto"])
+}
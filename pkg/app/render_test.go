@@ -8,6 +8,7 @@ import (
 	"main/pkg/fs"
 	"main/pkg/types"
 	"testing"
+	"time"
 
 	"github.com/guregu/null/v5"
 
@@ -25,7 +26,7 @@ func TestAppRenderPanelErrorFetchingPanels(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -70,7 +71,7 @@ func TestAppRenderPanelPanelNotFound(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -120,7 +121,7 @@ func TestAppRenderPanelRenderError(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -175,7 +176,7 @@ func TestAppRenderPanelOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -220,6 +221,60 @@ func TestAppRenderPanelOk(t *testing.T) {
 	require.NoError(t, err)
 }
 
+//nolint:paralleltest // disabled
+func TestAppRenderPanelWithDashboardIndexOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.DashboardIndex = configPkg.DashboardIndexConfig{
+			Enabled:                  null.BoolFrom(true),
+			RefreshInterval:          time.Minute,
+			DashboardRefreshInterval: time.Hour,
+		}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok-single.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/alertmanager",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	// A panel nested into a collapsed row.
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=177",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendPhoto",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	for range 2 {
+		ctx := app.Bot.NewContext(tele.Update{
+			ID: 1,
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Text:   "/render memory usage",
+				Chat:   &tele.Chat{ID: 2},
+			},
+		})
+
+		err := app.HandleRenderPanel(ctx)
+		require.NoError(t, err)
+	}
+
+	callsCount := httpmock.GetCallCountInfo()
+	require.Equal(t, 1, callsCount["GET https://example.com/api/search?type=dash-db"])
+	require.Equal(t, 1, callsCount["GET https://example.com/api/dashboards/uid/alertmanager"])
+}
+
 //nolint:paralleltest // disabled
 func TestAppRenderPanelChooseDashboardErrorFetchingDashboards(t *testing.T) {
 	httpmock.Activate()
@@ -229,7 +284,7 @@ func TestAppRenderPanelChooseDashboardErrorFetchingDashboards(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -274,9 +329,9 @@ func TestAppRenderChooseDashboardInvalidInvocation(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -324,9 +379,9 @@ func TestAppRenderChooseDashboardNoDashboards(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -379,9 +434,9 @@ func TestAppRenderChooseDashboardOkFromCallback(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -434,9 +489,9 @@ func TestAppRenderChooseDashboardOkFromCommand(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -479,9 +534,9 @@ func TestAppRenderChoosePanelWrongCallbackData(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -529,9 +584,9 @@ func TestAppRenderChoosePanelInvalidPage(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -579,9 +634,9 @@ func TestAppRenderChoosePanelFailedToFetchDashboard(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -634,9 +689,9 @@ func TestAppRenderChoosePanelDashboardWithoutPanels(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -689,9 +744,9 @@ func TestAppRenderChoosePanelOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -744,9 +799,9 @@ func TestAppRenderPanelFromCallbackInvalidInvocation(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -794,9 +849,9 @@ func TestAppRenderPanelFromCallbackErrorFetchingDashboard(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -849,9 +904,9 @@ func TestAppRenderPanelFromCallbackPanelNotFound(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -904,9 +959,9 @@ func TestAppRenderPanelFromCallbackFailedToRender(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -917,7 +972,7 @@ func TestAppRenderPanelFromCallbackFailedToRender(t *testing.T) {
 	httpmock.RegisterMatcherResponder(
 		"POST",
 		"https://api.telegram.org/botxxx:yyy/sendMessage",
-		types.TelegramResponseHasText("Error rendering panel: Get \"https://example.com/render/d-solo/alertmanager/dashboard?panelId=123\": custom error"),
+		types.TelegramResponseHasText("Error rendering panel: Get \"https://example.com/render/d-solo/alertmanager/dashboard?panelId=121\": custom error"),
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
 	)
 
@@ -938,7 +993,7 @@ func TestAppRenderPanelFromCallbackFailedToRender(t *testing.T) {
 
 	httpmock.RegisterResponder(
 		"GET",
-		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=123",
+		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=121",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
 	httpmock.RegisterResponder(
@@ -963,7 +1018,7 @@ func TestAppRenderPanelFromCallbackFailedToRender(t *testing.T) {
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
 			Unique: "\f" + constants.GrafanaRenderRenderPanelPrefix,
-			Data:   "dashboard 123",
+			Data:   "dashboard 121",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
 				Text:   "/render",
@@ -985,9 +1040,9 @@ func TestAppRenderPanelFromCallbackOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -1012,7 +1067,7 @@ func TestAppRenderPanelFromCallbackOk(t *testing.T) {
 
 	httpmock.RegisterResponder(
 		"GET",
-		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=123",
+		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=121",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
 
 	httpmock.RegisterResponder(
@@ -1037,7 +1092,7 @@ func TestAppRenderPanelFromCallbackOk(t *testing.T) {
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
 			Unique: "\f" + constants.GrafanaRenderRenderPanelPrefix,
-			Data:   "dashboard 123",
+			Data:   "dashboard 121",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
 				Text:   "/render",
@@ -1049,3 +1104,457 @@ func TestAppRenderPanelFromCallbackOk(t *testing.T) {
 	err := app.HandleRenderPanelFromCallback(ctx)
 	require.NoError(t, err)
 }
+
+func renderVariablesTestApp(t *testing.T) (*App, types.RenderVariablesState) {
+	t.Helper()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/variables",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-variables.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources/proxy/uid/prometheus/api/v1/label/instance/values?match%5B%5D=up%7Benv%3D%22staging%22%7D",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-label-values-ok.json")))
+
+	state := types.RenderVariablesState{
+		DashboardUID: "variables",
+		PanelID:      1,
+		ChatID:       2,
+		MessageID:    3,
+		Variables:    map[string]string{},
+	}
+
+	return app, state
+}
+
+func renderVariablesTestContext(app *App, unique, data string) tele.Context {
+	message := &tele.Message{
+		ID:     3,
+		Sender: &tele.User{Username: "testuser"},
+		Text:   "/render",
+		Chat:   &tele.Chat{ID: 2},
+		ReplyTo: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/render",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	}
+
+	return app.Bot.NewContext(tele.Update{
+		ID:      1,
+		Message: message,
+		Callback: &tele.Callback{
+			Sender:  &tele.User{Username: "testuser"},
+			Unique:  "\f" + unique,
+			Data:    data,
+			Message: message,
+		},
+	})
+}
+
+func instanceVariableKeyboard(cacheKey string) types.TelegramInlineKeyboardResponse {
+	return types.TelegramInlineKeyboardResponse{
+		InlineKeyboard: [][]types.TelegramInlineKeyboard{
+			{{
+				Unique:       "render_choose_variable_",
+				Text:         "All",
+				CallbackData: "\frender_choose_variable_|" + cacheKey + " 0",
+			}},
+			{{
+				Unique:       "render_choose_variable_",
+				Text:         "host1:9100",
+				CallbackData: "\frender_choose_variable_|" + cacheKey + " 1",
+			}},
+			{{
+				Unique:       "render_choose_variable_",
+				Text:         "host2:9100",
+				CallbackData: "\frender_choose_variable_|" + cacheKey + " 2",
+			}},
+		},
+	}
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderPanelFromCallbackWithVariables(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app, state := renderVariablesTestApp(t)
+	cacheKey := state.GetHash()
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageText",
+		types.TelegramResponseHasTextAndMarkup(
+			"Dashboard: Variables\nPanel: Panel\nChoose a value for Environment (1 - 2 of 2):",
+			types.TelegramInlineKeyboardResponse{
+				InlineKeyboard: [][]types.TelegramInlineKeyboard{
+					{{
+						Unique:       "render_choose_variable_",
+						Text:         "prod",
+						CallbackData: "\frender_choose_variable_|" + cacheKey + " 0",
+					}},
+					{{
+						Unique:       "render_choose_variable_",
+						Text:         "staging",
+						CallbackData: "\frender_choose_variable_|" + cacheKey + " 1",
+					}},
+				},
+			},
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	err := app.HandleRenderPanelFromCallback(renderVariablesTestContext(app, constants.GrafanaRenderRenderPanelPrefix, "variables 1"))
+	require.NoError(t, err)
+
+	savedState := types.RenderVariablesState{}
+	require.True(t, app.Cache.GetObject(cacheKey, &savedState))
+	require.Equal(t, "env", savedState.Variable)
+	require.Empty(t, savedState.Variables)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseVariableAskNext(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app, state := renderVariablesTestApp(t)
+	state.Variable = "env"
+	state.Options = []types.GrafanaTemplateVariableOption{
+		{Text: "prod", Value: "prod"},
+		{Text: "staging", Value: "staging"},
+	}
+	cacheKey := app.Cache.SetObject(state.GetHash(), state)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageText",
+		types.TelegramResponseHasTextAndMarkup(
+			"Dashboard: Variables\nPanel: Panel\nChoose a value for instance (1 - 3 of 3):",
+			instanceVariableKeyboard(cacheKey),
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	err := app.HandleRenderChooseVariableFromCallback(renderVariablesTestContext(app, constants.GrafanaRenderChooseVariablePrefix, cacheKey+" 1"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderVariablesPage(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app, state := renderVariablesTestApp(t)
+	state.Variables = map[string]string{"env": "staging"}
+	state.Variable = "instance"
+	state.Options = []types.GrafanaTemplateVariableOption{
+		{Text: "All", Value: "$__all"},
+		{Text: "host1:9100", Value: "host1:9100"},
+		{Text: "host2:9100", Value: "host2:9100"},
+	}
+	cacheKey := app.Cache.SetObject(state.GetHash(), state)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageText",
+		types.TelegramResponseHasTextAndMarkup(
+			"Dashboard: Variables\nPanel: Panel\nChoose a value for instance (1 - 3 of 3):",
+			instanceVariableKeyboard(cacheKey),
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	err := app.HandleRenderVariablesPageFromCallback(renderVariablesTestContext(app, constants.GrafanaRenderVariablesPagePrefix, cacheKey+" 0"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseVariableInvalidOption(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app, state := renderVariablesTestApp(t)
+	state.Variable = "env"
+	state.Options = []types.GrafanaTemplateVariableOption{{Text: "prod", Value: "prod"}}
+	cacheKey := app.Cache.SetObject(state.GetHash(), state)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid variable value provided!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	err := app.HandleRenderChooseVariableFromCallback(renderVariablesTestContext(app, constants.GrafanaRenderChooseVariablePrefix, cacheKey+" 5"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseVariableRender(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app, state := renderVariablesTestApp(t)
+	state.Variables = map[string]string{"env": "staging"}
+	state.Variable = "instance"
+	state.Options = []types.GrafanaTemplateVariableOption{
+		{Text: "All", Value: "$__all"},
+		{Text: "host1:9100", Value: "host1:9100"},
+	}
+	cacheKey := app.Cache.SetObject(state.GetHash(), state)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/variables/dashboard?panelId=1&var-env=staging&var-instance=host1%3A9100",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/deleteMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendPhoto",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleRenderChooseVariableFromCallback(renderVariablesTestContext(app, constants.GrafanaRenderChooseVariablePrefix, cacheKey+" 1"))
+	require.NoError(t, err)
+
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendPhoto"])
+
+	_, found := app.Cache.Get(cacheKey)
+	require.False(t, found)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseVariableNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Render query was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\f" + constants.GrafanaRenderChooseVariablePrefix,
+			Data:   "key 1",
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Text:   "/render",
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.HandleRenderChooseVariableFromCallback(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderPanelAmbiguous(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok-single.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/alertmanager",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	var keyboard types.TelegramInlineKeyboardResponse
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Found multiple panels matching \"number of instances\", choose one:"),
+		captureKeyboard(&keyboard))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	err := app.HandleRenderPanel(newTestContext(app, "/render from=now-1h number of instances"))
+	require.NoError(t, err)
+
+	// The dashboard has two panels with this name, both are offered, followed by less relevant ones.
+	key := constants.RenderPanelChoiceCachePrefix + types.RenderPanelChoice{ChatID: 2}.GetHash()
+	require.Greater(t, len(keyboard.InlineKeyboard), 2)
+	require.LessOrEqual(t, len(keyboard.InlineKeyboard), constants.SearchCandidatesInOneMessage+1)
+	require.Equal(t, types.TelegramInlineKeyboard{
+		Unique:       constants.GrafanaRenderChooseFoundPanelPrefix,
+		Text:         "Alertmanager / Number of instances",
+		CallbackData: "\f" + constants.GrafanaRenderChooseFoundPanelPrefix + "|" + key + " 0",
+	}, keyboard.InlineKeyboard[0][0])
+	require.Equal(t, "Alertmanager / Number of instances", keyboard.InlineKeyboard[1][0].Text)
+	require.Equal(t, "❌Cancel", keyboard.InlineKeyboard[len(keyboard.InlineKeyboard)-1][0].Text)
+
+	choice := types.RenderPanelChoice{}
+	require.True(t, app.Cache.GetObject(key, &choice))
+	require.Equal(t, map[string]string{"from": "now-1h"}, choice.Params)
+	require.Len(t, choice.Panels, len(keyboard.InlineKeyboard)-1)
+}
+
+func renderChooseFoundPanelTestContext(app *App, data string) tele.Context {
+	return app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\f" + constants.GrafanaRenderChooseFoundPanelPrefix,
+			Data:   data,
+			Message: &tele.Message{
+				ID:     3,
+				Sender: &tele.User{Username: "testuser"},
+				Text:   "Found multiple panels matching \"memory\", choose one:",
+				Chat:   &tele.Chat{ID: 2},
+				ReplyTo: &tele.Message{
+					Sender: &tele.User{Username: "testuser"},
+					Text:   "/render memory",
+					Chat:   &tele.Chat{ID: 2},
+				},
+			},
+		},
+	})
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseFoundPanelInvalidCallback(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid callback provided!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleRenderChooseFoundPanelFromCallback(renderChooseFoundPanelTestContext(app, "key"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseFoundPanelNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Render query was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleRenderChooseFoundPanelFromCallback(renderChooseFoundPanelTestContext(app, "not-existing 0"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseFoundPanelInvalidIndex(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	choice := types.RenderPanelChoice{ChatID: 2, Panels: []types.PanelStruct{{DashboardID: "alertmanager", PanelID: 177}}}
+	key := app.Cache.SetObject(constants.RenderPanelChoiceCachePrefix+choice.GetHash(), choice)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid panel provided!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleRenderChooseFoundPanelFromCallback(renderChooseFoundPanelTestContext(app, key+" 1"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppRenderChooseFoundPanelOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t)
+
+	// A panel nested into a collapsed row, rendered with the params from the original query.
+	choice := types.RenderPanelChoice{
+		ChatID: 2,
+		Panels: []types.PanelStruct{
+			{DashboardID: "alertmanager", PanelID: 176},
+			{DashboardID: "alertmanager", PanelID: 177},
+		},
+		Params: map[string]string{"from": "now-1h"},
+	}
+	key := app.Cache.SetObject(constants.RenderPanelChoiceCachePrefix+choice.GetHash(), choice)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/alertmanager",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/alertmanager/dashboard?from=now-1h&panelId=177",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/deleteMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendPhoto",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleRenderChooseFoundPanelFromCallback(renderChooseFoundPanelTestContext(app, key+" 1"))
+	require.NoError(t, err)
+
+	require.False(t, app.Cache.GetObject(key, &choice))
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendPhoto"])
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	configPkg "main/pkg/config"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"main/pkg/utils/generic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) StartReportsScheduler(ctx context.Context) {
+	if len(a.Config.Reports) == 0 {
+		a.Logger.Debug().Msg("Reports are not configured, not starting reports scheduler")
+		return
+	}
+
+	for _, report := range a.Config.Reports {
+		go a.StartReportScheduler(ctx, report)
+	}
+}
+
+func (a *App) StartReportScheduler(ctx context.Context, report configPkg.ReportConfig) {
+	schedule, err := cron.ParseStandard(report.Schedule)
+	if err != nil {
+		a.Logger.Error().Err(err).Str("report", report.Name).Msg("Could not parse report schedule")
+		return
+	}
+
+	timezone, _ := time.LoadLocation(a.Config.Timezone)
+
+	a.Logger.Info().
+		Str("report", report.Name).
+		Str("schedule", report.Schedule).
+		Msg("Starting report scheduler")
+
+	for {
+		// The schedule is evaluated in the time zone of the time passed to it.
+		next := schedule.Next(time.Now().In(timezone))
+		if next.IsZero() {
+			a.Logger.Error().Str("report", report.Name).Msg("Report schedule never matches")
+			return
+		}
+
+		a.Logger.Debug().
+			Str("report", report.Name).
+			Time("next", next).
+			Msg("Scheduled next report")
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			a.Logger.Info().Str("report", report.Name).Msg("Stopping report scheduler")
+			return
+		case <-timer.C:
+			a.SendReport(ctx, report)
+		}
+	}
+}
+
+// SendReport renders all report panels and sends them to all report chats
+// as an album, with the report summary as a caption.
+func (a *App) SendReport(ctx context.Context, report configPkg.ReportConfig) {
+	a.Logger.Info().Str("report", report.Name).Msg("Sending report")
+
+	panels, failedPanels := a.ResolveReportPanels(ctx, report)
+	renderedPanels := make([]types.PanelStruct, 0, len(panels))
+	images := make([][]byte, 0, len(panels))
+
+	for _, panel := range panels {
+		image, err := a.RenderPanelToBytes(ctx, panel, report.RenderOptions)
+		if err != nil {
+			a.Logger.Error().
+				Err(err).
+				Str("report", report.Name).
+				Str("panel", panel.Name).
+				Msg("Could not render report panel")
+			failedPanels = append(failedPanels, types.ReportPanelError{Name: panel.Name, Error: err.Error()})
+			continue
+		}
+
+		renderedPanels = append(renderedPanels, panel)
+		images = append(images, image)
+	}
+
+	caption, err := a.TemplateManager.Render("report", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data: types.ReportStruct{
+			Name:         report.Name,
+			Panels:       renderedPanels,
+			FailedPanels: failedPanels,
+			RenderTime:   time.Now(),
+		},
+	})
+	if err != nil {
+		a.Logger.Error().Err(err).Str("report", report.Name).Msg("Error rendering report template")
+		return
+	}
+
+	for _, chatID := range report.Chats {
+		if err := a.SendReportToChat(chatID, caption, images); err != nil {
+			a.Logger.Error().
+				Err(err).
+				Str("report", report.Name).
+				Int64("chat", chatID).
+				Msg("Could not send report")
+		}
+	}
+}
+
+func (a *App) SendReportToChat(chatID int64, caption string, images [][]byte) error {
+	// Telegram does not allow long captions, so if the caption is too long,
+	// or if there's nothing to send it with, it's sent as a separate message.
+	if len(images) == 0 || len(caption) > MaxCaptionSize {
+		if err := a.BotSend(chatID, caption); err != nil {
+			return err
+		}
+
+		caption = ""
+	}
+
+	if len(images) == 0 {
+		return nil
+	}
+
+	photos := generic.Map(images, func(image []byte) *tele.Photo {
+		return &tele.Photo{File: tele.FromReader(bytes.NewReader(image))}
+	})
+	photos[0].Caption = caption
+
+	return a.SendPhotosAlbum(tele.ChatID(chatID), photos, &tele.SendOptions{ParseMode: tele.ModeHTML})
+}
+
+// ResolveReportPanels finds the report panels in Grafana, either by dashboard UID
+// and panel ID, or by name, returning the ones that were not found separately.
+func (a *App) ResolveReportPanels(ctx context.Context, report configPkg.ReportConfig) ([]types.PanelStruct, []types.ReportPanelError) {
+	panels := make([]types.PanelStruct, 0, len(report.Panels))
+	failedPanels := make([]types.ReportPanelError, 0)
+	dashboards := make(map[string]*types.GrafanaDashboardResponse)
+
+	var allPanels types.PanelsStruct
+
+	for _, panelConfig := range report.Panels {
+		if panelConfig.Name != "" {
+			if allPanels == nil {
+				fetchedPanels, err := a.DashboardIndex.GetAllPanels(ctx)
+				if err != nil {
+					failedPanels = append(failedPanels, types.ReportPanelError{Name: panelConfig.Name, Error: err.Error()})
+					continue
+				}
+
+				allPanels = fetchedPanels
+			}
+
+			panel, found := allPanels.FindByName(panelConfig.Name)
+			if !found {
+				failedPanels = append(failedPanels, types.ReportPanelError{Name: panelConfig.Name, Error: "panel not found"})
+				continue
+			}
+
+			panels = append(panels, *panel)
+			continue
+		}
+
+		name := fmt.Sprintf("%s/%d", panelConfig.Dashboard, panelConfig.PanelID)
+
+		dashboard, found := dashboards[panelConfig.Dashboard]
+		if !found {
+			fetchedDashboard, err := a.DashboardIndex.GetDashboard(ctx, panelConfig.Dashboard)
+			if err != nil {
+				failedPanels = append(failedPanels, types.ReportPanelError{Name: name, Error: err.Error()})
+				continue
+			}
+
+			dashboard = fetchedDashboard
+			dashboards[panelConfig.Dashboard] = dashboard
+		}
+
+		panel, found := generic.Find(dashboard.Dashboard.GetAllPanels(), func(p types.GrafanaPanel) bool {
+			return p.ID == panelConfig.PanelID
+		})
+		if !found {
+			failedPanels = append(failedPanels, types.ReportPanelError{Name: name, Error: "panel not found"})
+			continue
+		}
+
+		panels = append(panels, types.PanelStruct{
+			Name:          panel.Title,
+			DashboardName: dashboard.Dashboard.Title,
+			DashboardID:   dashboard.Dashboard.UID,
+			DashboardURL:  dashboard.Meta.URL,
+			PanelID:       panel.ID,
+		})
+	}
+
+	return panels, failedPanels
+}
+
+func (a *App) RenderPanelToBytes(ctx context.Context, panel types.PanelStruct, params map[string]string) ([]byte, error) {
+	image, err := a.Grafana.RenderPanel(ctx, panel.PanelID, panel.DashboardID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	defer image.Close()
+
+	return io.ReadAll(image)
+}
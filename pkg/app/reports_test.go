@@ -0,0 +1,262 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/types"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // disabled
+func TestAppStartReportsSchedulerNotConfigured(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Reports = nil
+	})
+	app.StartReportsScheduler(context.Background())
+}
+
+//nolint:paralleltest // disabled
+func TestAppStartReportSchedulerStop(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	report := configPkg.ReportConfig{Name: "report", Schedule: "0 9 * * *", Chats: []int64{1}}
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Reports = []configPkg.ReportConfig{report}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	app.StartReportScheduler(ctx, report)
+}
+
+//nolint:paralleltest // disabled
+func TestAppStartReportSchedulerInvalidSchedule(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	report := configPkg.ReportConfig{Name: "report", Schedule: "invalid", Chats: []int64{1}}
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Reports = []configPkg.ReportConfig{report}
+	})
+
+	app.StartReportScheduler(context.Background(), report)
+}
+
+//nolint:paralleltest // disabled
+func TestAppStartReportSchedulerNeverMatches(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	report := configPkg.ReportConfig{Name: "report", Schedule: "0 0 30 FEB *", Chats: []int64{1}}
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Reports = []configPkg.ReportConfig{report}
+	})
+
+	app.StartReportScheduler(context.Background(), report)
+}
+
+//nolint:paralleltest // disabled
+func TestAppResolveReportPanels(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	report := configPkg.ReportConfig{
+		Name:     "report",
+		Schedule: "0 9 * * *",
+		Chats:    []int64{1},
+		Panels: []configPkg.ReportPanelConfig{
+			{Name: "cluster size"},
+			{Name: "nonexistent"},
+			{Dashboard: "alertmanager", PanelID: 118},
+			{Dashboard: "alertmanager", PanelID: 999},
+			{Dashboard: "unknown", PanelID: 1},
+		},
+	}
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Reports = []configPkg.ReportConfig{report}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok-single.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/alertmanager",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/unknown",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	panels, failedPanels := app.ResolveReportPanels(context.Background(), report)
+	require.Equal(t, []types.PanelStruct{
+		{
+			Name:          "Cluster size",
+			DashboardName: "Alertmanager",
+			DashboardID:   "alertmanager",
+			DashboardURL:  "/d/alertmanager/alertmanager",
+			PanelID:       207,
+		},
+		{
+			Name:          "Notifications sent from $instance",
+			DashboardName: "Alertmanager",
+			DashboardID:   "alertmanager",
+			DashboardURL:  "/d/alertmanager/alertmanager",
+			PanelID:       118,
+		},
+	}, panels)
+	require.Len(t, failedPanels, 3)
+	require.Equal(t, types.ReportPanelError{Name: "nonexistent", Error: "panel not found"}, failedPanels[0])
+	require.Equal(t, types.ReportPanelError{Name: "alertmanager/999", Error: "panel not found"}, failedPanels[1])
+	require.Equal(t, "unknown/1", failedPanels[2].Name)
+	require.Contains(t, failedPanels[2].Error, "custom error")
+
+	// The dashboard is fetched once for all panels of it.
+	require.Equal(t, 2, httpmock.GetCallCountInfo()["GET https://example.com/api/dashboards/uid/alertmanager"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppSendReportOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	report := configPkg.ReportConfig{
+		Name:          "report",
+		Schedule:      "0 9 * * *",
+		Chats:         []int64{1, 2},
+		RenderOptions: map[string]string{"from": "now-1d"},
+		Panels: []configPkg.ReportPanelConfig{
+			{Dashboard: "alertmanager", PanelID: 118},
+			{Dashboard: "alertmanager", PanelID: 115},
+		},
+	}
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Reports = []configPkg.ReportConfig{report}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/alertmanager",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/alertmanager/dashboard?from=now-1d&panelId=118",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/alertmanager/dashboard?from=now-1d&panelId=115",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMediaGroup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-media-group-ok.json")),
+	)
+
+	app.SendReport(context.Background(), report)
+
+	require.Equal(t, 2, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMediaGroup"])
+	require.Equal(t, 0, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppSendReportAllPanelsFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	report := configPkg.ReportConfig{
+		Name:     "report",
+		Schedule: "0 9 * * *",
+		Chats:    []int64{1},
+		Panels:   []configPkg.ReportPanelConfig{{Dashboard: "alertmanager", PanelID: 118}},
+	}
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Reports = []configPkg.ReportConfig{report}
+	})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/alertmanager",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/alertmanager/dashboard?panelId=118",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app.SendReport(context.Background(), report)
+
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+	require.Equal(t, 0, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMediaGroup"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppSendReportToChatLongCaption(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Reports = nil
+	})
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMediaGroup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-media-group-ok.json")),
+	)
+
+	err := app.SendReportToChat(1, strings.Repeat("a", MaxCaptionSize+1), [][]byte{
+		assets.GetBytesOrPanic("render.jpeg"),
+		assets.GetBytesOrPanic("render.jpeg"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMediaGroup"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppSendReportToChatError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Reports = nil
+	})
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMediaGroup",
+		httpmock.NewErrorResponder(errors.New("custom error")),
+	)
+
+	err := app.SendReportToChat(1, "caption", [][]byte{assets.GetBytesOrPanic("render.jpeg")})
+	require.Error(t, err)
+}
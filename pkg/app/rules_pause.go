@@ -0,0 +1,221 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"main/pkg/alert_source"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"main/pkg/utils/generic"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) HandlePauseRule(c tele.Context) error {
+	return a.HandleSetRulePausedGeneric(c, true)
+}
+
+func (a *App) HandleResumeRule(c tele.Context) error {
+	return a.HandleSetRulePausedGeneric(c, false)
+}
+
+func (a *App) HandleSetRulePausedGeneric(c tele.Context, paused bool) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Bool("paused", paused).
+		Msg("Got pause/resume rule query")
+
+	command, action := "/resume_rule", "resume"
+	if paused {
+		command, action = "/pause_rule", "pause"
+	}
+
+	args := strings.SplitN(c.Text(), " ", 2)
+	if len(args) != 2 || strings.TrimSpace(args[1]) == "" {
+		return c.Reply(fmt.Sprintf("Usage: %s <alert name>", command))
+	}
+
+	query := strings.TrimSpace(args[1])
+
+	rules, err := a.GetPausableAlertRules(context.Background())
+	if err != nil {
+		return c.Reply(fmt.Sprintf("Error querying alerts: %s", err))
+	}
+
+	results := rules.SearchByName(query)
+	if results.IsAmbiguous() {
+		// Rules that cannot be paused or resumed are not offered, the best match
+		// is used to explain why if none of them can.
+		candidates := generic.Filter(results.Top(constants.SearchCandidatesInOneMessage), func(rule types.GrafanaAlertRuleWithSource) bool {
+			return rule.Rule.UID != "" && rule.Rule.IsPaused != paused
+		})
+
+		if len(candidates) > 0 {
+			return a.ReplyAlertRulePauseChoice(c, candidates, query, paused)
+		}
+	}
+
+	found, ok := results.Best()
+	if !ok {
+		return c.Reply("Could not find Grafana alert rule. See /alerts for alerting rules.")
+	}
+
+	rule := found.Rule
+
+	if rule.UID == "" {
+		return c.Reply("Alert rule UID is unknown, probably your Grafana version does not support pausing rules.")
+	}
+
+	if rule.IsPaused && paused {
+		return c.Reply(fmt.Sprintf("Alert rule %s is already paused.", rule.Name))
+	}
+
+	if !rule.IsPaused && !paused {
+		return c.Reply(fmt.Sprintf("Alert rule %s is not paused.", rule.Name))
+	}
+
+	key := a.SetAlertRulePause(*found, paused)
+
+	menu := &tele.ReplyMarkup{ResizeKeyboard: true}
+	menu.Inline(menu.Row(
+		menu.Data("✅Confirm", constants.PauseRulePrefix, key),
+		menu.Data("❌Cancel", constants.ClearKeyboardPrefix),
+	))
+
+	return c.Reply(fmt.Sprintf("Are you sure you want to %s alert rule %s?", action, rule.Name), menu)
+}
+
+// ReplyAlertRulePauseChoice asks to choose one of the alert rules matching the
+// /pause_rule or /resume_rule query, if none of them matches it clearly better than
+// the others. Choosing a rule pauses or resumes it, so it does not ask to confirm again.
+func (a *App) ReplyAlertRulePauseChoice(
+	c tele.Context,
+	rules []types.GrafanaAlertRuleWithSource,
+	query string,
+	paused bool,
+) error {
+	action := "resume"
+	if paused {
+		action = "pause"
+	}
+
+	menu := GenerateChoiceMenu(
+		rules,
+		func(rule types.GrafanaAlertRuleWithSource) string {
+			return rule.AlertSourceName + " / " + rule.GroupName + " / " + rule.Rule.Name
+		},
+		constants.PauseRulePrefix,
+		func(rule types.GrafanaAlertRuleWithSource, index int) string {
+			return a.SetAlertRulePause(rule, paused)
+		},
+	)
+
+	return c.Reply(fmt.Sprintf("Found multiple alert rules matching \"%s\", choose one to %s:", query, action), menu)
+}
+
+// SetAlertRulePause stores the request to pause or resume the rule in cache
+// until it is confirmed, returning its cache key.
+func (a *App) SetAlertRulePause(rule types.GrafanaAlertRuleWithSource, paused bool) string {
+	rulePause := types.AlertRulePause{
+		AlertSourceName: rule.AlertSourceName,
+		RuleUID:         rule.Rule.UID,
+		RuleName:        rule.Rule.Name,
+		Paused:          paused,
+	}
+
+	return a.Cache.SetObject(constants.RuleToPauseCachePrefix+rulePause.GetHash(), rulePause)
+}
+
+func (a *App) HandleSetRulePausedFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got pause/resume rule callback")
+
+	rulePause := types.AlertRulePause{}
+	if !a.Cache.GetObject(callback.Data, &rulePause) {
+		return c.Reply("Alert rule was not found!")
+	}
+
+	action := "resume"
+	if rulePause.Paused {
+		action = "pause"
+	}
+
+	alertSource, found := a.FindPausableAlertSource(rulePause.AlertSourceName)
+	if !found {
+		return c.Reply("Alert source was not found!")
+	}
+
+	if err := alertSource.SetAlertRulePaused(context.Background(), rulePause.RuleUID, rulePause.Paused); err != nil {
+		return c.Reply(fmt.Sprintf("Error trying to %s alert rule: %s", action, err))
+	}
+
+	a.ClearAllKeyboardCache(c)
+	_ = a.ClearKeyboard(c)
+
+	emoji := "▶️"
+	if rulePause.Paused {
+		emoji = "⏸"
+	}
+
+	return c.Reply(fmt.Sprintf(
+		"%s Alert rule %s is %sd by %s.",
+		emoji,
+		rulePause.RuleName,
+		action,
+		GetUserDisplayName(c.Sender()),
+	))
+}
+
+// GetPausableAlertSources returns enabled alert sources which rules can be paused.
+func (a *App) GetPausableAlertSources() []alert_source.PausableAlertSource {
+	alertSources := make([]alert_source.PausableAlertSource, 0)
+
+	for _, alertSourceWithSilenceManager := range a.AlertSourcesWithSilenceManager {
+		alertSource, ok := alertSourceWithSilenceManager.AlertSource.(alert_source.PausableAlertSource)
+		if ok && alertSource.Enabled() {
+			alertSources = append(alertSources, alertSource)
+		}
+	}
+
+	return alertSources
+}
+
+func (a *App) FindPausableAlertSource(name string) (alert_source.PausableAlertSource, bool) {
+	for _, alertSource := range a.GetPausableAlertSources() {
+		if alertSource.Name() == name {
+			return alertSource, true
+		}
+	}
+
+	return nil, false
+}
+
+// GetPausableAlertRules returns the rules of all alert sources supporting pausing rules,
+// so they can be searched together.
+func (a *App) GetPausableAlertRules(ctx context.Context) (types.GrafanaAlertRulesWithSource, error) {
+	rules := make(types.GrafanaAlertRulesWithSource, 0)
+
+	for _, alertSource := range a.GetPausableAlertSources() {
+		groups, err := alertSource.GetAlertingRules(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups {
+			for _, rule := range group.Rules {
+				rules = append(rules, types.GrafanaAlertRuleWithSource{
+					AlertSourceName:           alertSource.Name(),
+					GrafanaAlertRuleWithGroup: types.GrafanaAlertRuleWithGroup{GroupName: group.Name, Rule: rule},
+				})
+			}
+		}
+	}
+
+	return rules, nil
+}
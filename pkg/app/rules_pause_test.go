@@ -0,0 +1,363 @@
+package app
+
+import (
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"testing"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleInvalidInvocation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Usage: /pause_rule <alert name>"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandlePauseRule(newTestContext(app, "/pause_rule"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleRulesFetchError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error querying alerts: Get \"https://example.com/api/prometheus/grafana/api/v1/rules\": custom error"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandlePauseRule(newTestContext(app, "/pause_rule DiskFull"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Could not find Grafana alert rule. See /alerts for alerting rules."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandlePauseRule(newTestContext(app, "/pause_rule HighLatency"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleNoUID(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Alert rule UID is unknown, probably your Grafana version does not support pausing rules."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandlePauseRule(newTestContext(app, "/pause_rule CosmosNodeNotLatestBinary"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleAlreadyPaused(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Alert rule DiskSlow is already paused."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandlePauseRule(newTestContext(app, "/pause_rule DiskSlow"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppResumeRuleNotPaused(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Alert rule DiskFull is not paused."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleResumeRule(newTestContext(app, "/resume_rule DiskFull"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleAsksForConfirmation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+
+	var keyboard types.TelegramInlineKeyboardResponse
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Are you sure you want to pause alert rule DiskFull?"),
+		captureKeyboard(&keyboard))
+
+	err := app.HandlePauseRule(newTestContext(app, "/pause_rule diskfull"))
+	require.NoError(t, err)
+
+	require.Len(t, keyboard.InlineKeyboard, 1)
+	require.Len(t, keyboard.InlineKeyboard[0], 2)
+	require.Equal(t, "✅Confirm", keyboard.InlineKeyboard[0][0].Text)
+	require.Equal(t, "❌Cancel", keyboard.InlineKeyboard[0][1].Text)
+
+	rulePause := types.AlertRulePause{}
+	key := keyboard.InlineKeyboard[0][0].CallbackData[len("\f"+constants.PauseRulePrefix+"|"):]
+	require.True(t, app.Cache.GetObject(key, &rulePause))
+	require.Equal(t, types.AlertRulePause{
+		AlertSourceName: "Grafana",
+		RuleUID:         "disk-full",
+		RuleName:        "DiskFull",
+		Paused:          true,
+	}, rulePause)
+}
+
+// withStagingGrafanaAlerts adds another Grafana instance used as an alert source, for newTestApp.
+func withStagingGrafanaAlerts(config *configPkg.Config) {
+	config.Grafana = append(config.Grafana, configPkg.GrafanaConfig{
+		Name:   "Staging",
+		URL:    "https://staging.example.com",
+		Alerts: null.BoolFrom(true),
+	})
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleRanksAcrossAlertSources(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts, withStagingGrafanaAlerts)
+
+	// The first alert source only has a worse match, which should not be picked.
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewStringResponder(200, `{"status":"success","data":{"groups":[{"name":"Disks","rules":[
+			{"state":"inactive","name":"DiskFullSoon","type":"alerting","uid":"disk-full-soon","isPaused":false,"alerts":[]}
+		]}]}}`))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://staging.example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+
+	var keyboard types.TelegramInlineKeyboardResponse
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Are you sure you want to pause alert rule DiskFull?"),
+		captureKeyboard(&keyboard))
+
+	err := app.HandlePauseRule(newTestContext(app, "/pause_rule diskfull"))
+	require.NoError(t, err)
+
+	require.Len(t, keyboard.InlineKeyboard, 1)
+
+	rulePause := types.AlertRulePause{}
+	key := keyboard.InlineKeyboard[0][0].CallbackData[len("\f"+constants.PauseRulePrefix+"|"):]
+	require.True(t, app.Cache.GetObject(key, &rulePause))
+	require.Equal(t, "Staging", rulePause.AlertSourceName)
+	require.Equal(t, "disk-full", rulePause.RuleUID)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleAmbiguous(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts, withStagingGrafanaAlerts)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://staging.example.com/api/prometheus/grafana/api/v1/rules",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+
+	grafanaPause := types.AlertRulePause{AlertSourceName: "Grafana", RuleUID: "disk-full", RuleName: "DiskFull", Paused: true}
+	stagingPause := types.AlertRulePause{AlertSourceName: "Staging", RuleUID: "disk-full", RuleName: "DiskFull", Paused: true}
+
+	button := func(text string, rulePause types.AlertRulePause) []types.TelegramInlineKeyboard {
+		return []types.TelegramInlineKeyboard{{
+			Unique:       constants.PauseRulePrefix,
+			Text:         text,
+			CallbackData: "\f" + constants.PauseRulePrefix + "|" + constants.RuleToPauseCachePrefix + rulePause.GetHash(),
+		}}
+	}
+
+	// Choosing the rule pauses it, so there is no separate confirmation.
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasTextAndMarkup(
+			"Found multiple alert rules matching \"DiskFull\", choose one to pause:",
+			types.TelegramInlineKeyboardResponse{InlineKeyboard: [][]types.TelegramInlineKeyboard{
+				button("Grafana / Disks / DiskFull", grafanaPause),
+				button("Staging / Disks / DiskFull", stagingPause),
+				{{
+					Unique:       constants.ClearKeyboardPrefix,
+					Text:         "❌Cancel",
+					CallbackData: "\f" + constants.ClearKeyboardPrefix,
+				}},
+			}},
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandlePauseRule(newTestContext(app, "/pause_rule DiskFull"))
+	require.NoError(t, err)
+
+	rulePause := types.AlertRulePause{}
+	require.True(t, app.Cache.GetObject(constants.RuleToPauseCachePrefix+stagingPause.GetHash(), &rulePause))
+	require.Equal(t, stagingPause, rulePause)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleFromCallbackNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Alert rule was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSetRulePausedFromCallback(newTestCallbackContext(app, constants.PauseRulePrefix, "not-existing", "Are you sure you want to pause alert rule DiskFull?"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleFromCallbackError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	rulePause := types.AlertRulePause{AlertSourceName: "Grafana", RuleUID: "disk-full", RuleName: "DiskFull", Paused: true}
+	key := app.Cache.SetObject(constants.RuleToPauseCachePrefix+rulePause.GetHash(), rulePause)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/v1/provisioning/alert-rules/disk-full",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error trying to pause alert rule: Get \"https://example.com/api/v1/provisioning/alert-rules/disk-full\": custom error"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSetRulePausedFromCallback(newTestCallbackContext(app, constants.PauseRulePrefix, key, "Are you sure you want to pause alert rule DiskFull?"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPauseRuleFromCallbackOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, withGrafanaAlerts)
+
+	rulePause := types.AlertRulePause{AlertSourceName: "Grafana", RuleUID: "disk-full", RuleName: "DiskFull", Paused: true}
+	key := app.Cache.SetObject(constants.RuleToPauseCachePrefix+rulePause.GetHash(), rulePause)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/v1/provisioning/alert-rules/disk-full",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alert-rule-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"PUT",
+		"https://example.com/api/v1/provisioning/alert-rules/disk-full",
+		httpmock.BodyContainsString(`"isPaused":true`),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alert-rule-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("⏸ Alert rule DiskFull is paused by @testuser."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSetRulePausedFromCallback(newTestCallbackContext(app, constants.PauseRulePrefix, key, "Are you sure you want to pause alert rule DiskFull?"))
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup"])
+	require.False(t, app.Cache.GetObject(key, &rulePause))
+}
@@ -0,0 +1,297 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"main/pkg/constants"
+	"main/pkg/silence_manager"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) StartSilenceRemindersWatcher(ctx context.Context) {
+	if a.Config.SilenceReminders == nil {
+		a.Logger.Debug().Msg("Silence reminders are not configured, not starting silence reminders watcher")
+		return
+	}
+
+	a.Logger.Info().
+		Dur("interval", a.Config.SilenceReminders.Interval).
+		Dur("remind_before", a.Config.SilenceReminders.RemindBefore).
+		Msg("Starting silence reminders watcher")
+
+	ticker := time.NewTicker(a.Config.SilenceReminders.Interval)
+	defer ticker.Stop()
+
+	a.CheckSilenceReminders(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.Logger.Info().Msg("Stopping silence reminders watcher")
+			return
+		case <-ticker.C:
+			a.CheckSilenceReminders(ctx)
+		}
+	}
+}
+
+// UpdateTrackedSilences loads tracked silences from cache, lets the callback
+// modify them and saves them back, so concurrent updates are not lost.
+func (a *App) UpdateTrackedSilences(update func(trackedSilences types.TrackedSilences)) {
+	a.TrackedSilencesMutex.Lock()
+	defer a.TrackedSilencesMutex.Unlock()
+
+	trackedSilences := types.TrackedSilences{}
+	a.Cache.GetObject(constants.TrackedSilencesCacheKey, &trackedSilences)
+
+	update(trackedSilences)
+
+	a.Cache.SetObject(constants.TrackedSilencesCacheKey, trackedSilences)
+}
+
+func (a *App) TrackSilence(
+	c tele.Context,
+	silenceManager silence_manager.SilenceManager,
+	silence types.Silence,
+) {
+	if a.Config.SilenceReminders == nil {
+		return
+	}
+
+	message := c.Message()
+	if message == nil || message.Chat == nil {
+		return
+	}
+
+	a.UpdateTrackedSilences(func(trackedSilences types.TrackedSilences) {
+		trackedSilences[silence.ID] = types.TrackedSilence{
+			SilenceID:      silence.ID,
+			SilenceManager: silenceManager.Name(),
+			ChatID:         message.Chat.ID,
+			MessageID:      message.ID,
+			EndsAt:         silence.EndsAt,
+		}
+	})
+}
+
+func (a *App) UntrackSilence(silenceID string) {
+	a.UpdateTrackedSilences(func(trackedSilences types.TrackedSilences) {
+		delete(trackedSilences, silenceID)
+	})
+}
+
+func (a *App) CheckSilenceReminders(ctx context.Context) {
+	now := time.Now()
+	toRemind := make([]types.TrackedSilence, 0)
+
+	a.UpdateTrackedSilences(func(trackedSilences types.TrackedSilences) {
+		for silenceID, trackedSilence := range trackedSilences {
+			if !trackedSilence.EndsAt.After(now) {
+				delete(trackedSilences, silenceID)
+				continue
+			}
+
+			if !trackedSilence.Reminded && trackedSilence.EndsAt.Sub(now) <= a.Config.SilenceReminders.RemindBefore {
+				toRemind = append(toRemind, trackedSilence)
+			}
+		}
+	})
+
+	// Silences are marked as reminded only after the reminder is sent,
+	// so the ones failed to be sent are retried on the next check.
+	for _, trackedSilence := range toRemind {
+		if err := a.SendSilenceReminder(ctx, trackedSilence); err != nil {
+			a.Logger.Error().
+				Err(err).
+				Str("silence_manager", trackedSilence.SilenceManager).
+				Str("silence_id", trackedSilence.SilenceID).
+				Int64("chat_id", trackedSilence.ChatID).
+				Msg("Error sending silence reminder")
+			continue
+		}
+
+		a.UpdateTrackedSilences(func(trackedSilences types.TrackedSilences) {
+			if storedSilence, found := trackedSilences[trackedSilence.SilenceID]; found {
+				storedSilence.Reminded = true
+				trackedSilences[trackedSilence.SilenceID] = storedSilence
+			}
+		})
+	}
+}
+
+func (a *App) SendSilenceReminder(ctx context.Context, trackedSilence types.TrackedSilence) error {
+	silenceManager, found := a.FindSilenceManagerByName(trackedSilence.SilenceManager)
+	if !found {
+		a.Logger.Warn().
+			Str("silence_manager", trackedSilence.SilenceManager).
+			Str("silence_id", trackedSilence.SilenceID).
+			Msg("Silence manager for tracked silence is not found, not sending reminder")
+		a.UntrackSilence(trackedSilence.SilenceID)
+		return nil
+	}
+
+	silence, err := silenceManager.GetSilence(ctx, trackedSilence.SilenceID)
+	if err != nil {
+		return fmt.Errorf("error fetching tracked silence: %w", err)
+	}
+
+	// Silence was deleted or already expired, nothing to remind about.
+	if silence.Status.State != "active" {
+		a.UntrackSilence(trackedSilence.SilenceID)
+		return nil
+	}
+
+	cacheKey := a.Cache.Set(silence.GetHash(), silence.ID)
+
+	menu := &tele.ReplyMarkup{ResizeKeyboard: true}
+	rows := make([]tele.Row, 0)
+
+	// Extending creates a new silence from now, same as in HandleCallbackExtendSilence.
+	now := time.Now()
+	endsAt := silence.EndsAt
+	if endsAt.Before(now) {
+		endsAt = now
+	}
+
+	for _, mute := range silenceManager.GetMutesDurations() {
+		// The reminder is not a reply to anyone's command, so only the durations
+		// anyone who can silence is allowed to use are offered.
+		if duration, err := time.ParseDuration(mute); err == nil &&
+			a.IsSilenceDurationLimited(endsAt.Add(duration).Sub(now).Round(time.Second)) {
+			continue
+		}
+
+		rows = append(rows, menu.Row(menu.Data(
+			fmt.Sprintf("⌛ Extend for %s", mute),
+			silenceManager.Prefixes().ExtendSilence,
+			cacheKey+" "+mute,
+		)))
+	}
+
+	rows = append(rows, menu.Row(menu.Data(
+		"🔕 Let it expire",
+		constants.ClearKeyboardPrefix,
+	)))
+
+	menu.Inline(rows...)
+
+	// The markup is passed in the send options, as telebot overrides the markup
+	// passed separately with the one from the send options.
+	replyTo := &tele.SendOptions{
+		ReplyTo:           &tele.Message{ID: trackedSilence.MessageID, Chat: &tele.Chat{ID: trackedSilence.ChatID}},
+		AllowWithoutReply: true,
+		ReplyMarkup:       menu,
+	}
+
+	return a.SendRender(trackedSilence.ChatID, "silence_reminder", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data: types.SilenceReminderStruct{
+			Silence:        silence,
+			SilenceManager: silenceManager.Name(),
+			RenderTime:     now,
+		},
+	}, replyTo)
+}
+
+func (a *App) HandleCallbackExtendSilence(silenceManager silence_manager.SilenceManager) func(c tele.Context) error {
+	return func(c tele.Context) error {
+		a.Logger.Info().
+			Str("sender", c.Sender().Username).
+			Str("silence_manager", silenceManager.Name()).
+			Str("callback", c.Callback().Data).
+			Msg("Got new extend silence callback via button")
+
+		dataSplit := strings.SplitN(c.Callback().Data, " ", 2)
+		if len(dataSplit) != 2 {
+			return c.Reply("Invalid callback provided!")
+		}
+
+		duration, err := time.ParseDuration(dataSplit[1])
+		if err != nil {
+			return c.Reply("Invalid duration provided!")
+		}
+
+		silenceID, found := a.Cache.Get(dataSplit[0])
+		if !found {
+			return c.Reply("Silence was not found!")
+		}
+
+		previousSilence, err := silenceManager.GetSilence(context.Background(), silenceID)
+		if err != nil {
+			return c.Reply(fmt.Sprintf("Error getting silence to extend: %s", err))
+		}
+
+		if previousSilence.Status.State != "active" {
+			return c.Reply("Silence is not active anymore!")
+		}
+
+		// If the silence has expired meanwhile, extending it from now.
+		endsAt := previousSilence.EndsAt
+		if now := time.Now(); endsAt.Before(now) {
+			endsAt = now
+		}
+
+		silenceInfo := &types.Silence{
+			StartsAt:  time.Now(),
+			EndsAt:    endsAt.Add(duration),
+			Matchers:  previousSilence.Matchers,
+			CreatedBy: previousSilence.CreatedBy,
+			Comment:   previousSilence.Comment,
+		}
+
+		if !a.IsSilenceDurationAllowed(c, silenceInfo.EndsAt.Sub(silenceInfo.StartsAt).Round(time.Second)) {
+			a.LogAccessDenied(c, a.GetRoleFromContext(c)).
+				Str("silence_manager", silenceManager.Name()).
+				Str("silence_id", silenceID).
+				Str("duration", duration.String()).
+				Msg("Silence duration is not allowed")
+
+			return c.Reply(fmt.Sprintf(
+				"Only admins can create silences longer than %s.",
+				a.Config.Telegram.Roles.MaxSilenceDuration,
+			))
+		}
+
+		silenceResponse, err := silenceManager.CreateSilence(context.Background(), *silenceInfo)
+		if err != nil {
+			return c.Reply(fmt.Sprintf("Error extending silence: %s", err))
+		}
+
+		_ = a.ClearKeyboard(c)
+		a.Cache.Delete(dataSplit[0])
+
+		silence, err := silenceManager.GetSilence(context.Background(), silenceResponse.SilenceID)
+		if err != nil {
+			return c.Reply(fmt.Sprintf("Error getting extended silence: %s", err))
+		}
+
+		a.RecordAuditEntry(c, types.AuditActionCreateSilence, silenceManager, silence, silence.EndsAt.Sub(silence.StartsAt).Round(time.Second))
+
+		// The new silence covers the same alerts, so the previous one is not needed anymore.
+		if err := silenceManager.DeleteSilence(context.Background(), previousSilence.ID); err != nil {
+			a.Logger.Error().
+				Err(err).
+				Str("silence_manager", silenceManager.Name()).
+				Str("silence_id", previousSilence.ID).
+				Msg("Error deleting previous silence when extending it")
+		} else {
+			a.RecordAuditEntry(c, types.AuditActionDeleteSilence, silenceManager, previousSilence, 0)
+		}
+
+		a.UntrackSilence(previousSilence.ID)
+		a.TrackSilence(c, silenceManager, silence)
+
+		return a.ReplyRender(c, "silence_extended", render.RenderStruct{
+			Grafana: a.Grafana,
+			Data: types.SilenceExtendedStruct{
+				PreviousSilence: previousSilence,
+				Silence:         silence,
+			},
+		})
+	}
+}
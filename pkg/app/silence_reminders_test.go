@@ -0,0 +1,417 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/constants"
+	"main/pkg/fs"
+	"main/pkg/types"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+)
+
+//nolint:paralleltest // disabled
+func TestAppTrackSilenceDisabled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy"},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			ID:     3,
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/grafana_silence 48h host=test",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	app.TrackSilence(ctx, app.AlertSourcesWithSilenceManager[0].SilenceManager, types.Silence{
+		ID:     "silence",
+		EndsAt: time.Now().Add(time.Hour),
+	})
+
+	_, found := app.Cache.Get(constants.TrackedSilencesCacheKey)
+	require.False(t, found)
+}
+
+//nolint:paralleltest // disabled
+func TestAppCheckSilenceReminders(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy"},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+		SilenceReminders: &configPkg.SilenceRemindersConfig{
+			Interval:     time.Minute,
+			RemindBefore: 30 * time.Minute,
+		},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			ID:     3,
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/grafana_silence 48h host=test",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	silenceManager := app.AlertSourcesWithSilenceManager[0].SilenceManager
+
+	app.TrackSilence(ctx, silenceManager, types.Silence{
+		ID:     "4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		EndsAt: time.Now().Add(10 * time.Minute),
+	})
+	app.TrackSilence(ctx, silenceManager, types.Silence{
+		ID:     "not-expiring-soon",
+		EndsAt: time.Now().Add(time.Hour),
+	})
+	app.TrackSilence(ctx, silenceManager, types.Silence{
+		ID:     "expired",
+		EndsAt: time.Now().Add(-time.Hour),
+	})
+
+	app.CheckSilenceReminders(context.Background())
+
+	trackedSilences := types.TrackedSilences{}
+	require.True(t, app.Cache.GetObject(constants.TrackedSilencesCacheKey, &trackedSilences))
+	require.Len(t, trackedSilences, 2)
+	require.True(t, trackedSilences["4de5faa2-8c0c-4c66-bd31-25c3bf5fa231"].Reminded)
+	require.Equal(t, int64(2), trackedSilences["4de5faa2-8c0c-4c66-bd31-25c3bf5fa231"].ChatID)
+	require.Equal(t, 3, trackedSilences["4de5faa2-8c0c-4c66-bd31-25c3bf5fa231"].MessageID)
+	require.False(t, trackedSilences["not-expiring-soon"].Reminded)
+
+	// Reminders are sent only once.
+	app.CheckSilenceReminders(context.Background())
+
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppCheckSilenceRemindersSendFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{
+			Token: "xxx:yyy",
+			Roles: configPkg.RolesConfig{MaxSilenceDuration: 8 * time.Hour},
+		},
+		Grafana: []configPkg.GrafanaConfig{{
+			URL:            "https://example.com",
+			Silences:       null.BoolFrom(true),
+			MutesDurations: []string{"1h", "8h", "24h"},
+		}},
+		SilenceReminders: &configPkg.SilenceRemindersConfig{
+			Interval:     time.Minute,
+			RemindBefore: 30 * time.Minute,
+		},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	// Only the durations not longer than the max silence duration can be extended for.
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewMatcher("extend buttons", func(req *http.Request) bool {
+			body, _ := io.ReadAll(req.Body)
+			return strings.Contains(string(body), "Extend for 8h") &&
+				!strings.Contains(string(body), "Extend for 24h")
+		}),
+		httpmock.NewErrorResponder(errors.New("custom error")).
+			Times(1).
+			Then(httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json"))),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			ID:     3,
+			Sender: &tele.User{Username: "testuser"},
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	app.TrackSilence(ctx, app.AlertSourcesWithSilenceManager[0].SilenceManager, types.Silence{
+		ID:     "4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		EndsAt: time.Now().Add(10 * time.Minute),
+	})
+
+	app.CheckSilenceReminders(context.Background())
+
+	trackedSilences := types.TrackedSilences{}
+	require.True(t, app.Cache.GetObject(constants.TrackedSilencesCacheKey, &trackedSilences))
+	require.False(t, trackedSilences["4de5faa2-8c0c-4c66-bd31-25c3bf5fa231"].Reminded)
+
+	// The reminder failed to be sent is retried.
+	app.CheckSilenceReminders(context.Background())
+
+	require.True(t, app.Cache.GetObject(constants.TrackedSilencesCacheKey, &trackedSilences))
+	require.True(t, trackedSilences["4de5faa2-8c0c-4c66-bd31-25c3bf5fa231"].Reminded)
+}
+
+//nolint:paralleltest // disabled
+func TestAppCheckSilenceRemindersSilenceExpired(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy"},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+		SilenceReminders: &configPkg.SilenceRemindersConfig{
+			Interval:     time.Minute,
+			RemindBefore: 30 * time.Minute,
+		},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/silence",
+		httpmock.NewStringResponder(200, `{"id":"silence","status":{"state":"expired"}}`))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			ID:     3,
+			Sender: &tele.User{Username: "testuser"},
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	app.TrackSilence(ctx, app.AlertSourcesWithSilenceManager[0].SilenceManager, types.Silence{
+		ID:     "silence",
+		EndsAt: time.Now().Add(10 * time.Minute),
+	})
+
+	app.CheckSilenceReminders(context.Background())
+
+	trackedSilences := types.TrackedSilences{}
+	require.True(t, app.Cache.GetObject(constants.TrackedSilencesCacheKey, &trackedSilences))
+	require.Empty(t, trackedSilences)
+}
+
+//nolint:paralleltest // disabled
+func TestAppExtendSilenceInvalidDuration(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy"},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid duration provided!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\fgrafana_extend_silence_",
+			Data:   "key invalid",
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.HandleCallbackExtendSilence(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppExtendSilenceNotFound(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy"},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Silence was not found!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\fgrafana_extend_silence_",
+			Data:   "key 1h",
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.HandleCallbackExtendSilence(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppExtendSilenceOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy"},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+		SilenceReminders: &configPkg.SilenceRemindersConfig{
+			Interval:     time.Minute,
+			RemindBefore: 30 * time.Minute,
+		},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-create-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/005a07f4-3e6b-4fc1-b97e-6cb928135281",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-extended-ok.json")))
+
+	httpmock.RegisterResponder(
+		"DELETE",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, []byte{}))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasBytes(assets.GetBytesOrPanic("responses/silence-extended-ok.html")),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	app.Cache.Set("key", "4de5faa2-8c0c-4c66-bd31-25c3bf5fa231")
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\fgrafana_extend_silence_",
+			Data:   "key 1h",
+			Message: &tele.Message{
+				ID:     3,
+				Sender: &tele.User{Username: "testuser"},
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.HandleCallbackExtendSilence(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+
+	trackedSilences := types.TrackedSilences{}
+	require.True(t, app.Cache.GetObject(constants.TrackedSilencesCacheKey, &trackedSilences))
+	require.Len(t, trackedSilences, 1)
+	require.Contains(t, trackedSilences, "005a07f4-3e6b-4fc1-b97e-6cb928135281")
+}
@@ -1,8 +1,8 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"main/pkg/alert_source"
 	"main/pkg/constants"
 	"main/pkg/silence_manager"
 	"main/pkg/types"
@@ -38,13 +38,11 @@ func (a *App) HandleNewSilenceViaCommand(silenceManager silence_manager.SilenceM
 
 func (a *App) HandlePrepareNewSilenceFromCallback(
 	silenceManager silence_manager.SilenceManager,
-	alertSource alert_source.AlertSource,
 ) func(c tele.Context) error {
 	return func(c tele.Context) error {
 		a.Logger.Info().
 			Str("sender", c.Sender().Username).
 			Str("silence_manager", silenceManager.Name()).
-			Str("alert_source", alertSource.Name()).
 			Str("callback", c.Callback().Data).
 			Msg("Got new prepare silence callback via button")
 
@@ -66,7 +64,7 @@ func (a *App) HandlePrepareNewSilenceFromCallback(
 
 		var silenceMatchers types.SilenceMatchers = generic.Map(matchers, types.MatcherFromQueryMatcher)
 
-		alerts, err := silenceManager.GetMatchingAlerts(silenceMatchers)
+		alerts, err := silenceManager.GetMatchingAlerts(context.Background(), silenceMatchers)
 		if err != nil {
 			return c.Reply(fmt.Sprintf("Could not fetch alerts matching this silence: %s", err))
 		}
@@ -89,6 +87,10 @@ func (a *App) HandlePrepareNewSilenceFromCallback(
 		}
 
 		for _, mute := range mutesDurations {
+			if duration, err := time.ParseDuration(mute); err == nil && !a.IsSilenceDurationAllowed(c, duration) {
+				continue
+			}
+
 			rows = append(rows, menu.Row(menu.Data(
 				fmt.Sprintf("⌛ Silence for %s", mute),
 				silenceManager.Prefixes().Silence,
@@ -119,13 +121,11 @@ func (a *App) HandlePrepareNewSilenceFromCallback(
 
 func (a *App) HandleCallbackNewSilence(
 	silenceManager silence_manager.SilenceManager,
-	alertSource alert_source.AlertSource,
 ) func(c tele.Context) error {
 	return func(c tele.Context) error {
 		a.Logger.Info().
 			Str("sender", c.Sender().Username).
 			Str("silence_manager", silenceManager.Name()).
-			Str("alert_source", alertSource.Name()).
 			Str("callback", c.Callback().Data).
 			Msg("Got new create silence callback via button")
 
@@ -165,17 +165,38 @@ func (a *App) HandleNewSilenceGeneric(
 	silenceManager silence_manager.SilenceManager,
 	silenceInfo *types.Silence,
 ) error {
-	silenceResponse, silenceErr := silenceManager.CreateSilence(*silenceInfo)
+	// Rounding to avoid false positives as StartsAt and EndsAt are taken at slightly different moments.
+	duration := silenceInfo.EndsAt.Sub(silenceInfo.StartsAt).Round(time.Second)
+	if !a.IsSilenceDurationAllowed(c, duration) {
+		a.LogAccessDenied(c, a.GetRoleFromContext(c)).
+			Str("silence_manager", silenceManager.Name()).
+			Str("duration", duration.String()).
+			Msg("Silence duration is not allowed")
+
+		return c.Reply(fmt.Sprintf(
+			"Only admins can create silences longer than %s.",
+			a.Config.Telegram.Roles.MaxSilenceDuration,
+		))
+	}
+
+	silenceResponse, silenceErr := silenceManager.CreateSilence(context.Background(), *silenceInfo)
 	if silenceErr != nil {
 		return c.Reply(fmt.Sprintf("Error creating silence: %s", silenceErr))
 	}
 
-	silence, silenceErr := silenceManager.GetSilence(silenceResponse.SilenceID)
+	createdSilence := *silenceInfo
+	createdSilence.ID = silenceResponse.SilenceID
+	a.RecordAuditEntry(c, types.AuditActionCreateSilence, silenceManager, createdSilence, duration)
+	a.AnnotateSilence(c, silenceManager, createdSilence, duration)
+
+	silence, silenceErr := silenceManager.GetSilence(context.Background(), silenceResponse.SilenceID)
 	if silenceErr != nil {
 		return c.Reply(fmt.Sprintf("Error getting created silence: %s", silenceErr))
 	}
 
-	alerts, alertsErr := silenceManager.GetMatchingAlerts(silence.Matchers)
+	a.TrackSilence(c, silenceManager, silence)
+
+	alerts, alertsErr := silenceManager.GetMatchingAlerts(context.Background(), silence.Matchers)
 	if alertsErr != nil {
 		return c.Reply(fmt.Sprintf("Error getting alerts for silence: %s", alertsErr))
 	}
@@ -187,7 +208,7 @@ func (a *App) HandleNewSilenceGeneric(
 	)), menu.Row(menu.Data(
 		"❌Unsilence",
 		silenceManager.Prefixes().Unsilence,
-		silence.ID+" 1",
+		a.Cache.Set(silence.GetHash(), silence.ID)+" 1",
 	)))
 
 	return a.ReplyRender(c, "silences_create", render.RenderStruct{
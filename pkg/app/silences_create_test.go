@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"main/assets"
 	configPkg "main/pkg/config"
-	"main/pkg/constants"
 	"main/pkg/fs"
 	"main/pkg/types"
+	"net/http"
 	"testing"
 
 	"github.com/guregu/null/v5"
@@ -25,7 +25,7 @@ func TestAppCreateSilenceSilenceManagerDisabled(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -65,7 +65,7 @@ func TestAppCreateSilenceInvalidInvocation(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -105,7 +105,7 @@ func TestAppCreateSilenceErrorCreatingSilence(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -141,6 +141,52 @@ func TestAppCreateSilenceErrorCreatingSilence(t *testing.T) {
 	require.NoError(t, err)
 }
 
+//nolint:paralleltest // disabled
+func TestAppCreateSilenceUpstreamErrorMessage(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewStringResponder(400, `{"message":"invalid silence: invalid label matcher 0: invalid label name"}`))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText(
+			"Error creating silence: Post \"https://example.com/api/alertmanager/grafana/api/v2/silences\": "+
+				"400 Bad Request: invalid silence: invalid label matcher 0: invalid label name",
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/grafana_silence 48h host=test",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleNewSilenceViaCommand(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
 //nolint:paralleltest // disabled
 func TestAppCreateSilenceErrorGettingSilence(t *testing.T) {
 	httpmock.Activate()
@@ -150,7 +196,7 @@ func TestAppCreateSilenceErrorGettingSilence(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -200,7 +246,7 @@ func TestAppCreateSilenceErrorGettingSilenceAlerts(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -255,7 +301,7 @@ func TestAppCreateSilenceOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -293,7 +339,7 @@ func TestAppCreateSilenceOk(t *testing.T) {
 				{{
 					Unique:       "grafana_unsilence_",
 					Text:         "❌Unsilence",
-					CallbackData: "\fgrafana_unsilence_|4de5faa2-8c0c-4c66-bd31-25c3bf5fa231 1",
+					CallbackData: "\fgrafana_unsilence_|" + types.Silence{ID: "4de5faa2-8c0c-4c66-bd31-25c3bf5fa231"}.GetHash() + " 1",
 				}},
 			},
 		}),
@@ -314,6 +360,138 @@ func TestAppCreateSilenceOk(t *testing.T) {
 	require.NoError(t, err)
 }
 
+//nolint:paralleltest // disabled
+func TestAppCreateSilenceWithAnnotationOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:    "Etc/GMT",
+		Log:         configPkg.LogConfig{LogLevel: "info"},
+		Telegram:    configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:     []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+		Annotations: &configPkg.AnnotationsConfig{Silences: true, SilenceTags: []string{"silence"}},
+	}
+
+	annotationsCalls := 0
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-create-silence-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/annotations",
+		httpmock.BodyContainsString(`"text":"Silence created in Grafana by @testuser for 2 days: host = test"`).
+			And(httpmock.BodyContainsString(`"tags":["silence"]`)),
+		func(req *http.Request) (*http.Response, error) {
+			annotationsCalls++
+			return httpmock.NewStringResponder(200, `{"id":1,"message":"Annotation added"}`)(req)
+		})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/005a07f4-3e6b-4fc1-b97e-6cb928135281",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/alerts?filter=network%3D%22neutron%22&filter=alertname%3D%22CosmosNodeNotLatestBinary%22&silenced=true&inhibited=true&active=true",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-alerts.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/grafana_silence 48h host=test",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleNewSilenceViaCommand(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, annotationsCalls)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppCreateSilenceWithAnnotationFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone:    "Etc/GMT",
+		Log:         configPkg.LogConfig{LogLevel: "info"},
+		Telegram:    configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:     []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+		Annotations: &configPkg.AnnotationsConfig{Silences: true, SilenceTags: []string{"silence"}},
+	}
+
+	annotationsCalls := 0
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-create-silence-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/annotations",
+		httpmock.BodyContainsString(`"text":"Silence created in Grafana by @testuser for 2 days: host = test"`).
+			And(httpmock.BodyContainsString(`"tags":["silence"]`)),
+		func(req *http.Request) (*http.Response, error) {
+			annotationsCalls++
+			return httpmock.NewErrorResponder(errors.New("custom error"))(req)
+		})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/005a07f4-3e6b-4fc1-b97e-6cb928135281",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/alerts?filter=network%3D%22neutron%22&filter=alertname%3D%22CosmosNodeNotLatestBinary%22&silenced=true&inhibited=true&active=true",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-alerts.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/grafana_silence 48h host=test",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleNewSilenceViaCommand(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, annotationsCalls)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+}
+
 //nolint:paralleltest // disabled
 func TestAppPrepareSilenceViaCallbackAlertNotFound(t *testing.T) {
 	httpmock.Activate()
@@ -323,7 +501,7 @@ func TestAppPrepareSilenceViaCallbackAlertNotFound(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -355,7 +533,7 @@ func TestAppPrepareSilenceViaCallbackAlertNotFound(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   "123",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -367,7 +545,6 @@ func TestAppPrepareSilenceViaCallbackAlertNotFound(t *testing.T) {
 
 	err := app.HandlePrepareNewSilenceFromCallback(
 		app.AlertSourcesWithSilenceManager[0].SilenceManager,
-		app.AlertSourcesWithSilenceManager[0].AlertSource,
 	)(ctx)
 	require.NoError(t, err)
 }
@@ -381,11 +558,11 @@ func TestAppPrepareSilenceViaCallbackFailedToFetchMatchingAlerts(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:            "https://example.com",
 			Silences:       null.BoolFrom(true),
 			MutesDurations: []string{"1h", "3h"},
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -421,7 +598,7 @@ func TestAppPrepareSilenceViaCallbackFailedToFetchMatchingAlerts(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   key,
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -433,7 +610,6 @@ func TestAppPrepareSilenceViaCallbackFailedToFetchMatchingAlerts(t *testing.T) {
 
 	err := app.HandlePrepareNewSilenceFromCallback(
 		app.AlertSourcesWithSilenceManager[0].SilenceManager,
-		app.AlertSourcesWithSilenceManager[0].AlertSource,
 	)(ctx)
 	require.NoError(t, err)
 }
@@ -447,11 +623,11 @@ func TestAppPrepareSilenceViaCallbackOk(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:            "https://example.com",
 			Silences:       null.BoolFrom(true),
 			MutesDurations: []string{"1h", "3h"},
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -521,7 +697,7 @@ func TestAppPrepareSilenceViaCallbackOk(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   key,
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -533,7 +709,6 @@ func TestAppPrepareSilenceViaCallbackOk(t *testing.T) {
 
 	err := app.HandlePrepareNewSilenceFromCallback(
 		app.AlertSourcesWithSilenceManager[0].SilenceManager,
-		app.AlertSourcesWithSilenceManager[0].AlertSource,
 	)(ctx)
 	require.NoError(t, err)
 }
@@ -547,11 +722,11 @@ func TestAppPrepareSilenceViaCallbackOkWithEditKeyboard(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:            "https://example.com",
 			Silences:       null.BoolFrom(true),
 			MutesDurations: []string{"1h", "3h"},
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -621,7 +796,7 @@ func TestAppPrepareSilenceViaCallbackOkWithEditKeyboard(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   key + " 1",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -633,7 +808,6 @@ func TestAppPrepareSilenceViaCallbackOkWithEditKeyboard(t *testing.T) {
 
 	err := app.HandlePrepareNewSilenceFromCallback(
 		app.AlertSourcesWithSilenceManager[0].SilenceManager,
-		app.AlertSourcesWithSilenceManager[0].AlertSource,
 	)(ctx)
 	require.NoError(t, err)
 }
@@ -647,7 +821,7 @@ func TestAppCreateSilenceViaCallbackInvalidPayload(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -674,7 +848,7 @@ func TestAppCreateSilenceViaCallbackInvalidPayload(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   "123",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -686,7 +860,6 @@ func TestAppCreateSilenceViaCallbackInvalidPayload(t *testing.T) {
 
 	err := app.HandleCallbackNewSilence(
 		app.AlertSourcesWithSilenceManager[0].SilenceManager,
-		app.AlertSourcesWithSilenceManager[0].AlertSource,
 	)(ctx)
 	require.NoError(t, err)
 }
@@ -700,7 +873,7 @@ func TestAppCreateSilenceViaCallbackInvalidDuration(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -732,7 +905,7 @@ func TestAppCreateSilenceViaCallbackInvalidDuration(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   "invalid 123",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -744,7 +917,6 @@ func TestAppCreateSilenceViaCallbackInvalidDuration(t *testing.T) {
 
 	err := app.HandleCallbackNewSilence(
 		app.AlertSourcesWithSilenceManager[0].SilenceManager,
-		app.AlertSourcesWithSilenceManager[0].AlertSource,
 	)(ctx)
 	require.NoError(t, err)
 }
@@ -758,7 +930,7 @@ func TestAppCreateSilenceViaCallbackAlertNotFound(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -790,7 +962,7 @@ func TestAppCreateSilenceViaCallbackAlertNotFound(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   "123 48h",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -802,7 +974,6 @@ func TestAppCreateSilenceViaCallbackAlertNotFound(t *testing.T) {
 
 	err := app.HandleCallbackNewSilence(
 		app.AlertSourcesWithSilenceManager[0].SilenceManager,
-		app.AlertSourcesWithSilenceManager[0].AlertSource,
 	)(ctx)
 	require.NoError(t, err)
 }
@@ -816,7 +987,7 @@ func TestAppCreateSilenceViaCallbackOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -851,7 +1022,7 @@ func TestAppCreateSilenceViaCallbackOk(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   key + " 48h",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -863,7 +1034,6 @@ func TestAppCreateSilenceViaCallbackOk(t *testing.T) {
 
 	err := app.HandleCallbackNewSilence(
 		app.AlertSourcesWithSilenceManager[0].SilenceManager,
-		app.AlertSourcesWithSilenceManager[0].AlertSource,
 	)(ctx)
 	require.NoError(t, err)
 }
@@ -1,8 +1,10 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/silence_manager"
+	"main/pkg/types"
 	"main/pkg/types/render"
 	"strings"
 
@@ -47,7 +49,13 @@ func (a *App) HandleCallbackDeleteSilence(silenceManager silence_manager.Silence
 			a.RemoveKeyboardItemByCallback(c, callback)
 		}
 
-		return a.HandleDeleteSilenceGeneric(c, silenceManager, dataSplit[0])
+		// Buttons sent before silence IDs were passed via cache have the silence ID itself.
+		silenceID, found := a.Cache.Get(dataSplit[0])
+		if !found {
+			silenceID = dataSplit[0]
+		}
+
+		return a.HandleDeleteSilenceGeneric(c, silenceManager, silenceID)
 	}
 }
 
@@ -56,7 +64,7 @@ func (a *App) HandleDeleteSilenceGeneric(
 	silenceManager silence_manager.SilenceManager,
 	silenceID string,
 ) error {
-	silences, silencesFetchErr := silenceManager.GetSilences()
+	silences, silencesFetchErr := silenceManager.GetSilences(context.Background())
 	if silencesFetchErr != nil {
 		return c.Reply(fmt.Sprintf("Error getting silence to delete: %s", silencesFetchErr))
 	}
@@ -70,11 +78,14 @@ func (a *App) HandleDeleteSilenceGeneric(
 		return c.Reply("Silence is already deleted!")
 	}
 
-	silenceErr := silenceManager.DeleteSilence(silence.ID)
+	silenceErr := silenceManager.DeleteSilence(context.Background(), silence.ID)
 	if silenceErr != nil {
 		return c.Reply(fmt.Sprintf("Error deleting silence: %s", silenceErr))
 	}
 
+	a.RecordAuditEntry(c, types.AuditActionDeleteSilence, silenceManager, *silence, 0)
+	a.UntrackSilence(silence.ID)
+
 	return a.ReplyRender(c, "silences_delete", render.RenderStruct{
 		Grafana: a.Grafana,
 		Data:    silence,
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"main/assets"
 	configPkg "main/pkg/config"
-	"main/pkg/constants"
 	"main/pkg/fs"
 	"main/pkg/types"
 	"testing"
@@ -24,10 +23,10 @@ func TestAppDeleteSilenceSilenceManagerDisabled(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:      "https://example.com",
 			Silences: null.BoolFrom(false),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -67,10 +66,10 @@ func TestAppDeleteSilenceInvalidInvocation(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:      "https://example.com",
 			Silences: null.BoolFrom(true),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -110,10 +109,10 @@ func TestAppDeleteSilenceFetchSilencesFailed(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:      "https://example.com",
 			Silences: null.BoolFrom(true),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -158,10 +157,10 @@ func TestAppDeleteSilenceSilenceNotFound(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:      "https://example.com",
 			Silences: null.BoolFrom(true),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -206,10 +205,10 @@ func TestAppDeleteSilenceSilenceExpired(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:      "https://example.com",
 			Silences: null.BoolFrom(true),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -254,10 +253,10 @@ func TestAppDeleteSilenceErrorDeletingSilence(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:      "https://example.com",
 			Silences: null.BoolFrom(true),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -307,10 +306,10 @@ func TestAppDeleteSilenceOk(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:      "https://example.com",
 			Silences: null.BoolFrom(true),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -360,10 +359,10 @@ func TestAppDeleteSilenceCallbackOk(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:      "https://example.com",
 			Silences: null.BoolFrom(true),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -395,7 +394,7 @@ func TestAppDeleteSilenceCallbackOk(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaUnsilencePrefix,
+			Unique: "\fgrafana_unsilence_",
 			Data:   "123",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -418,10 +417,10 @@ func TestAppDeleteSilenceCallbackOkWithDeleteKeyboard(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:      "https://example.com",
 			Silences: null.BoolFrom(true),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -453,7 +452,7 @@ func TestAppDeleteSilenceCallbackOkWithDeleteKeyboard(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaUnsilencePrefix,
+			Unique: "\fgrafana_unsilence_",
 			Data:   "123 1",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -466,3 +465,44 @@ func TestAppDeleteSilenceCallbackOkWithDeleteKeyboard(t *testing.T) {
 	err := app.HandleCallbackDeleteSilence(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
 	require.NoError(t, err)
 }
+
+//nolint:paralleltest // disabled
+func TestAppDeleteSilenceCallbackCachedSilenceID(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Grafana[0].Silences = null.BoolFrom(true)
+	})
+
+	// The silence ID is too long to fit into callback data with a long prefix, so it is cached.
+	silence := types.Silence{ID: "4de5faa2-8c0c-4c66-bd31-25c3bf5fa231"}
+	key := app.Cache.Set(silence.GetHash(), silence.ID)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silences-ok.json")))
+
+	httpmock.RegisterResponder(
+		"DELETE",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, []byte("")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasBytes(assets.GetBytesOrPanic("responses/silence-delete-ok.html")),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	silenceManager := app.AlertSourcesWithSilenceManager[0].SilenceManager
+	err := app.HandleCallbackDeleteSilence(silenceManager)(newTestCallbackContext(app, silenceManager.Prefixes().Unsilence, key+" 1", "Silence created"))
+	require.NoError(t, err)
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["DELETE https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231"])
+}
@@ -0,0 +1,252 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"main/pkg/constants"
+	"main/pkg/silence_manager"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"main/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) HandleEditSilenceViaCommand(silenceManager silence_manager.SilenceManager) func(c tele.Context) error {
+	return func(c tele.Context) error {
+		a.Logger.Info().
+			Str("sender", c.Sender().Username).
+			Str("text", c.Text()).
+			Str("silence_manager", silenceManager.Name()).
+			Msg("Got new edit silence query via command")
+
+		if !silenceManager.Enabled() {
+			return c.Reply(silenceManager.Name() + " is disabled.")
+		}
+
+		args := strings.SplitN(c.Text(), " ", 3)
+		if len(args) <= 2 {
+			return c.Reply(fmt.Sprintf(
+				"Usage: %s <silence ID> [duration=<duration>] [comment=<comment>] [params]",
+				args[0],
+			))
+		}
+
+		silence, err := a.GetSilenceToEdit(silenceManager, args[1])
+		if err != "" {
+			return c.Reply(err)
+		}
+
+		editedSilence, err := utils.ApplySilenceEdit(args[0], silence, args[2])
+		if err != "" {
+			return c.Reply(err)
+		}
+
+		return a.HandleEditSilenceGeneric(c, silenceManager, silence, editedSilence)
+	}
+}
+
+func (a *App) HandlePrepareEditSilenceFromCallback(silenceManager silence_manager.SilenceManager) func(c tele.Context) error {
+	return func(c tele.Context) error {
+		a.Logger.Info().
+			Str("sender", c.Sender().Username).
+			Str("silence_manager", silenceManager.Name()).
+			Str("callback", c.Callback().Data).
+			Msg("Got new prepare edit silence callback via button")
+
+		silenceID, found := a.Cache.Get(c.Callback().Data)
+		if !found {
+			return c.Reply("Silence was not found!")
+		}
+
+		silence, err := a.GetSilenceToEdit(silenceManager, silenceID)
+		if err != "" {
+			return c.Reply(err)
+		}
+
+		alerts, alertsErr := silenceManager.GetMatchingAlerts(context.Background(), silence.Matchers)
+		if alertsErr != nil {
+			return c.Reply(fmt.Sprintf("Could not fetch alerts matching this silence: %s", alertsErr))
+		}
+
+		prefixes := silenceManager.Prefixes()
+		cacheKey := c.Callback().Data
+
+		menu := &tele.ReplyMarkup{ResizeKeyboard: true}
+		rows := make([]tele.Row, 0)
+
+		for _, mute := range silenceManager.GetMutesDurations() {
+			if duration, err := time.ParseDuration(mute); err == nil && !a.IsSilenceDurationAllowed(c, duration) {
+				continue
+			}
+
+			rows = append(rows, menu.Row(menu.Data(
+				fmt.Sprintf("⌛ End in %s", mute),
+				prefixes.EditSilence,
+				cacheKey+" duration "+mute,
+			)))
+		}
+
+		if len(silence.Matchers) > 1 {
+			for index, matcher := range silence.Matchers {
+				rows = append(rows, menu.Row(menu.Data(
+					fmt.Sprintf("❌ Remove %s", matcher.Serialize()),
+					prefixes.EditSilence,
+					cacheKey+" remove "+strconv.Itoa(index),
+				)))
+			}
+		}
+
+		rows = append(rows, menu.Row(menu.Data(
+			"🚫 Cancel",
+			constants.ClearKeyboardPrefix,
+		)))
+
+		menu.Inline(rows...)
+
+		return a.ReplyRender(c, "silence_prepare_edit", render.RenderStruct{
+			Grafana: a.Grafana,
+			Data: types.SilencePrepareEditStruct{
+				Silence:     silence,
+				Command:     prefixes.EditSilenceCommand,
+				AlertsCount: len(alerts),
+			},
+		}, menu)
+	}
+}
+
+func (a *App) HandleCallbackEditSilence(silenceManager silence_manager.SilenceManager) func(c tele.Context) error {
+	return func(c tele.Context) error {
+		a.Logger.Info().
+			Str("sender", c.Sender().Username).
+			Str("silence_manager", silenceManager.Name()).
+			Str("callback", c.Callback().Data).
+			Msg("Got new edit silence callback via button")
+
+		dataSplit := strings.SplitN(c.Callback().Data, " ", 3)
+		if len(dataSplit) != 3 {
+			return c.Reply("Invalid callback provided!")
+		}
+
+		silenceID, found := a.Cache.Get(dataSplit[0])
+		if !found {
+			return c.Reply("Silence was not found!")
+		}
+
+		silence, err := a.GetSilenceToEdit(silenceManager, silenceID)
+		if err != "" {
+			return c.Reply(err)
+		}
+
+		editedSilence := silence
+		editedSilence.Status = types.SilenceStatus{}
+
+		switch dataSplit[1] {
+		case "duration":
+			duration, durationErr := time.ParseDuration(dataSplit[2])
+			if durationErr != nil || duration <= 0 {
+				return c.Reply("Invalid duration provided!")
+			}
+
+			editedSilence.EndsAt = time.Now().Add(duration)
+		case "remove":
+			index, indexErr := strconv.Atoi(dataSplit[2])
+			if indexErr != nil || index < 0 || index >= len(silence.Matchers) || len(silence.Matchers) <= 1 {
+				return c.Reply("Invalid matcher provided!")
+			}
+
+			editedSilence.Matchers = make(types.SilenceMatchers, 0, len(silence.Matchers)-1)
+			editedSilence.Matchers = append(editedSilence.Matchers, silence.Matchers[:index]...)
+			editedSilence.Matchers = append(editedSilence.Matchers, silence.Matchers[index+1:]...)
+		default:
+			return c.Reply("Invalid callback provided!")
+		}
+
+		_ = a.ClearKeyboard(c)
+		a.Cache.Delete(dataSplit[0])
+
+		return a.HandleEditSilenceGeneric(c, silenceManager, silence, &editedSilence)
+	}
+}
+
+// GetSilenceToEdit fetches a silence by its ID, returning an error text
+// to reply with if it cannot be edited.
+func (a *App) GetSilenceToEdit(
+	silenceManager silence_manager.SilenceManager,
+	silenceID string,
+) (types.Silence, string) {
+	silence, err := silenceManager.GetSilence(context.Background(), silenceID)
+	if err != nil {
+		return silence, fmt.Sprintf("Error getting silence to edit: %s", err)
+	}
+
+	if silence.Status.State == "expired" {
+		return silence, "Silence is already expired!"
+	}
+
+	return silence, ""
+}
+
+func (a *App) HandleEditSilenceGeneric(
+	c tele.Context,
+	silenceManager silence_manager.SilenceManager,
+	previousSilence types.Silence,
+	silenceInfo *types.Silence,
+) error {
+	// Pending silences are counted from their start, active ones from now.
+	startsAt := time.Now()
+	if silenceInfo.StartsAt.After(startsAt) {
+		startsAt = silenceInfo.StartsAt
+	}
+
+	duration := silenceInfo.EndsAt.Sub(startsAt).Round(time.Second)
+	if !a.IsSilenceDurationAllowed(c, duration) {
+		a.LogAccessDenied(c, a.GetRoleFromContext(c)).
+			Str("silence_manager", silenceManager.Name()).
+			Str("silence_id", previousSilence.ID).
+			Str("duration", duration.String()).
+			Msg("Silence duration is not allowed")
+
+		return c.Reply(fmt.Sprintf(
+			"Only admins can create silences longer than %s.",
+			a.Config.Telegram.Roles.MaxSilenceDuration,
+		))
+	}
+
+	// Posting a silence with an existing ID updates it. Alertmanager might expire
+	// the existing silence and create a new one instead, if matchers were changed.
+	silenceResponse, silenceErr := silenceManager.CreateSilence(context.Background(), *silenceInfo)
+	if silenceErr != nil {
+		return c.Reply(fmt.Sprintf("Error editing silence: %s", silenceErr))
+	}
+
+	silence, silenceErr := silenceManager.GetSilence(context.Background(), silenceResponse.SilenceID)
+	if silenceErr != nil {
+		return c.Reply(fmt.Sprintf("Error getting edited silence: %s", silenceErr))
+	}
+
+	a.RecordAuditEntry(c, types.AuditActionEditSilence, silenceManager, silence, duration)
+
+	if silence.ID != previousSilence.ID {
+		a.UntrackSilence(previousSilence.ID)
+	}
+
+	a.TrackSilence(c, silenceManager, silence)
+
+	alerts, alertsErr := silenceManager.GetMatchingAlerts(context.Background(), silence.Matchers)
+	if alertsErr != nil {
+		return c.Reply(fmt.Sprintf("Error getting alerts for silence: %s", alertsErr))
+	}
+
+	return a.ReplyRender(c, "silences_edit", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data: types.SilenceEditStruct{
+			PreviousSilence: previousSilence,
+			Silence:         silence,
+			Alerts:          alerts,
+		},
+	})
+}
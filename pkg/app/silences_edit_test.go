@@ -0,0 +1,406 @@
+package app
+
+import (
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/fs"
+	"main/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+)
+
+//nolint:paralleltest // disabled
+func TestAppEditSilenceInvalidInvocation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Usage: /grafana_edit_silence <silence ID> [duration=<duration>] [comment=<comment>] [params]"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/grafana_edit_silence 4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleEditSilenceViaCommand(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppEditSilenceExpired(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/silence",
+		httpmock.NewStringResponder(200, `{"id":"silence","status":{"state":"expired"}}`))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Silence is already expired!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/grafana_edit_silence silence duration=2h",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleEditSilenceViaCommand(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppEditSilenceNotAllowed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{
+			Token: "xxx:yyy",
+			Roles: configPkg.RolesConfig{
+				Silencers:          configPkg.RoleConfig{Chats: []int64{2}},
+				MaxSilenceDuration: time.Hour,
+			},
+		},
+		Grafana: []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Only admins can create silences longer than 1h0m0s."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{ID: 3, Username: "testuser"},
+			Text:   "/grafana_edit_silence 4de5faa2-8c0c-4c66-bd31-25c3bf5fa231 duration=48h",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleEditSilenceViaCommand(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppEditSilenceOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-create-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/005a07f4-3e6b-4fc1-b97e-6cb928135281",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-extended-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/alerts?filter=network%3D%22neutron%22&filter=alertname%3D%22CosmosNodeNotLatestBinary%22&silenced=true&inhibited=true&active=true",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-alerts.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasBytes(assets.GetBytesOrPanic("responses/silence-edit-ok.html")),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   "/grafana_edit_silence 4de5faa2-8c0c-4c66-bd31-25c3bf5fa231 duration=72h",
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+
+	err := app.HandleEditSilenceViaCommand(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppPrepareEditSilenceViaCallbackOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana: []configPkg.GrafanaConfig{{
+			URL:            "https://example.com",
+			Silences:       null.BoolFrom(true),
+			MutesDurations: []string{"1h"},
+		}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/alerts?filter=network%3D%22neutron%22&filter=alertname%3D%22CosmosNodeNotLatestBinary%22&silenced=true&inhibited=true&active=true",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-alerts.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasBytesAndMarkup(assets.GetBytesOrPanic("responses/silence-prepare-edit-ok.html"), types.TelegramInlineKeyboardResponse{
+			InlineKeyboard: [][]types.TelegramInlineKeyboard{
+				{{
+					Unique:       "grafana_edit_silence_",
+					Text:         "⌛ End in 1h",
+					CallbackData: "\fgrafana_edit_silence_|key duration 1h",
+				}},
+				{{
+					Unique:       "grafana_edit_silence_",
+					Text:         "❌ Remove network = neutron",
+					CallbackData: "\fgrafana_edit_silence_|key remove 0",
+				}},
+				{{
+					Unique:       "grafana_edit_silence_",
+					Text:         "❌ Remove alertname = CosmosNodeNotLatestBinary",
+					CallbackData: "\fgrafana_edit_silence_|key remove 1",
+				}},
+				{{
+					Unique:       "clear_keyboard_",
+					Text:         "🚫 Cancel",
+					CallbackData: "\fclear_keyboard_",
+				}},
+			},
+		}),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	app.Cache.Set("key", "4de5faa2-8c0c-4c66-bd31-25c3bf5fa231")
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\fgrafana_prepare_edit_silence_",
+			Data:   "key",
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.HandlePrepareEditSilenceFromCallback(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppEditSilenceViaCallbackInvalidMatcher(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Invalid matcher provided!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	app.Cache.Set("key", "4de5faa2-8c0c-4c66-bd31-25c3bf5fa231")
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\fgrafana_edit_silence_",
+			Data:   "key remove 5",
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.HandleCallbackEditSilence(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppEditSilenceViaCallbackOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/4de5faa2-8c0c-4c66-bd31-25c3bf5fa231",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.BodyContainsString(`"matchers":[{"isEqual":true,"isRegex":false,"name":"alertname","value":"CosmosNodeNotLatestBinary"}]`),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-create-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silence/005a07f4-3e6b-4fc1-b97e-6cb928135281",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-extended-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/alerts?filter=network%3D%22neutron%22&filter=alertname%3D%22CosmosNodeNotLatestBinary%22&silenced=true&inhibited=true&active=true",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-alerts.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasBytes(assets.GetBytesOrPanic("responses/silence-edit-ok.html")),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+	app.Cache.Set("key", "4de5faa2-8c0c-4c66-bd31-25c3bf5fa231")
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\fgrafana_edit_silence_",
+			Data:   "key remove 0",
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Chat:   &tele.Chat{ID: 2},
+			},
+		},
+	})
+
+	err := app.HandleCallbackEditSilence(app.AlertSourcesWithSilenceManager[0].SilenceManager)(ctx)
+	require.NoError(t, err)
+
+	_, found := app.Cache.Get("key")
+	require.False(t, found)
+}
@@ -1,12 +1,12 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/constants"
 	"main/pkg/silence_manager"
 	"main/pkg/types"
 	"main/pkg/types/render"
-	"main/pkg/utils/generic"
 	"strconv"
 
 	tele "gopkg.in/telebot.v3"
@@ -18,9 +18,7 @@ func (a *App) HandleChooseSilenceManagerForListSilences(c tele.Context) error {
 		Str("text", c.Text()).
 		Msg("Got choosing a datasource for list silences query")
 
-	silenceManagers := generic.Filter(a.AlertSourcesWithSilenceManager, func(a AlertSourceWithSilenceManager) bool {
-		return a.SilenceManager.Enabled()
-	})
+	silenceManagers := a.GetSilenceManagers()
 
 	if len(silenceManagers) == 0 {
 		return a.BotReply(c, "No silence managers configured!")
@@ -29,7 +27,7 @@ func (a *App) HandleChooseSilenceManagerForListSilences(c tele.Context) error {
 	if len(silenceManagers) == 1 {
 		return a.HandleListSilencesWithPagination(
 			c,
-			silenceManagers[0].SilenceManager,
+			silenceManagers[0],
 			0,
 			false,
 		)
@@ -39,10 +37,10 @@ func (a *App) HandleChooseSilenceManagerForListSilences(c tele.Context) error {
 	rows := make([]tele.Row, 0)
 	index := 0
 
-	for _, source := range silenceManagers {
+	for _, silenceManager := range silenceManagers {
 		button := menu.Data(
-			source.SilenceManager.Name(),
-			source.SilenceManager.Prefixes().PaginatedSilencesList,
+			silenceManager.Name(),
+			silenceManager.Prefixes().PaginatedSilencesList,
 			"0", // page
 		)
 
@@ -97,6 +95,7 @@ func (a *App) HandleListSilencesWithPagination(
 	}
 
 	silencesWithAlerts, totalPages, totalCount, err := silence_manager.GetSilencesWithAlerts(
+		context.Background(),
 		silenceManager,
 		page,
 		constants.SilencesInOneMessage,
@@ -117,16 +116,27 @@ func (a *App) HandleListSilencesWithPagination(
 
 	prefixes := silenceManager.Prefixes()
 
-	menu := GenerateMenuWithPagination(
+	menu := GenerateMenuWithPaginationAndButtons(
 		silencesWithAlerts,
-		func(elt types.SilenceWithAlerts, index int) string {
-			return fmt.Sprintf("❌Unsilence %s", elt.Silence.ID)
+		func(menu *tele.ReplyMarkup, elt types.SilenceWithAlerts, index int) []tele.Btn {
+			return []tele.Btn{
+				menu.Data(
+					fmt.Sprintf("❌Unsilence %s", elt.Silence.ID),
+					prefixes.Unsilence,
+					a.Cache.Set(elt.Silence.GetHash(), elt.Silence.ID),
+				),
+				menu.Data(
+					"✏️Edit",
+					prefixes.PrepareEditSilence,
+					a.Cache.Set(elt.Silence.GetHash(), elt.Silence.ID),
+				),
+			}
 		},
-		prefixes.Unsilence,
-		func(elt types.SilenceWithAlerts) string { return elt.Silence.ID },
 		prefixes.PaginatedSilencesList,
 		page,
 		totalPages,
+		DefaultPrevPagePrefix,
+		DefaultNextPagePrefix,
 	)
 
 	if editPrevious {
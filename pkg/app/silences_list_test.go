@@ -7,6 +7,7 @@ import (
 	"main/pkg/constants"
 	"main/pkg/fs"
 	"main/pkg/types"
+	"strings"
 	"testing"
 
 	"github.com/guregu/null/v5"
@@ -24,10 +25,10 @@ func TestAppSilencesListNoSilenceManagers(t *testing.T) {
 		Timezone: "Etc/GMT",
 		Log:      configPkg.LogConfig{LogLevel: "info"},
 		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana: configPkg.GrafanaConfig{
+		Grafana: []configPkg.GrafanaConfig{{
 			URL:    "https://example.com",
 			Alerts: null.BoolFrom(false),
-		},
+		}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -67,9 +68,9 @@ func TestAppSilencesMultipleSilenceManagers(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(true)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -127,9 +128,9 @@ func TestAppSilencesSilenceManagerFail(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -172,9 +173,9 @@ func TestAppSilencesLastPage(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -209,7 +210,7 @@ func TestAppSilencesLastPage(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.PrometheusPaginatedFiringAlertsList,
+			Unique: "\fprometheus_paginated_firing_alerts_list_",
 			Data:   "1",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -232,9 +233,9 @@ func TestAppSilencesListFirstPage(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -281,9 +282,9 @@ func TestAppListSilencesAlertInvalidCallback(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -308,7 +309,7 @@ func TestAppListSilencesAlertInvalidCallback(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.AlertmanagerPaginatedSilencesList,
+			Unique: "\falertmanager_paginated_silences_list_",
 			Data:   "not-a-number",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -331,7 +332,7 @@ func TestAppListSilenceManagerSilenceManagerDisabled(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Silences: null.BoolFrom(false)},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(false)}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -358,7 +359,7 @@ func TestAppListSilenceManagerSilenceManagerDisabled(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.AlertmanagerPaginatedSilencesList,
+			Unique: "\falertmanager_paginated_silences_list_",
 			Data:   "0",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -381,9 +382,9 @@ func TestAppListSilencesNoSilences(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", Alerts: null.BoolFrom(false)},
-		Alertmanager: &configPkg.AlertmanagerConfig{URL: "http://alertmanager.com"},
-		Prometheus:   &configPkg.PrometheusConfig{URL: "https://prometheus.com"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Alerts: null.BoolFrom(false)}},
+		Alertmanager: []configPkg.AlertmanagerConfig{{URL: "http://alertmanager.com"}},
+		Prometheus:   []configPkg.PrometheusConfig{{URL: "https://prometheus.com"}},
 	}
 
 	httpmock.RegisterResponder(
@@ -413,7 +414,7 @@ func TestAppListSilencesNoSilences(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.AlertmanagerPaginatedSilencesList,
+			Unique: "\falertmanager_paginated_silences_list_",
 			Data:   "0",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -426,3 +427,84 @@ func TestAppListSilencesNoSilences(t *testing.T) {
 	err := app.HandleListSilences(app.AlertSourcesWithSilenceManager[1].SilenceManager)(ctx)
 	require.NoError(t, err)
 }
+
+//nolint:paralleltest // disabled
+func TestAppSilenceButtonsCallbackDataLength(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// The longest allowed name, which is a part of all the silence buttons prefixes.
+	name := strings.Repeat("a", configPkg.MaxNameLength)
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Grafana[0].Name = name
+		config.Grafana[0].Silences = null.BoolFrom(true)
+		config.Grafana[0].MutesDurations = []string{"1h", "8h", "24h", "168h", "99999h"}
+	})
+
+	silenceManager := app.AlertSourcesWithSilenceManager[0].SilenceManager
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silences-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/alertmanager/grafana/api/v2/silences",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-create-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"=~^https://example.com/api/alertmanager/grafana/api/v2/silence/",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"=~^https://example.com/api/alertmanager/grafana/api/v2/alerts",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-alerts.json")))
+
+	var keyboard types.TelegramInlineKeyboardResponse
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		captureKeyboard(&keyboard))
+
+	requireCallbackDataFits := func(prefix string) string {
+		var callbackData string
+
+		for _, row := range keyboard.InlineKeyboard {
+			for _, button := range row {
+				require.LessOrEqual(t, len(button.CallbackData), constants.MaxCallbackDataLength, button.CallbackData)
+
+				if button.Unique == prefix {
+					callbackData = button.CallbackData
+				}
+			}
+		}
+
+		require.NotEmpty(t, callbackData, "no button with prefix %s", prefix)
+		return callbackData[len("\f"+prefix+"|"):]
+	}
+
+	// Silences list, with unsilence, edit and pagination buttons.
+	err := app.HandleListSilences(silenceManager)(newTestContext(app, "/"+name+"_silences"))
+	require.NoError(t, err)
+
+	requireCallbackDataFits(silenceManager.Prefixes().Unsilence)
+	requireCallbackDataFits(silenceManager.Prefixes().PaginatedSilencesList)
+	editKey := requireCallbackDataFits(silenceManager.Prefixes().PrepareEditSilence)
+
+	// Created silence, with the unsilence button.
+	err = app.HandleNewSilenceViaCommand(silenceManager)(newTestContext(app, "/"+name+"_silence 48h host=test"))
+	require.NoError(t, err)
+
+	requireCallbackDataFits(silenceManager.Prefixes().Unsilence)
+
+	// Editing the silence, with the duration and matchers buttons.
+	err = app.HandlePrepareEditSilenceFromCallback(silenceManager)(newTestCallbackContext(app, silenceManager.Prefixes().PrepareEditSilence, editKey, "Silences"))
+	require.NoError(t, err)
+
+	requireCallbackDataFits(silenceManager.Prefixes().EditSilence)
+}
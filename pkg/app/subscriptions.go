@@ -0,0 +1,166 @@
+package app
+
+import (
+	"fmt"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) HandleSubscribe(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got subscribe query")
+
+	// Subscribed chats are notified both by the alerts watcher and by the webhook.
+	if a.Config.Notifications == nil && a.Config.Webhook == nil {
+		return c.Reply("Alerts notifications and webhook are not configured, so subscriptions are not available.")
+	}
+
+	args := strings.SplitN(c.Text(), " ", 2)
+	if len(args) != 2 || strings.TrimSpace(args[1]) == "" {
+		return c.Reply("Usage: /subscribe <matchers>, like /subscribe team=payments severity=~critical|warning")
+	}
+
+	matchers := types.QueryMatcherFromKeyValueString(args[1])
+	if err := matchers.Compile(); err != nil {
+		return c.Reply(fmt.Sprintf("Error parsing matchers: %s", err))
+	}
+
+	added := false
+
+	a.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+		added = subscriptions.Add(c.Chat().ID, matchers)
+	})
+
+	if !added {
+		return c.Reply("This chat is already subscribed to these alerts!")
+	}
+
+	return c.Reply(fmt.Sprintf("Subscribed to alerts matching %s.", matchers.ToQueryString()))
+}
+
+func (a *App) HandleUnsubscribe(c tele.Context) error {
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("text", c.Text()).
+		Msg("Got unsubscribe query")
+
+	args := strings.SplitN(c.Text(), " ", 2)
+	if len(args) != 2 || strings.TrimSpace(args[1]) == "" {
+		return a.HandleListSubscriptions(c)
+	}
+
+	if strings.TrimSpace(args[1]) == "all" {
+		a.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+			delete(subscriptions, c.Chat().ID)
+		})
+
+		return c.Reply("Unsubscribed from all alerts.")
+	}
+
+	matchers := types.QueryMatcherFromKeyValueString(args[1])
+
+	return a.HandleUnsubscribeGeneric(c, matchers.GetHash())
+}
+
+func (a *App) HandleUnsubscribeFromCallback(c tele.Context) error {
+	callback := c.Callback()
+
+	a.Logger.Info().
+		Str("sender", c.Sender().Username).
+		Str("data", callback.Data).
+		Msg("Got unsubscribe callback")
+
+	a.RemoveKeyboardItemByCallback(c, callback)
+
+	return a.HandleUnsubscribeGeneric(c, callback.Data)
+}
+
+func (a *App) HandleUnsubscribeGeneric(c tele.Context, hash string) error {
+	var (
+		matchers types.QueryMatchers
+		removed  bool
+	)
+
+	a.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+		matchers, removed = subscriptions.Find(c.Chat().ID, hash)
+		subscriptions.Remove(c.Chat().ID, hash)
+	})
+
+	if !removed {
+		return c.Reply("This chat is not subscribed to these alerts!")
+	}
+
+	return c.Reply(fmt.Sprintf("Unsubscribed from alerts matching %s.", matchers.ToQueryString()))
+}
+
+// HandleListSubscriptions shows the chat subscriptions, with buttons to remove them.
+func (a *App) HandleListSubscriptions(c tele.Context) error {
+	subscriptions := a.GetSubscriptions()[c.Chat().ID]
+
+	menu := &tele.ReplyMarkup{ResizeKeyboard: true}
+	rows := make([]tele.Row, len(subscriptions))
+
+	for index, matchers := range subscriptions {
+		rows[index] = menu.Row(menu.Data(
+			"❌ "+matchers.ToQueryString(),
+			constants.UnsubscribePrefix,
+			matchers.GetHash(),
+		))
+	}
+
+	menu.Inline(rows...)
+
+	return a.ReplyRender(c, "subscriptions_list", render.RenderStruct{
+		Grafana: a.Grafana,
+		Data:    types.SubscriptionsListStruct{Subscriptions: subscriptions},
+	}, menu)
+}
+
+func (a *App) GetSubscriptions() types.Subscriptions {
+	a.SubscriptionsMutex.Lock()
+	defer a.SubscriptionsMutex.Unlock()
+
+	return a.loadSubscriptionsUnsafe().Clone()
+}
+
+// UpdateSubscriptions lets the callback modify the subscriptions and saves them
+// to cache, so concurrent updates are not lost.
+func (a *App) UpdateSubscriptions(update func(subscriptions types.Subscriptions)) {
+	a.SubscriptionsMutex.Lock()
+	defer a.SubscriptionsMutex.Unlock()
+
+	update(a.loadSubscriptionsUnsafe())
+
+	a.Cache.SetObject(constants.SubscriptionsCacheKey, a.Subscriptions)
+}
+
+// loadSubscriptionsUnsafe loads subscriptions from cache once and compiles their matchers.
+// Should be called with SubscriptionsMutex locked.
+func (a *App) loadSubscriptionsUnsafe() types.Subscriptions {
+	if a.Subscriptions != nil {
+		return a.Subscriptions
+	}
+
+	a.Subscriptions = types.Subscriptions{}
+	a.Cache.GetObject(constants.SubscriptionsCacheKey, &a.Subscriptions)
+
+	for chatID, subscriptions := range a.Subscriptions {
+		for _, matchers := range subscriptions {
+			if err := matchers.Compile(); err != nil {
+				a.Logger.Warn().
+					Err(err).
+					Int64("chat_id", chatID).
+					Str("matchers", matchers.ToQueryString()).
+					Msg("Error compiling subscription matchers")
+			}
+		}
+	}
+
+	return a.Subscriptions
+}
@@ -0,0 +1,309 @@
+package app
+
+import (
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/constants"
+	"main/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	tele "gopkg.in/telebot.v3"
+)
+
+//nolint:paralleltest // disabled
+func TestAppSubscribeNotificationsDisabled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = nil
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Alerts notifications and webhook are not configured, so subscriptions are not available."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSubscribe(newTestContext(app, "/subscribe team=payments"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppSubscribeInvalidInvocation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Usage: /subscribe <matchers>, like /subscribe team=payments severity=~critical|warning"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSubscribe(newTestContext(app, "/subscribe"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppSubscribeInvalidRegex(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Error parsing matchers: invalid regex in matcher team =~ (: error parsing regexp: missing closing ): `(`"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSubscribe(newTestContext(app, "/subscribe team=~("))
+	require.NoError(t, err)
+	require.Empty(t, app.GetSubscriptions())
+}
+
+//nolint:paralleltest // disabled
+func TestAppSubscribeOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Subscribed to alerts matching severity=~critical|warning team=payments."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSubscribe(newTestContext(app, "/subscribe team=payments severity=~critical|warning"))
+	require.NoError(t, err)
+
+	subscriptions := app.GetSubscriptions()
+	require.Len(t, subscriptions[2], 1)
+	require.Equal(t, "severity=~critical|warning team=payments", subscriptions[2][0].ToQueryString())
+}
+
+//nolint:paralleltest // disabled
+func TestAppSubscribeWebhookOnlyOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = nil
+		config.Webhook = &configPkg.WebhookConfig{ListenAddress: ":9500"}
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Subscribed to alerts matching team=payments."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSubscribe(newTestContext(app, "/subscribe team=payments"))
+	require.NoError(t, err)
+	require.Len(t, app.GetSubscriptions()[2], 1)
+}
+
+//nolint:paralleltest // disabled
+func TestAppSubscribeAlreadySubscribed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+	app.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+		subscriptions.Add(2, types.QueryMatcherFromKeyValueString("team=payments"))
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("This chat is already subscribed to these alerts!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleSubscribe(newTestContext(app, "/subscribe team=payments"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppUnsubscribeListEmpty(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("This chat has no alerts subscriptions. Use /subscribe to add one."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleUnsubscribe(newTestContext(app, "/unsubscribe"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppUnsubscribeList(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+	app.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+		subscriptions.Add(2, types.QueryMatcherFromKeyValueString("team=payments"))
+		subscriptions.Add(2, types.QueryMatcherFromKeyValueString("team=infra"))
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasTextAndMarkup(
+			"🔔 <strong>Alerts subscriptions in this chat:</strong>\n- <code>team=payments</code>\n- <code>team=infra</code>",
+			types.TelegramInlineKeyboardResponse{
+				InlineKeyboard: [][]types.TelegramInlineKeyboard{
+					{{Unique: constants.UnsubscribePrefix, Text: "❌ team=payments", CallbackData: "\f" + constants.UnsubscribePrefix + "|" + types.QueryMatcherFromKeyValueString("team=payments").GetHash()}},
+					{{Unique: constants.UnsubscribePrefix, Text: "❌ team=infra", CallbackData: "\f" + constants.UnsubscribePrefix + "|" + types.QueryMatcherFromKeyValueString("team=infra").GetHash()}},
+				},
+			},
+		),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleUnsubscribe(newTestContext(app, "/unsubscribe"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppUnsubscribeAll(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+	app.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+		subscriptions.Add(1, types.QueryMatcherFromKeyValueString("team=payments"))
+		subscriptions.Add(2, types.QueryMatcherFromKeyValueString("team=payments"))
+		subscriptions.Add(2, types.QueryMatcherFromKeyValueString("team=infra"))
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Unsubscribed from all alerts."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleUnsubscribe(newTestContext(app, "/unsubscribe all"))
+	require.NoError(t, err)
+
+	subscriptions := app.GetSubscriptions()
+	require.Len(t, subscriptions, 1)
+	require.Len(t, subscriptions[1], 1)
+}
+
+//nolint:paralleltest // disabled
+func TestAppUnsubscribeNotSubscribed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("This chat is not subscribed to these alerts!"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleUnsubscribe(newTestContext(app, "/unsubscribe team=payments"))
+	require.NoError(t, err)
+}
+
+//nolint:paralleltest // disabled
+func TestAppUnsubscribeOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+	app.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+		subscriptions.Add(2, types.QueryMatcherFromKeyValueString("team=payments severity=critical"))
+	})
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Unsubscribed from alerts matching severity=critical team=payments."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	err := app.HandleUnsubscribe(newTestContext(app, "/unsubscribe severity=critical team=payments"))
+	require.NoError(t, err)
+	require.Empty(t, app.GetSubscriptions())
+}
+
+//nolint:paralleltest // disabled
+func TestAppUnsubscribeFromCallbackOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	app := newTestApp(t, func(config *configPkg.Config) {
+		config.Notifications = &configPkg.NotificationsConfig{Interval: time.Minute}
+	})
+
+	matchers := types.QueryMatcherFromKeyValueString("team=payments")
+	app.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+		subscriptions.Add(2, matchers)
+	})
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/editMessageReplyMarkup",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasText("Unsubscribed from alerts matching team=payments."),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	ctx := app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\f" + constants.UnsubscribePrefix,
+			Data:   matchers.GetHash(),
+			Message: &tele.Message{
+				Sender: &tele.User{Username: "testuser"},
+				Text:   "/unsubscribe",
+				Chat:   &tele.Chat{ID: 2},
+				ReplyMarkup: &tele.ReplyMarkup{
+					InlineKeyboard: [][]tele.InlineButton{{{
+						Unique: constants.UnsubscribePrefix,
+						Text:   "❌ team=payments",
+						Data:   "\f" + constants.UnsubscribePrefix + "|" + matchers.GetHash(),
+					}}},
+				},
+			},
+		},
+	})
+
+	err := app.HandleUnsubscribeFromCallback(ctx)
+	require.NoError(t, err)
+	require.Empty(t, app.GetSubscriptions())
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"encoding/json"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/fs"
+	"main/pkg/types"
+	"net/http"
+	"testing"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	tele "gopkg.in/telebot.v3"
+)
+
+// newTestApp creates the app with a minimal config having one Grafana instance, changed
+// by the passed functions, if any, and mocks the Telegram request done on startup.
+// httpmock should be activated before calling it.
+func newTestApp(t *testing.T, configure ...func(config *configPkg.Config)) *App {
+	t.Helper()
+
+	config := &configPkg.Config{
+		Timezone: "Etc/GMT",
+		Log:      configPkg.LogConfig{LogLevel: "info"},
+		Telegram: configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:  []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
+	}
+
+	for _, f := range configure {
+		f(config)
+	}
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	return NewApp(config, &fs.TestFS{}, "1.2.3")
+}
+
+// newTestContext returns the context of a command sent to the bot.
+func newTestContext(app *App, text string) tele.Context {
+	return app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Message: &tele.Message{
+			Sender: &tele.User{Username: "testuser"},
+			Text:   text,
+			Chat:   &tele.Chat{ID: 2},
+		},
+	})
+}
+
+// newTestCallbackContext returns the context of pressing the button with the given
+// callback prefix and data, attached to the bot message with the given text.
+func newTestCallbackContext(app *App, prefix, data, text string) tele.Context {
+	return app.Bot.NewContext(tele.Update{
+		ID: 1,
+		Callback: &tele.Callback{
+			Sender: &tele.User{Username: "testuser"},
+			Unique: "\f" + prefix,
+			Data:   data,
+			Message: &tele.Message{
+				ID:     3,
+				Sender: &tele.User{Username: "testuser"},
+				Text:   text,
+				Chat:   &tele.Chat{ID: 2},
+				ReplyMarkup: &tele.ReplyMarkup{
+					InlineKeyboard: [][]tele.InlineButton{{{
+						Unique: prefix,
+						Text:   "Button",
+						Data:   "\f" + prefix + "|" + data,
+					}}},
+				},
+			},
+		},
+	})
+}
+
+// withGrafanaAlerts enables using Grafana as an alert source, for newTestApp.
+func withGrafanaAlerts(config *configPkg.Config) {
+	config.Grafana[0].Alerts = null.BoolFrom(true)
+}
+
+// captureKeyboard returns a responder for sent Telegram messages, storing
+// the inline keyboard of the message, as Telegram receives it, into keyboard.
+func captureKeyboard(keyboard *types.TelegramInlineKeyboardResponse) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		var response types.TelegramResponse
+		if err := json.NewDecoder(req.Body).Decode(&response); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(response.ReplyMarkup), keyboard); err != nil {
+			return nil, err
+		}
+
+		return httpmock.NewBytesResponse(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")), nil
+	}
+}
@@ -1,9 +1,13 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"main/pkg/constants"
+	"main/pkg/silence_manager"
 	"main/pkg/types"
 	"main/pkg/types/render"
+	"main/pkg/utils/generic"
 	"strconv"
 	"strings"
 
@@ -86,11 +90,11 @@ func (a *App) ClearKeyboard(c tele.Context) error {
 	return nil
 }
 
-func (a *App) GetAllAlertingRules() (types.GrafanaAlertGroups, error) {
+func (a *App) GetAllAlertingRules(ctx context.Context) (types.GrafanaAlertGroups, error) {
 	rules := make(types.GrafanaAlertGroups, 0)
 
 	for _, alertSource := range a.AlertSourcesWithSilenceManager {
-		alertSourceRules, err := alertSource.AlertSource.GetAlertingRules()
+		alertSourceRules, err := alertSource.AlertSource.GetAlertingRules(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -101,6 +105,25 @@ func (a *App) GetAllAlertingRules() (types.GrafanaAlertGroups, error) {
 	return rules, nil
 }
 
+// GetSilenceManagers returns all enabled silence managers.
+func (a *App) GetSilenceManagers() []silence_manager.SilenceManager {
+	return generic.Filter(a.SilenceManagers, func(silenceManager silence_manager.SilenceManager) bool {
+		return silenceManager.Enabled()
+	})
+}
+
+// FindSilenceManagerByName returns an enabled silence manager with the given name,
+// or the first enabled silence manager if the name is empty.
+func (a *App) FindSilenceManagerByName(name string) (silence_manager.SilenceManager, bool) {
+	for _, silenceManager := range a.GetSilenceManagers() {
+		if name == "" || silenceManager.Name() == name {
+			return silenceManager, true
+		}
+	}
+
+	return nil, false
+}
+
 func (a *App) ReplyRender(
 	c tele.Context,
 	templateName string,
@@ -116,6 +139,21 @@ func (a *App) ReplyRender(
 	return a.BotReply(c, template, opts...)
 }
 
+func (a *App) SendRender(
+	chatID int64,
+	templateName string,
+	renderStruct render.RenderStruct,
+	opts ...interface{},
+) error {
+	template, err := a.TemplateManager.Render(templateName, renderStruct)
+	if err != nil {
+		a.Logger.Error().Str("template", templateName).Err(err).Msg("Error rendering template")
+		return err
+	}
+
+	return a.BotSend(chatID, template, opts...)
+}
+
 func (a *App) EditRender(
 	c tele.Context,
 	templateName string,
@@ -179,19 +217,53 @@ func GenerateMenuWithPaginationAndPagePrefix[T any](
 	pagesTotal int,
 	prevPagePrefix func(int) string,
 	nextPagePrefix func(int) string,
+) *tele.ReplyMarkup {
+	// If elementCallback is nil, only pagination buttons are generated,
+	// for lists where elements have no actions.
+	var elementButtons func(*tele.ReplyMarkup, T, int) []tele.Btn
+	if elementCallback != nil {
+		elementButtons = func(menu *tele.ReplyMarkup, element T, index int) []tele.Btn {
+			return []tele.Btn{menu.Data(
+				textCallback(element, index),
+				elementPrefix,
+				elementCallback(element),
+			)}
+		}
+	}
+
+	return GenerateMenuWithPaginationAndButtons(
+		chunk,
+		elementButtons,
+		paginationPrefix,
+		page,
+		pagesTotal,
+		prevPagePrefix,
+		nextPagePrefix,
+	)
+}
+
+// GenerateMenuWithPaginationAndButtons generates a menu with a row of buttons
+// returned by elementButtons for each element, for lists where elements
+// have more than one action.
+func GenerateMenuWithPaginationAndButtons[T any](
+	chunk []T,
+	elementButtons func(*tele.ReplyMarkup, T, int) []tele.Btn,
+	paginationPrefix string,
+	page int,
+	pagesTotal int,
+	prevPagePrefix func(int) string,
+	nextPagePrefix func(int) string,
 ) *tele.ReplyMarkup {
 	menu := &tele.ReplyMarkup{ResizeKeyboard: true}
 
 	rows := make([]tele.Row, 0)
 
 	for index, element := range chunk {
-		button := menu.Data(
-			textCallback(element, index),
-			elementPrefix,
-			elementCallback(element),
-		)
+		if elementButtons == nil {
+			break
+		}
 
-		rows = append(rows, menu.Row(button))
+		rows = append(rows, menu.Row(elementButtons(menu, element, index)...))
 	}
 
 	if len(chunk) > 0 {
@@ -220,3 +292,29 @@ func GenerateMenuWithPaginationAndPagePrefix[T any](
 	menu.Inline(rows...)
 	return menu
 }
+
+// GenerateChoiceMenu generates a keyboard with a button for each element, for choosing
+// one of the multiple search results, and a button to cancel the choice.
+func GenerateChoiceMenu[T any](
+	elements []T,
+	textCallback func(T) string,
+	elementPrefix string,
+	elementCallback func(T, int) string,
+) *tele.ReplyMarkup {
+	menu := &tele.ReplyMarkup{ResizeKeyboard: true}
+
+	rows := make([]tele.Row, 0, len(elements)+1)
+
+	for index, element := range elements {
+		rows = append(rows, menu.Row(menu.Data(
+			textCallback(element),
+			elementPrefix,
+			elementCallback(element, index),
+		)))
+	}
+
+	rows = append(rows, menu.Row(menu.Data("❌Cancel", constants.ClearKeyboardPrefix)))
+
+	menu.Inline(rows...)
+	return menu
+}
@@ -24,7 +24,7 @@ func TestAppReplyRenderFailedToRender(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -64,7 +64,7 @@ func TestAppReplyRenderFailedToSend(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -103,7 +103,7 @@ func TestAppEditRenderFailedToRender(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -142,7 +142,7 @@ func TestAppEditRenderFailedToSend(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -167,7 +167,7 @@ func TestAppEditRenderFailedToSend(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   "48h 123",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -177,8 +177,8 @@ func TestAppEditRenderFailedToSend(t *testing.T) {
 					{
 						{
 							Text:   "text",
-							Unique: "\f" + constants.GrafanaSilencePrefix,
-							Data:   constants.GrafanaSilencePrefix + "|48h 123",
+							Unique: "\fgrafana_silence_",
+							Data:   "grafana_silence_|48h 123",
 						},
 					},
 				}},
@@ -186,7 +186,7 @@ func TestAppEditRenderFailedToSend(t *testing.T) {
 		},
 	})
 
-	err := app.EditRender(ctx, "help", render.RenderStruct{})
+	err := app.EditRender(ctx, "help", render.RenderStruct{Data: types.HelpStruct{}})
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 }
@@ -200,7 +200,7 @@ func TestAppRemoveKeyboardItemFailedToDelete(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -219,8 +219,8 @@ func TestAppRemoveKeyboardItemFailedToDelete(t *testing.T) {
 				InlineKeyboard: [][]types.TelegramInlineKeyboard{{
 					{
 						Text:         "text",
-						Unique:       constants.GrafanaUnsilencePrefix,
-						CallbackData: "\f" + constants.GrafanaUnsilencePrefix + "|random",
+						Unique:       "grafana_unsilence_",
+						CallbackData: "\fgrafana_unsilence_" + "|random",
 					},
 				}},
 			},
@@ -238,7 +238,7 @@ func TestAppRemoveKeyboardItemFailedToDelete(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   "48h 123",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -248,12 +248,12 @@ func TestAppRemoveKeyboardItemFailedToDelete(t *testing.T) {
 					{
 						{
 							Text:   "text",
-							Unique: "\f" + constants.GrafanaSilencePrefix,
-							Data:   constants.GrafanaSilencePrefix + "|48h 123",
+							Unique: "\fgrafana_silence_",
+							Data:   "grafana_silence_|48h 123",
 						},
 						{
 							Text:   "text",
-							Unique: constants.GrafanaUnsilencePrefix,
+							Unique: "grafana_unsilence_",
 							Data:   "random",
 						},
 					},
@@ -274,7 +274,7 @@ func TestAppClearKeyboardFailedToDelete(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -300,7 +300,7 @@ func TestAppClearKeyboardFailedToDelete(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   "48h 123",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -310,12 +310,12 @@ func TestAppClearKeyboardFailedToDelete(t *testing.T) {
 					{
 						{
 							Text:   "text",
-							Unique: "\f" + constants.GrafanaSilencePrefix,
-							Data:   constants.GrafanaSilencePrefix + "|48h 123",
+							Unique: "\fgrafana_silence_",
+							Data:   "grafana_silence_|48h 123",
 						},
 						{
 							Text:   "text",
-							Unique: constants.GrafanaUnsilencePrefix,
+							Unique: "grafana_unsilence_",
 							Data:   "random",
 						},
 					},
@@ -338,7 +338,7 @@ func TestAppClearKeyboardOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -363,7 +363,7 @@ func TestAppClearKeyboardOk(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   "48h 123",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -373,12 +373,12 @@ func TestAppClearKeyboardOk(t *testing.T) {
 					{
 						{
 							Text:   "text",
-							Unique: "\f" + constants.GrafanaSilencePrefix,
-							Data:   constants.GrafanaSilencePrefix + "|48h 123",
+							Unique: "\fgrafana_silence_",
+							Data:   "grafana_silence_|48h 123",
 						},
 						{
 							Text:   "text",
-							Unique: constants.GrafanaUnsilencePrefix,
+							Unique: "grafana_unsilence_",
 							Data:   "random",
 						},
 					},
@@ -400,7 +400,7 @@ func TestAppClearAllKeyboardCacheOk(t *testing.T) {
 		Timezone:     "Etc/GMT",
 		Log:          configPkg.LogConfig{LogLevel: "info"},
 		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
-		Grafana:      configPkg.GrafanaConfig{URL: "https://example.com", User: "admin", Password: "admin"},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", User: "admin", Password: "admin"}},
 		Alertmanager: nil,
 		Prometheus:   nil,
 	}
@@ -425,7 +425,7 @@ func TestAppClearAllKeyboardCacheOk(t *testing.T) {
 		},
 		Callback: &tele.Callback{
 			Sender: &tele.User{Username: "testuser"},
-			Unique: "\f" + constants.GrafanaSilencePrefix,
+			Unique: "\fgrafana_silence_",
 			Data:   "48h 123",
 			Message: &tele.Message{
 				Sender: &tele.User{Username: "testuser"},
@@ -435,23 +435,23 @@ func TestAppClearAllKeyboardCacheOk(t *testing.T) {
 					{
 						{
 							Text:   "CacheItem1",
-							Unique: "\f" + constants.GrafanaSilencePrefix,
-							Data:   constants.GrafanaSilencePrefix + "|123", // should be removed
+							Unique: "\fgrafana_silence_",
+							Data:   "grafana_silence_|123", // should be removed
 						},
 						{
 							Text:   "CacheItem2",
-							Unique: "\f" + constants.GrafanaSilencePrefix,
-							Data:   constants.GrafanaSilencePrefix + "|", // invalid, not removed
+							Unique: "\fgrafana_silence_",
+							Data:   "grafana_silence_|", // invalid, not removed
 						},
 						{
 							Text:   "CacheItem3",
-							Unique: "\f" + constants.GrafanaSilencePrefix,
+							Unique: "\fgrafana_silence_",
 							Data:   "", // invalid, not removed
 						},
 						{
 							Text:   "CacheItem1",
-							Unique: "\f" + constants.GrafanaSilencePrefix,
-							Data:   constants.GrafanaSilencePrefix + "|456", // not in cache, not removed
+							Unique: "\fgrafana_silence_",
+							Data:   "grafana_silence_|456", // not in cache, not removed
 						},
 					},
 				}},
@@ -469,3 +469,111 @@ func TestAppClearAllKeyboardCacheOk(t *testing.T) {
 	require.True(t, found)
 	require.Equal(t, "789", item)
 }
+
+//nolint:paralleltest // disabled
+func TestAppChoiceMenusCallbackDataLength(t *testing.T) {
+	dashboardsSearch := func() {
+		httpmock.RegisterResponder(
+			"GET",
+			"https://example.com/api/search?type=dash-db",
+			httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
+	}
+
+	testCases := []struct {
+		name    string
+		text    string
+		options []func(config *configPkg.Config)
+		mock    func()
+		handler func(app *App) tele.HandlerFunc
+	}{
+		{
+			name: "render panel",
+			text: "/render number of instances",
+			mock: func() {
+				httpmock.RegisterResponder(
+					"GET",
+					"https://example.com/api/search?type=dash-db",
+					httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok-single.json")))
+				httpmock.RegisterResponder(
+					"GET",
+					"https://example.com/api/dashboards/uid/alertmanager",
+					httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+			},
+			handler: func(app *App) tele.HandlerFunc { return app.HandleRenderPanel },
+		},
+		{
+			name:    "render dashboard",
+			text:    "/render_dashboard unifi poller",
+			mock:    dashboardsSearch,
+			handler: func(app *App) tele.HandlerFunc { return app.HandleRenderDashboard },
+		},
+		{
+			name:    "show dashboard",
+			text:    "/dashboard unifi poller",
+			mock:    dashboardsSearch,
+			handler: func(app *App) tele.HandlerFunc { return app.HandleShowDashboard },
+		},
+		{
+			name:    "annotate",
+			text:    "/annotate dashboard=\"unifi poller\" Controller upgraded",
+			mock:    dashboardsSearch,
+			handler: func(app *App) tele.HandlerFunc { return app.HandleAnnotate },
+		},
+		{
+			name: "show alert",
+			text: "/alert cosmosnode",
+			mock: func() {
+				httpmock.RegisterResponder(
+					"GET",
+					"https://example.com/api/prometheus/grafana/api/v1/rules",
+					httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-alerting-rules-ok.json")))
+			},
+			handler: func(app *App) tele.HandlerFunc { return app.HandleSingleAlert },
+		},
+		{
+			name:    "pause rule",
+			text:    "/pause_rule DiskFull",
+			options: []func(config *configPkg.Config){withGrafanaAlerts, withStagingGrafanaAlerts},
+			mock: func() {
+				httpmock.RegisterResponder(
+					"GET",
+					"https://example.com/api/prometheus/grafana/api/v1/rules",
+					httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+				httpmock.RegisterResponder(
+					"GET",
+					"https://staging.example.com/api/prometheus/grafana/api/v1/rules",
+					httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-alerting-rules-paused.json")))
+			},
+			handler: func(app *App) tele.HandlerFunc { return app.HandlePauseRule },
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			app := newTestApp(t, testCase.options...)
+			testCase.mock()
+
+			var keyboard types.TelegramInlineKeyboardResponse
+
+			httpmock.RegisterResponder(
+				"POST",
+				"https://api.telegram.org/botxxx:yyy/sendMessage",
+				captureKeyboard(&keyboard))
+
+			err := testCase.handler(app)(newTestContext(app, testCase.text))
+			require.NoError(t, err)
+
+			// The choice buttons and the cancel button.
+			require.Greater(t, len(keyboard.InlineKeyboard), 2)
+
+			for _, row := range keyboard.InlineKeyboard {
+				for _, button := range row {
+					require.LessOrEqual(t, len(button.CallbackData), constants.MaxCallbackDataLength, button.CallbackData)
+				}
+			}
+		})
+	}
+}
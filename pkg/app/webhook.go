@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	configPkg "main/pkg/config"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"net/http"
+	"slices"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func (a *App) StartWebhookServer() {
+	if a.WebhookServer == nil {
+		a.Logger.Debug().Msg("Webhook is not configured, not starting webhook server")
+		return
+	}
+
+	a.Logger.Info().
+		Str("address", a.WebhookServer.Addr).
+		Msg("Webhook server listening")
+
+	if err := a.WebhookServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		a.Logger.Panic().Err(err).Msg("Could not start webhook server")
+	}
+}
+
+func (a *App) StopWebhookServer() {
+	if a.WebhookServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := a.WebhookServer.Shutdown(ctx); err != nil {
+		a.Logger.Error().Err(err).Msg("Error stopping webhook server")
+	}
+}
+
+func (a *App) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	webhookConfig := a.Config.Webhook
+
+	if webhookConfig.Secret != "" {
+		secret := r.Header.Get(webhookConfig.SecretHeader)
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(webhookConfig.Secret)) != 1 {
+			a.Logger.Warn().
+				Str("remote_addr", r.RemoteAddr).
+				Msg("Got webhook with invalid secret")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var webhook types.AlertmanagerWebhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		a.Logger.Warn().Err(err).Msg("Could not decode webhook")
+		http.Error(w, fmt.Sprintf("Could not decode webhook: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	a.Logger.Info().
+		Str("receiver", webhook.Receiver).
+		Str("status", webhook.Status).
+		Int("alerts", len(webhook.Alerts)).
+		Msg("Got webhook")
+
+	receiver, found := webhookConfig.FindReceiver(webhook.Receiver)
+	if !found {
+		a.Logger.Warn().
+			Str("receiver", webhook.Receiver).
+			Msg("Got webhook for unknown receiver")
+		http.Error(w, "Receiver not found: "+webhook.Receiver, http.StatusNotFound)
+		return
+	}
+
+	notification := types.WebhookNotificationStruct{
+		Webhook:    webhook,
+		RenderTime: time.Now(),
+	}
+
+	templateData := render.RenderStruct{
+		Grafana: a.Grafana,
+		Data:    notification,
+	}
+
+	opts := []interface{}{}
+
+	silenceManager, silenceManagerFound := a.FindSilenceManagerByName(receiver.SilenceManager)
+	firingAlerts := webhook.FiringAlerts()
+
+	for _, alert := range webhook.Alerts {
+		if alert.Status != "firing" {
+			a.ExpireAlertAck(alert.Labels)
+		}
+	}
+
+	if len(firingAlerts) > 0 {
+		menu := &tele.ReplyMarkup{ResizeKeyboard: true}
+		rows := make([]tele.Row, 0, len(firingAlerts))
+
+		// Buttons are numbered the same way as the alerts in the notification,
+		// resolved alerts are numbered too but do not get any buttons.
+		for index, alert := range webhook.Alerts {
+			if alert.Status != "firing" {
+				continue
+			}
+
+			alertNumber := notification.GetAlertNumber(index)
+			buttons := []tele.Btn{}
+
+			if silenceManagerFound {
+				buttons = append(buttons, menu.Data(
+					fmt.Sprintf("🔇Silence alert #%d", alertNumber),
+					silenceManager.Prefixes().PrepareSilence,
+					a.Cache.Set(alert.GetHash(), alert.SerializeLabels()),
+				))
+			}
+
+			buttons = append(buttons, a.GetAckButton(menu, fmt.Sprintf("👀 Ack alert #%d", alertNumber), types.AlertAck{
+				AlertSourceName: receiver.Name,
+				AlertName:       alert.Labels["alertname"],
+				Labels:          alert.Labels,
+				TTL:             a.Config.Webhook.AckTTL,
+			}))
+
+			rows = append(rows, menu.Row(buttons...))
+		}
+
+		menu.Inline(rows...)
+		opts = append(opts, menu)
+	}
+
+	var sendErr error
+	sentCount := 0
+
+	for _, chatID := range a.GetWebhookNotificationChats(receiver, webhook) {
+		if err := a.SendRender(chatID, "webhook_notification", templateData, opts...); err != nil {
+			a.Logger.Error().
+				Err(err).
+				Int64("chat_id", chatID).
+				Str("receiver", receiver.Name).
+				Msg("Error sending webhook notification")
+			sendErr = err
+			continue
+		}
+
+		sentCount++
+	}
+
+	// Responding with an error so Alertmanager would retry sending this notification
+	// only if it was not sent anywhere, as a retry is sent to all the chats again
+	// and would duplicate the notification in the chats it was already sent to.
+	if sentCount == 0 && sendErr != nil {
+		http.Error(w, fmt.Sprintf("Error sending notification: %s", sendErr), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+}
+
+// GetWebhookNotificationChats returns the chats to send the webhook notification to:
+// the receiver ones, and the ones with subscriptions matching any of the alerts.
+func (a *App) GetWebhookNotificationChats(
+	receiver *configPkg.WebhookReceiverConfig,
+	webhook types.AlertmanagerWebhook,
+) []int64 {
+	chats := slices.Clone(receiver.Chats)
+	subscriptions := a.GetSubscriptions()
+
+	for _, alert := range webhook.Alerts {
+		for _, chatID := range subscriptions.GetMatchingChats(alert.Labels) {
+			if !slices.Contains(chats, chatID) {
+				chats = append(chats, chatID)
+			}
+		}
+	}
+
+	return chats
+}
@@ -0,0 +1,314 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/constants"
+	"main/pkg/fs"
+	"main/pkg/types"
+	"main/pkg/types/render"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func getWebhookTestConfig() *configPkg.Config {
+	return &configPkg.Config{
+		Timezone:     "Etc/GMT",
+		Log:          configPkg.LogConfig{LogLevel: "info"},
+		Telegram:     configPkg.TelegramConfig{Token: "xxx:yyy", Admins: []int64{1, 2}},
+		Grafana:      []configPkg.GrafanaConfig{{URL: "https://example.com", Silences: null.BoolFrom(true)}},
+		Alertmanager: nil,
+		Prometheus:   nil,
+		Webhook: &configPkg.WebhookConfig{
+			ListenAddress: ":9500",
+			SecretHeader:  "X-Webhook-Secret",
+			Secret:        "secret",
+			AckTTL:        time.Hour,
+			Receivers: []configPkg.WebhookReceiverConfig{
+				{Name: "telegram", Chats: []int64{1, 2}},
+			},
+		},
+	}
+}
+
+//nolint:paralleltest // disabled
+func TestAppWebhookMethodNotAllowed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getWebhookTestConfig(), &fs.TestFS{}, "1.2.3")
+	require.NotNil(t, app.WebhookServer)
+
+	recorder := httptest.NewRecorder()
+	app.HandleWebhook(recorder, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+//nolint:paralleltest // disabled
+func TestAppWebhookUnauthorized(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getWebhookTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	request := httptest.NewRequest(
+		http.MethodPost,
+		"/webhook",
+		bytes.NewReader(assets.GetBytesOrPanic("alertmanager-webhook.json")),
+	)
+	request.Header.Set("X-Webhook-Secret", "wrong")
+
+	recorder := httptest.NewRecorder()
+	app.HandleWebhook(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+//nolint:paralleltest // disabled
+func TestAppWebhookInvalidPayload(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	app := NewApp(getWebhookTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	request := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte("not json")))
+	request.Header.Set("X-Webhook-Secret", "secret")
+
+	recorder := httptest.NewRecorder()
+	app.HandleWebhook(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+//nolint:paralleltest // disabled
+func TestAppWebhookUnknownReceiver(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	config := getWebhookTestConfig()
+	config.Webhook.Receivers[0].Name = "other"
+
+	app := NewApp(config, &fs.TestFS{}, "1.2.3")
+
+	request := httptest.NewRequest(
+		http.MethodPost,
+		"/webhook",
+		bytes.NewReader(assets.GetBytesOrPanic("alertmanager-webhook.json")),
+	)
+	request.Header.Set("X-Webhook-Secret", "secret")
+
+	recorder := httptest.NewRecorder()
+	app.HandleWebhook(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+//nolint:paralleltest // disabled
+func TestAppWebhookSendFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	app := NewApp(getWebhookTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	request := httptest.NewRequest(
+		http.MethodPost,
+		"/webhook",
+		bytes.NewReader(assets.GetBytesOrPanic("alertmanager-webhook.json")),
+	)
+	request.Header.Set("X-Webhook-Secret", "secret")
+
+	recorder := httptest.NewRecorder()
+	app.HandleWebhook(recorder, request)
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+//nolint:paralleltest // disabled
+func TestAppWebhookSendPartiallyFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")).
+			Times(1).
+			Then(httpmock.NewErrorResponder(errors.New("custom error"))))
+
+	app := NewApp(getWebhookTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	request := httptest.NewRequest(
+		http.MethodPost,
+		"/webhook",
+		bytes.NewReader(assets.GetBytesOrPanic("alertmanager-webhook.json")),
+	)
+	request.Header.Set("X-Webhook-Secret", "secret")
+
+	// Not retried, as a retry would duplicate the notification in the chat it was sent to.
+	recorder := httptest.NewRecorder()
+	app.HandleWebhook(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, 2, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppWebhookOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	// Only the first alert is firing, so only it has buttons, numbered as in the notification.
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.BodyContainsString("Ack alert #1"),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	app := NewApp(getWebhookTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	request := httptest.NewRequest(
+		http.MethodPost,
+		"/webhook",
+		bytes.NewReader(assets.GetBytesOrPanic("alertmanager-webhook.json")),
+	)
+	request.Header.Set("X-Webhook-Secret", "secret")
+
+	recorder := httptest.NewRecorder()
+	app.HandleWebhook(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, 3, httpmock.GetTotalCallCount())
+
+	// only the firing alert can be silenced or acked
+	hash := types.GetLabelsHash(map[string]string{
+		"alertname": "InstanceDown",
+		"instance":  "node-1:9100",
+		"severity":  "critical",
+	})
+	labels, found := app.Cache.Get(hash)
+	require.True(t, found)
+	require.Equal(t, "alertname=InstanceDown instance=node-1:9100 severity=critical", labels)
+
+	ack := types.AlertAck{}
+	require.True(t, app.Cache.GetObject(constants.AlertToAckCachePrefix+hash, &ack))
+	require.Equal(t, "telegram", ack.AlertSourceName)
+	require.Equal(t, "InstanceDown", ack.AlertName)
+	require.Equal(t, time.Hour, ack.TTL)
+	require.Equal(t, 2, app.Cache.Length())
+}
+
+//nolint:paralleltest // disabled
+func TestAppWebhookSubscribedChats(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")))
+
+	app := NewApp(getWebhookTestConfig(), &fs.TestFS{}, "1.2.3")
+	app.UpdateSubscriptions(func(subscriptions types.Subscriptions) {
+		subscriptions.Add(2, types.QueryMatcherFromKeyValueString("severity=~critical|warning"))
+		subscriptions.Add(3, types.QueryMatcherFromKeyValueString("instance=~node-.*"))
+		subscriptions.Add(4, types.QueryMatcherFromKeyValueString("severity=info"))
+	})
+
+	request := httptest.NewRequest(
+		http.MethodPost,
+		"/webhook",
+		bytes.NewReader(assets.GetBytesOrPanic("alertmanager-webhook.json")),
+	)
+	request.Header.Set("X-Webhook-Secret", "secret")
+
+	recorder := httptest.NewRecorder()
+	app.HandleWebhook(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// chats 1 and 2 are receiver chats, chat 3 is subscribed, chat 4 subscription does not match
+	require.Equal(t, 3, httpmock.GetCallCountInfo()["POST https://api.telegram.org/botxxx:yyy/sendMessage"])
+}
+
+//nolint:paralleltest // disabled
+func TestAppWebhookRenderOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/getMe",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-bot-ok.json")))
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://api.telegram.org/botxxx:yyy/sendMessage",
+		types.TelegramResponseHasBytes(assets.GetBytesOrPanic("responses/webhook-notification-ok.html")),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("telegram-send-message-ok.json")),
+	)
+
+	app := NewApp(getWebhookTestConfig(), &fs.TestFS{}, "1.2.3")
+
+	var webhook types.AlertmanagerWebhook
+	err := json.Unmarshal(assets.GetBytesOrPanic("alertmanager-webhook.json"), &webhook)
+	require.NoError(t, err)
+
+	timeParsed, err := time.Parse(time.RFC3339, "2024-11-08T23:34:01Z")
+	require.NoError(t, err)
+
+	err = app.SendRender(1, "webhook_notification", render.RenderStruct{
+		Grafana: app.Grafana,
+		Data: types.WebhookNotificationStruct{
+			Webhook:    webhook,
+			RenderTime: timeParsed,
+		},
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"main/pkg/fs"
+	"main/pkg/types"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Log is an append-only log of all mutating actions done via the bot,
+// stored as a file with one JSON-encoded entry per line.
+type Log struct {
+	filesystem fs.FS
+	path       string
+	logger     zerolog.Logger
+	mutex      sync.Mutex
+}
+
+func NewLog(logger *zerolog.Logger, filesystem fs.FS, path string) *Log {
+	return &Log{
+		filesystem: filesystem,
+		path:       path,
+		logger:     logger.With().Str("component", "audit").Logger(),
+	}
+}
+
+func (l *Log) Enabled() bool {
+	return l.path != ""
+}
+
+func (l *Log) Append(entry types.AuditEntry) error {
+	if !l.Enabled() {
+		return nil
+	}
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.filesystem.AppendFile(l.path, append(encoded, '\n'), 0o644)
+}
+
+// GetEntries returns all audit log entries, the most recent first.
+func (l *Log) GetEntries() ([]types.AuditEntry, error) {
+	if !l.Enabled() {
+		return []types.AuditEntry{}, nil
+	}
+
+	l.mutex.Lock()
+	content, err := l.filesystem.ReadFile(l.path)
+	l.mutex.Unlock()
+
+	// Nothing was logged yet.
+	if errors.Is(err, os.ErrNotExist) {
+		return []types.AuditEntry{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	entries := make([]types.AuditEntry, 0)
+
+	for index, line := range bytes.Split(content, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry types.AuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			l.logger.Warn().Err(err).Int("line", index+1).Msg("Error decoding audit log entry, skipping")
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	slices.Reverse(entries)
+	return entries, nil
+}
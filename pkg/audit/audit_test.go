@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"errors"
+	"main/pkg/fs"
+	loggerPkg "main/pkg/logger"
+	"main/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogDisabled(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	log := NewLog(logger, &fs.TestFS{WriteError: errors.New("custom error")}, "")
+
+	require.False(t, log.Enabled())
+	require.NoError(t, log.Append(types.AuditEntry{}))
+
+	entries, err := log.GetEntries()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestAuditLogAppendFailed(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	log := NewLog(logger, &fs.TestFS{WriteError: errors.New("custom error")}, "audit.jsonl")
+
+	err := log.Append(types.AuditEntry{})
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+}
+
+func TestAuditLogGetEntriesNotExisting(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	log := NewLog(logger, &fs.TestFS{}, "not-existing.jsonl")
+
+	entries, err := log.GetEntries()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestAuditLogGetEntriesOk(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	log := NewLog(logger, &fs.TestFS{}, "audit-log.jsonl")
+
+	entries, err := log.GetEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, types.AuditActionDeleteSilence, entries[0].Action)
+	require.Equal(t, types.AuditActionCreateSilence, entries[1].Action)
+	require.Equal(t, "48h0m0s", entries[1].Duration)
+}
+
+func TestAuditLogAppendAndRead(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	log := NewLog(logger, &fs.OsFS{}, t.TempDir()+"/audit.jsonl")
+
+	require.NoError(t, log.Append(types.AuditEntry{
+		Time:      time.Now(),
+		Action:    types.AuditActionCreateSilence,
+		UserID:    1,
+		SilenceID: "first",
+	}))
+	require.NoError(t, log.Append(types.AuditEntry{
+		Time:      time.Now(),
+		Action:    types.AuditActionDeleteSilence,
+		UserID:    1,
+		SilenceID: "first",
+	}))
+
+	entries, err := log.GetEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, types.AuditActionDeleteSilence, entries[0].Action)
+	require.Equal(t, "first", entries[1].SilenceID)
+}
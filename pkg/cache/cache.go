@@ -3,6 +3,7 @@ package cache
 import (
 	"encoding/json"
 	"main/pkg/fs"
+	"sync"
 
 	"github.com/rs/zerolog"
 )
@@ -12,6 +13,7 @@ type Cache struct {
 	cachePath  string
 	cache      map[string]string
 	logger     zerolog.Logger
+	mutex      sync.Mutex
 }
 
 func NewCache(logger *zerolog.Logger, filesystem fs.FS, cachePath string) *Cache {
@@ -24,11 +26,17 @@ func NewCache(logger *zerolog.Logger, filesystem fs.FS, cachePath string) *Cache
 }
 
 func (c *Cache) Get(key string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	value, found := c.cache[key]
 	return value, found
 }
 
 func (c *Cache) Set(key, value string) string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	c.cache[key] = value
 	c.logger.Trace().
 		Str("key", key).
@@ -36,22 +44,57 @@ func (c *Cache) Set(key, value string) string {
 		Int("len", len(c.cache)).
 		Msg("Cache set item")
 
-	c.Save()
+	c.saveUnsafe()
 
 	return key
 }
 
+// GetObject fetches a value stored with SetObject and decodes it into target.
+// Returns false if the value is not present or cannot be decoded.
+func (c *Cache) GetObject(key string, target interface{}) bool {
+	value, found := c.Get(key)
+	if !found {
+		return false
+	}
+
+	if err := json.Unmarshal([]byte(value), target); err != nil {
+		c.logger.Warn().Err(err).Str("key", key).Msg("Error decoding cache item")
+		return false
+	}
+
+	return true
+}
+
+// SetObject stores any JSON-serializable value, so it survives restarts
+// together with the rest of the cache. It is used for the buttons state, as Telegram
+// limits callback data to 64 bytes, so buttons only get the returned key as callback data.
+func (c *Cache) SetObject(key string, value interface{}) string {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		c.logger.Warn().Err(err).Str("key", key).Msg("Error encoding cache item")
+		return key
+	}
+
+	return c.Set(key, string(bytes))
+}
+
 func (c *Cache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	delete(c.cache, key)
 	c.logger.Trace().
 		Str("key", key).
 		Int("len", len(c.cache)).
 		Msg("Cache delete item")
 
-	c.Save()
+	c.saveUnsafe()
 }
 
 func (c *Cache) Length() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	return len(c.cache)
 }
 
@@ -67,6 +110,9 @@ func (c *Cache) Load() {
 		return
 	}
 
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if unmarshalErr := json.Unmarshal(bytes, &c.cache); unmarshalErr != nil {
 		c.logger.Warn().Err(err).Msg("Error parsing cache")
 	}
@@ -75,6 +121,13 @@ func (c *Cache) Load() {
 }
 
 func (c *Cache) Save() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.saveUnsafe()
+}
+
+func (c *Cache) saveUnsafe() {
 	if c.cachePath == "" {
 		return
 	}
@@ -41,3 +41,35 @@ func TestCacheSaveFailed(t *testing.T) {
 	}, "cache.json")
 	cache.Save()
 }
+
+func TestCacheObjectOk(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(loggerPkg.GetNopLogger(), &fs.TestFS{}, "")
+	cache.SetObject("key", map[string]int{"value": 1})
+
+	var value map[string]int
+	require.True(t, cache.GetObject("key", &value))
+	require.Equal(t, map[string]int{"value": 1}, value)
+}
+
+func TestCacheObjectNotFound(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(loggerPkg.GetNopLogger(), &fs.TestFS{}, "")
+
+	var value map[string]int
+	require.False(t, cache.GetObject("key", &value))
+}
+
+func TestCacheObjectInvalid(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(loggerPkg.GetNopLogger(), &fs.TestFS{}, "")
+	cache.Set("key", "not json")
+	cache.SetObject("key2", make(chan string))
+
+	var value map[string]int
+	require.False(t, cache.GetObject("key", &value))
+	require.False(t, cache.GetObject("key2", &value))
+}
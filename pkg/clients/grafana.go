@@ -2,6 +2,7 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"main/pkg/types"
 	"main/pkg/utils"
 	"main/pkg/utils/generic"
+	"net/url"
 	"strconv"
 
 	"github.com/rs/zerolog"
@@ -22,11 +24,15 @@ type Grafana struct {
 	Client *http.Client
 }
 
+// MaxConcurrentDashboardFetches limits how many dashboards are fetched
+// at once when all of them are needed, so Grafana is not overloaded.
+const MaxConcurrentDashboardFetches = 10
+
 func InitGrafana(config config.GrafanaConfig, logger *zerolog.Logger) *Grafana {
 	return &Grafana{
 		Config: config,
 		Logger: logger.With().Str("component", "grafana").Logger(),
-		Client: http.NewClient(logger, "grafana"),
+		Client: http.NewClient(logger, "grafana:"+config.GetName(), config.HTTPConfig),
 	}
 }
 
@@ -47,6 +53,7 @@ func (g *Grafana) GetAuth() *http.Auth {
 }
 
 func (g *Grafana) RenderPanel(
+	ctx context.Context,
 	panelID int,
 	dashboardID string,
 	qs map[string]string,
@@ -60,38 +67,115 @@ func (g *Grafana) RenderPanel(
 		utils.SerializeQueryString(params),
 	))
 
-	return g.Client.GetRaw(url, g.GetAuth())
+	return g.Client.GetRaw(ctx, url, g.GetAuth())
+}
+
+// RenderDashboard renders the whole dashboard. Unless the height is passed explicitly,
+// the full page is rendered, not only the part that fits the default height.
+func (g *Grafana) RenderDashboard(
+	ctx context.Context,
+	dashboardID string,
+	qs map[string]string,
+) (io.ReadCloser, error) {
+	params := generic.MergeMaps(g.Config.RenderOptions, map[string]string{"height": "-1"})
+	params = generic.MergeMaps(params, qs)
+
+	url := g.RelativeLink(fmt.Sprintf(
+		"/render/d/%s/dashboard?%s",
+		dashboardID,
+		utils.SerializeQueryString(params),
+	))
+
+	return g.Client.GetRaw(ctx, url, g.GetAuth())
+}
+
+// RenderPanels renders multiple panels of a dashboard concurrently, returning
+// the images in the same order as panels. If any of the panels fails to render,
+// an error is returned and all the images are closed.
+func (g *Grafana) RenderPanels(
+	ctx context.Context,
+	panelIDs []int,
+	dashboardID string,
+	qs map[string]string,
+) ([]io.ReadCloser, error) {
+	images := make([]io.ReadCloser, len(panelIDs))
+	group, groupCtx := errgroup.WithContext(ctx)
+
+	for i, p := range panelIDs {
+		index := i
+		panelID := p
+
+		group.Go(func() error {
+			image, renderErr := g.RenderPanel(groupCtx, panelID, dashboardID, qs)
+			if renderErr == nil {
+				images[index] = image
+			}
+
+			return renderErr
+		})
+	}
+
+	if groupErr := group.Wait(); groupErr != nil {
+		for _, image := range images {
+			if image != nil {
+				image.Close()
+			}
+		}
+
+		return nil, groupErr
+	}
+
+	return images, nil
 }
 
-func (g *Grafana) GetAllDashboards() (types.GrafanaDashboardsInfo, error) {
+func (g *Grafana) GetAllDashboards(ctx context.Context) (types.GrafanaDashboardsInfo, error) {
 	url := g.RelativeLink("/api/search?type=dash-db")
 	dashboards := types.GrafanaDashboardsInfo{}
-	err := g.Client.Get(url, &dashboards, g.GetAuth())
+	err := g.Client.Get(ctx, url, &dashboards, g.GetAuth())
 	return dashboards, err
 }
 
-func (g *Grafana) GetDashboard(dashboardUID string) (*types.GrafanaDashboardResponse, error) {
+func (g *Grafana) GetDashboard(ctx context.Context, dashboardUID string) (*types.GrafanaDashboardResponse, error) {
 	url := g.RelativeLink("/api/dashboards/uid/" + dashboardUID)
 	dashboards := &types.GrafanaDashboardResponse{}
-	err := g.Client.Get(url, &dashboards, g.GetAuth())
+	err := g.Client.Get(ctx, url, &dashboards, g.GetAuth())
 	return dashboards, err
 }
 
-func (g *Grafana) GetAllPanels() (types.PanelsStruct, error) {
-	dashboards, err := g.GetAllDashboards()
+func (g *Grafana) GetAllPanels(ctx context.Context) (types.PanelsStruct, error) {
+	dashboards, err := g.GetAllDashboards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboardsEnriched, err := g.GetDashboards(ctx, dashboards)
 	if err != nil {
 		return nil, err
 	}
 
+	panels := make(types.PanelsStruct, 0)
+	for _, d := range dashboardsEnriched {
+		panels = append(panels, d.GetPanels()...)
+	}
+
+	return panels, nil
+}
+
+// GetDashboards fetches the dashboards concurrently, keeping their order.
+func (g *Grafana) GetDashboards(
+	ctx context.Context,
+	dashboards types.GrafanaDashboardsInfo,
+) ([]types.GrafanaDashboardResponse, error) {
 	dashboardsEnriched := make([]types.GrafanaDashboardResponse, len(dashboards))
-	group, _ := errgroup.WithContext(context.Background())
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(MaxConcurrentDashboardFetches)
 
 	for i, d := range dashboards {
 		index := i
 		dashboard := d
 
 		group.Go(func() error {
-			enrichedDashboard, dashboardErr := g.GetDashboard(dashboard.UID)
+			enrichedDashboard, dashboardErr := g.GetDashboard(groupCtx, dashboard.UID)
 			if dashboardErr == nil {
 				dashboardsEnriched[index] = *enrichedDashboard
 			}
@@ -104,29 +188,7 @@ func (g *Grafana) GetAllPanels() (types.PanelsStruct, error) {
 		return nil, groupErr
 	}
 
-	panelsCount := 0
-	for _, d := range dashboardsEnriched {
-		panelsCount += len(d.Dashboard.Panels)
-	}
-
-	panels := make([]types.PanelStruct, panelsCount)
-	counter := 0
-
-	for _, d := range dashboardsEnriched {
-		for _, p := range d.Dashboard.Panels {
-			panels[counter] = types.PanelStruct{
-				Name:          p.Title,
-				DashboardName: d.Dashboard.Title,
-				DashboardID:   d.Dashboard.UID,
-				DashboardURL:  d.Meta.URL,
-				PanelID:       p.ID,
-			}
-
-			counter++
-		}
-	}
-
-	return panels, nil
+	return dashboardsEnriched, nil
 }
 
 func (g *Grafana) RelativeLink(url string) string {
@@ -150,9 +212,196 @@ func (g *Grafana) GetDatasourceLink(ds types.GrafanaDatasource) template.HTML {
 	return template.HTML(fmt.Sprintf("<a href='%s/datasources/edit/%s'>%s</a>", g.Config.URL, ds.UID, ds.Name))
 }
 
-func (g *Grafana) GetDatasources() ([]types.GrafanaDatasource, error) {
+func (g *Grafana) CreateAnnotation(ctx context.Context, annotation types.GrafanaAnnotation) (int64, error) {
+	response := types.GrafanaAnnotationCreateResponse{}
+	url := g.RelativeLink("/api/annotations")
+	err := g.Client.Post(ctx, url, annotation, &response, g.GetAuth())
+	return response.ID, err
+}
+
+// GetAnnotations returns the latest annotations, excluding the ones
+// created by Grafana alerting on alert state changes.
+func (g *Grafana) GetAnnotations(ctx context.Context, limit int) ([]types.GrafanaAnnotation, error) {
+	annotations := []types.GrafanaAnnotation{}
+	url := g.RelativeLink(fmt.Sprintf("/api/annotations?type=annotation&limit=%d", limit))
+	err := g.Client.Get(ctx, url, &annotations, g.GetAuth())
+	return annotations, err
+}
+
+func (g *Grafana) GetDatasources(ctx context.Context) ([]types.GrafanaDatasource, error) {
 	datasources := []types.GrafanaDatasource{}
 	url := g.RelativeLink("/api/datasources")
-	err := g.Client.Get(url, &datasources, g.GetAuth())
+	err := g.Client.Get(ctx, url, &datasources, g.GetAuth())
 	return datasources, err
 }
+
+// FindDatasource finds a datasource by its UID or name, or returns the default
+// datasource if the reference is empty or "default".
+func (g *Grafana) FindDatasource(ctx context.Context, reference string) (*types.GrafanaDatasource, error) {
+	datasources, err := g.GetDatasources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	datasource, found := generic.Find(datasources, func(ds types.GrafanaDatasource) bool {
+		if reference == "" || reference == "default" {
+			return ds.IsDefault
+		}
+
+		return ds.UID == reference || ds.Name == reference
+	})
+
+	if !found {
+		return nil, fmt.Errorf("datasource '%s' is not found", reference)
+	}
+
+	return datasource, nil
+}
+
+// QueryDatasource runs the query against the datasource via the Grafana
+// datasource query API, returning the resulting data frames.
+func (g *Grafana) QueryDatasource(
+	ctx context.Context,
+	datasource types.GrafanaDatasource,
+	query string,
+	from string,
+	to string,
+) ([]types.DataFrame, error) {
+	request, err := types.NewDatasourceQueryRequest(datasource, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := types.DatasourceQueryResponse{}
+	if err := g.Client.Post(ctx, g.RelativeLink("/api/ds/query"), request, &response, g.GetAuth()); err != nil {
+		return nil, err
+	}
+
+	result, ok := response.Results["A"]
+	if !ok {
+		return nil, errors.New("got empty response")
+	}
+
+	if result.Error != "" {
+		return nil, errors.New(result.Error)
+	}
+
+	return result.Frames, nil
+}
+
+func (g *Grafana) GetDatasourceProxyLink(datasourceUID string) string {
+	return g.RelativeLink("/api/datasources/proxy/uid/" + datasourceUID)
+}
+
+// GetPrometheusDatasource returns a client querying a Prometheus
+// datasource via the Grafana datasource proxy.
+func (g *Grafana) GetPrometheusDatasource(datasourceUID string) *Prometheus {
+	return &Prometheus{
+		URL:    g.GetDatasourceProxyLink(datasourceUID),
+		Auth:   g.GetAuth(),
+		Logger: g.Logger,
+		Client: g.Client,
+	}
+}
+
+// GetLokiDatasource returns a client querying a Loki
+// datasource via the Grafana datasource proxy.
+func (g *Grafana) GetLokiDatasource(datasourceUID string) *Loki {
+	return &Loki{
+		URL:    g.GetDatasourceProxyLink(datasourceUID),
+		Auth:   g.GetAuth(),
+		Logger: g.Logger,
+		Client: g.Client,
+	}
+}
+
+func (g *Grafana) GetLabelValues(ctx context.Context, datasourceUID, selector, label string) ([]string, error) {
+	relativeURL := fmt.Sprintf("/api/datasources/proxy/uid/%s/api/v1/label/%s/values", datasourceUID, label)
+	if selector != "" {
+		relativeURL += "?match[]=" + url.QueryEscape(selector)
+	}
+
+	response := types.PrometheusLabelValuesResponse{}
+	err := g.Client.Get(ctx, g.RelativeLink(relativeURL), &response, g.GetAuth())
+	return response.Data, err
+}
+
+// GetVariableOptions returns the options to choose from for a dashboard variable,
+// substituting other variables values into its query. Only custom and datasource
+// variables, and query variables with label_values() queries are supported,
+// for others the options saved in the dashboard are returned.
+func (g *Grafana) GetVariableOptions(
+	ctx context.Context,
+	variable types.GrafanaTemplateVariable,
+	values map[string]string,
+) ([]types.GrafanaTemplateVariableOption, error) {
+	query := utils.SubstituteVariables(string(variable.Query), values)
+	options := variable.Options
+
+	switch variable.Type {
+	case "custom":
+		if len(options) == 0 {
+			options = utils.ParseCustomVariableQuery(query)
+		}
+	case "datasource":
+		datasources, err := g.GetDatasources(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		names := generic.Map(
+			generic.Filter(datasources, func(ds types.GrafanaDatasource) bool { return ds.Type == query }),
+			func(ds types.GrafanaDatasource) string { return ds.Name },
+		)
+
+		if options, err = g.valuesToOptions(variable, names); err != nil {
+			return nil, err
+		}
+	case "query":
+		selector, label, ok := utils.ParseLabelValuesQuery(query)
+		if !ok {
+			break
+		}
+
+		datasource, err := g.FindDatasource(ctx, utils.SubstituteVariables(variable.Datasource.GetReference(), values))
+		if err != nil {
+			return nil, err
+		}
+
+		labelValues, err := g.GetLabelValues(ctx, datasource.UID, selector, label)
+		if err != nil {
+			return nil, err
+		}
+
+		if options, err = g.valuesToOptions(variable, labelValues); err != nil {
+			return nil, err
+		}
+	}
+
+	options = generic.Filter(options, func(o types.GrafanaTemplateVariableOption) bool {
+		return o.Value != "$__all"
+	})
+
+	if variable.IncludeAll && len(options) > 0 {
+		options = append([]types.GrafanaTemplateVariableOption{{Text: "All", Value: "$__all"}}, options...)
+	}
+
+	return options, nil
+}
+
+func (g *Grafana) valuesToOptions(
+	variable types.GrafanaTemplateVariable,
+	values []string,
+) ([]types.GrafanaTemplateVariableOption, error) {
+	filtered, err := utils.ApplyVariableRegex(variable.Regex, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return generic.Map(filtered, func(value string) types.GrafanaTemplateVariableOption {
+		return types.GrafanaTemplateVariableOption{
+			Text:  types.GrafanaTemplateVariableValue(value),
+			Value: types.GrafanaTemplateVariableValue(value),
+		}
+	}), nil
+}
@@ -1,10 +1,12 @@
 package clients
 
 import (
+	"context"
 	"errors"
 	"main/assets"
 	configPkg "main/pkg/config"
 	loggerPkg "main/pkg/logger"
+	"main/pkg/types"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -26,7 +28,7 @@ func TestGrafanaGetAllDashboardsFailed(t *testing.T) {
 		"https://example.com/api/search?type=dash-db",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	dashboards, err := client.GetAllDashboards()
+	dashboards, err := client.GetAllDashboards(context.Background())
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, dashboards)
@@ -46,7 +48,7 @@ func TestGrafanaGetAllDashboardsOk(t *testing.T) {
 		"https://example.com/api/search?type=dash-db",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboards-ok.json")))
 
-	dashboards, err := client.GetAllDashboards()
+	dashboards, err := client.GetAllDashboards(context.Background())
 	require.NoError(t, err)
 	require.NotEmpty(t, dashboards)
 }
@@ -65,7 +67,7 @@ func TestGrafanaGetDashboardFailed(t *testing.T) {
 		"https://example.com/api/dashboards/uid/dashboard",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	dashboard, err := client.GetDashboard("dashboard")
+	dashboard, err := client.GetDashboard(context.Background(), "dashboard")
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, dashboard)
@@ -85,7 +87,7 @@ func TestGrafanaGetDashboardOk(t *testing.T) {
 		"https://example.com/api/dashboards/uid/dashboard",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
 
-	dashboard, err := client.GetDashboard("dashboard")
+	dashboard, err := client.GetDashboard(context.Background(), "dashboard")
 	require.NoError(t, err)
 	require.NotEmpty(t, dashboard)
 }
@@ -104,7 +106,7 @@ func TestGrafanaGetAllPanelsDashboardFail(t *testing.T) {
 		"https://example.com/api/search?type=dash-db",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	panels, err := client.GetAllPanels()
+	panels, err := client.GetAllPanels(context.Background())
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, panels)
@@ -129,7 +131,7 @@ func TestGrafanaGetAllPanelsPanelFail(t *testing.T) {
 		"https://example.com/api/dashboards/uid/alertmanager",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	panels, err := client.GetAllPanels()
+	panels, err := client.GetAllPanels(context.Background())
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, panels)
@@ -154,9 +156,13 @@ func TestGrafanaGetAllPanelsOk(t *testing.T) {
 		"https://example.com/api/dashboards/uid/alertmanager",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
 
-	panels, err := client.GetAllPanels()
+	panels, err := client.GetAllPanels(context.Background())
 	require.NoError(t, err)
 	require.NotEmpty(t, panels)
+
+	// Panels nested into collapsed rows are included.
+	_, found := panels.FindByName("Memory usage for $instance")
+	require.True(t, found)
 }
 
 //nolint:paralleltest
@@ -173,7 +179,7 @@ func TestGrafanaGetDatasourcesFail(t *testing.T) {
 		"https://example.com/api/datasources",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	datasources, err := client.GetDatasources()
+	datasources, err := client.GetDatasources(context.Background())
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, datasources)
@@ -193,7 +199,7 @@ func TestGrafanaGetDatasourcesOk(t *testing.T) {
 		"https://example.com/api/datasources",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-ok.json")))
 
-	datasources, err := client.GetDatasources()
+	datasources, err := client.GetDatasources(context.Background())
 	require.NoError(t, err)
 	require.NotEmpty(t, datasources)
 }
@@ -212,7 +218,7 @@ func TestGrafanaRenderPanelFailed(t *testing.T) {
 		"https://example.com/render/d-solo/dashboard/dashboard?panelId=1",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	render, err := client.RenderPanel(1, "dashboard", map[string]string{})
+	render, err := client.RenderPanel(context.Background(), 1, "dashboard", map[string]string{})
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, render)
@@ -232,7 +238,7 @@ func TestGrafanaRenderPanelOk(t *testing.T) {
 		"https://example.com/render/d-solo/dashboard/dashboard?panelId=1",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
 
-	render, err := client.RenderPanel(1, "dashboard", map[string]string{})
+	render, err := client.RenderPanel(context.Background(), 1, "dashboard", map[string]string{})
 	defer func() {
 		_ = render.Close()
 	}()
@@ -240,3 +246,381 @@ func TestGrafanaRenderPanelOk(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, render)
 }
+
+//nolint:paralleltest
+func TestGrafanaRenderDashboardOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com", RenderOptions: map[string]string{"width": "1000"}}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d/dashboard/dashboard?height=-1&width=500",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	render, err := client.RenderDashboard(context.Background(), "dashboard", map[string]string{"width": "500"})
+	defer func() {
+		_ = render.Close()
+	}()
+
+	require.NoError(t, err)
+	require.NotNil(t, render)
+}
+
+//nolint:paralleltest
+func TestGrafanaRenderPanelsFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/dashboard/dashboard?panelId=1",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/dashboard/dashboard?panelId=2",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	renders, err := client.RenderPanels(context.Background(), []int{1, 2}, "dashboard", map[string]string{})
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Empty(t, renders)
+}
+
+//nolint:paralleltest
+func TestGrafanaRenderPanelsOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/dashboard/dashboard?panelId=1",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/render/d-solo/dashboard/dashboard?panelId=2",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("render.jpeg")))
+
+	renders, err := client.RenderPanels(context.Background(), []int{1, 2}, "dashboard", map[string]string{})
+	require.NoError(t, err)
+	require.Len(t, renders, 2)
+
+	for _, render := range renders {
+		require.NotNil(t, render)
+		_ = render.Close()
+	}
+}
+
+//nolint:paralleltest
+func TestGrafanaFindDatasource(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-ok.json")))
+
+	datasource, err := client.FindDatasource(context.Background(), "default")
+	require.NoError(t, err)
+	require.Equal(t, "prometheus", datasource.UID)
+
+	datasource, err = client.FindDatasource(context.Background(), "Prometheus")
+	require.NoError(t, err)
+	require.Equal(t, "prometheus", datasource.UID)
+
+	_, err = client.FindDatasource(context.Background(), "unknown")
+	require.Error(t, err)
+	require.ErrorContains(t, err, "datasource 'unknown' is not found")
+}
+
+//nolint:paralleltest
+func TestGrafanaGetVariableOptionsCustom(t *testing.T) {
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	options, err := client.GetVariableOptions(context.Background(), types.GrafanaTemplateVariable{
+		Name:       "env",
+		Type:       "custom",
+		Query:      "prod,staging",
+		IncludeAll: true,
+	}, map[string]string{})
+	require.NoError(t, err)
+	require.Equal(t, []types.GrafanaTemplateVariableOption{
+		{Text: "All", Value: "$__all"},
+		{Text: "prod", Value: "prod"},
+		{Text: "staging", Value: "staging"},
+	}, options)
+}
+
+//nolint:paralleltest
+func TestGrafanaGetVariableOptionsDatasource(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-ok.json")))
+
+	options, err := client.GetVariableOptions(context.Background(), types.GrafanaTemplateVariable{
+		Name:  "datasource",
+		Type:  "datasource",
+		Query: "prometheus",
+	}, map[string]string{})
+	require.NoError(t, err)
+	require.Equal(t, []types.GrafanaTemplateVariableOption{
+		{Text: "Prometheus", Value: "Prometheus"},
+	}, options)
+}
+
+//nolint:paralleltest
+func TestGrafanaGetVariableOptionsQueryFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources/proxy/uid/prometheus/api/v1/label/instance/values",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	_, err := client.GetVariableOptions(context.Background(), types.GrafanaTemplateVariable{
+		Name:  "instance",
+		Type:  "query",
+		Query: "label_values(instance)",
+	}, map[string]string{})
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+}
+
+//nolint:paralleltest
+func TestGrafanaGetVariableOptionsQueryOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-datasources-ok.json")))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/datasources/proxy/uid/prometheus/api/v1/label/instance/values?match%5B%5D=up%7Benv%3D%22staging%22%7D",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-label-values-ok.json")))
+
+	options, err := client.GetVariableOptions(context.Background(), types.GrafanaTemplateVariable{
+		Name:       "instance",
+		Type:       "query",
+		Query:      "label_values(up{env=\"$env\"}, instance)",
+		Regex:      "/(.*):9100/",
+		Datasource: types.GrafanaDatasourceRef{Name: "$datasource"},
+	}, map[string]string{"env": "staging", "datasource": "default"})
+	require.NoError(t, err)
+	require.Equal(t, []types.GrafanaTemplateVariableOption{
+		{Text: "host1", Value: "host1"},
+		{Text: "host2", Value: "host2"},
+	}, options)
+}
+
+//nolint:paralleltest
+func TestGrafanaGetVariableOptionsQueryUnsupported(t *testing.T) {
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	options, err := client.GetVariableOptions(context.Background(), types.GrafanaTemplateVariable{
+		Name:    "instance",
+		Type:    "query",
+		Query:   "query_result(up)",
+		Options: []types.GrafanaTemplateVariableOption{{Text: "saved", Value: "saved"}},
+	}, map[string]string{})
+	require.NoError(t, err)
+	require.Equal(t, []types.GrafanaTemplateVariableOption{{Text: "saved", Value: "saved"}}, options)
+}
+
+//nolint:paralleltest
+func TestGrafanaQueryDatasourceUnsupported(t *testing.T) {
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	frames, err := client.QueryDatasource(context.Background(), types.GrafanaDatasource{Type: "tempo"}, "{}", "now-1h", "now")
+	require.Error(t, err)
+	require.Empty(t, frames)
+}
+
+//nolint:paralleltest
+func TestGrafanaQueryDatasourceFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/ds/query",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	frames, err := client.QueryDatasource(context.Background(), types.GrafanaDatasource{Type: "loki"}, "{}", "now-1h", "now")
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Empty(t, frames)
+}
+
+//nolint:paralleltest
+func TestGrafanaQueryDatasourceEmpty(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/ds/query",
+		httpmock.NewStringResponder(200, `{"results":{}}`))
+
+	frames, err := client.QueryDatasource(context.Background(), types.GrafanaDatasource{Type: "loki"}, "{}", "now-1h", "now")
+	require.Error(t, err)
+	require.ErrorContains(t, err, "got empty response")
+	require.Empty(t, frames)
+}
+
+//nolint:paralleltest
+func TestGrafanaQueryDatasourceOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/ds/query",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-ds-query-prometheus.json")))
+
+	frames, err := client.QueryDatasource(context.Background(), types.GrafanaDatasource{Type: "prometheus"}, "up", "now-1h", "now")
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+	require.Equal(t, 2, frames[0].RowsCount())
+}
+
+//nolint:paralleltest
+func TestGrafanaCreateAnnotationFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"POST",
+		"https://example.com/api/annotations",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	id, err := client.CreateAnnotation(context.Background(), types.GrafanaAnnotation{Text: "text"})
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Zero(t, id)
+}
+
+//nolint:paralleltest
+func TestGrafanaCreateAnnotationOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterMatcherResponder(
+		"POST",
+		"https://example.com/api/annotations",
+		httpmock.BodyContainsString(`"text":"text"`),
+		httpmock.NewStringResponder(200, `{"id":5,"message":"Annotation added"}`))
+
+	id, err := client.CreateAnnotation(context.Background(), types.GrafanaAnnotation{Text: "text"})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), id)
+}
+
+//nolint:paralleltest
+func TestGrafanaGetAnnotationsFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/annotations?type=annotation&limit=10",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	annotations, err := client.GetAnnotations(context.Background(), 10)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Empty(t, annotations)
+}
+
+//nolint:paralleltest
+func TestGrafanaGetAnnotationsOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	logger := loggerPkg.GetNopLogger()
+	config := configPkg.GrafanaConfig{URL: "https://example.com"}
+	client := InitGrafana(config, logger)
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/annotations?type=annotation&limit=10",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-annotations-ok.json")))
+
+	annotations, err := client.GetAnnotations(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, annotations, 2)
+	require.Equal(t, "disk-graphs", annotations[0].DashboardUID)
+	require.Equal(t, []string{"incident", "disk"}, annotations[0].Tags)
+}
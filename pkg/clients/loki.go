@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main/pkg/config"
+	"main/pkg/http"
+	"main/pkg/types"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Loki is a client for the Loki query API, either of a Loki instance
+// itself, or of a Grafana datasource via the datasource proxy.
+type Loki struct {
+	URL    string
+	Auth   *http.Auth
+	Logger zerolog.Logger
+	Client *http.Client
+}
+
+func InitLoki(url string, auth *http.Auth, httpConfig config.HTTPConfig, logger *zerolog.Logger) *Loki {
+	return &Loki{
+		URL:    url,
+		Auth:   auth,
+		Logger: logger.With().Str("component", "loki").Logger(),
+		Client: http.NewClient(logger, "loki", httpConfig),
+	}
+}
+
+// QueryRange returns at most limit latest log lines matching the query
+// within the time range, sorted from the oldest to the newest.
+func (l *Loki) QueryRange(
+	ctx context.Context,
+	query string,
+	start time.Time,
+	end time.Time,
+	limit int,
+) ([]types.LogLine, error) {
+	params := url.Values{}
+	params.Add("query", query)
+	params.Add("start", strconv.FormatInt(start.UnixNano(), 10))
+	params.Add("end", strconv.FormatInt(end.UnixNano(), 10))
+	params.Add("limit", strconv.Itoa(limit))
+	params.Add("direction", "backward")
+
+	response := types.LokiQueryResponse{}
+	if err := l.Client.Get(ctx, l.URL+"/loki/api/v1/query_range?"+params.Encode(), &response, l.Auth); err != nil {
+		return nil, err
+	}
+
+	if response.Status != "success" {
+		if response.Error != "" {
+			return nil, fmt.Errorf("%s: %s", response.ErrorType, response.Error)
+		}
+
+		return nil, errors.New("query failed")
+	}
+
+	return response.Data.GetLines()
+}
@@ -0,0 +1,86 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	loggerPkg "main/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest
+func TestLokiQueryRangeFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := InitLoki("https://loki.com", nil, configPkg.HTTPConfig{}, loggerPkg.GetNopLogger())
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://loki.com/loki/api/v1/query_range?direction=backward&end=1704110600000000000&limit=10&query=%7Bapp%3D%22api%22%7D&start=1704106800000000000",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	lines, err := client.QueryRange(context.Background(), "{app=\"api\"}", time.Unix(1704106800, 0), time.Unix(1704110600, 0), 10)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Empty(t, lines)
+}
+
+//nolint:paralleltest
+func TestLokiQueryRangeError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := InitLoki("https://loki.com", nil, configPkg.HTTPConfig{}, loggerPkg.GetNopLogger())
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://loki.com/loki/api/v1/query_range",
+		httpmock.NewStringResponder(200, `{"status":"error","errorType":"bad_data","error":"parse error"}`))
+
+	lines, err := client.QueryRange(context.Background(), "{app=", time.Unix(1704106800, 0), time.Unix(1704110600, 0), 10)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "bad_data: parse error")
+	require.Empty(t, lines)
+}
+
+//nolint:paralleltest
+func TestLokiQueryRangeFailedWithoutError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := InitLoki("https://loki.com", nil, configPkg.HTTPConfig{}, loggerPkg.GetNopLogger())
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://loki.com/loki/api/v1/query_range",
+		httpmock.NewStringResponder(200, `{"status":"error"}`))
+
+	lines, err := client.QueryRange(context.Background(), "{app=\"api\"}", time.Unix(1704106800, 0), time.Unix(1704110600, 0), 10)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "query failed")
+	require.Empty(t, lines)
+}
+
+//nolint:paralleltest
+func TestLokiQueryRangeOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := InitLoki("https://loki.com", nil, configPkg.HTTPConfig{}, loggerPkg.GetNopLogger())
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://loki.com/loki/api/v1/query_range",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("loki-query-streams.json")))
+
+	lines, err := client.QueryRange(context.Background(), "{app=\"api\"}", time.Unix(1704106800, 0), time.Unix(1704110600, 0), 10)
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	require.Equal(t, "starting", lines[0].Line)
+}
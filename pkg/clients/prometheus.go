@@ -0,0 +1,84 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main/pkg/config"
+	"main/pkg/http"
+	"main/pkg/types"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Prometheus is a client for the Prometheus query API, either of a Prometheus
+// instance itself, or of a Grafana datasource via the datasource proxy.
+type Prometheus struct {
+	URL    string
+	Auth   *http.Auth
+	Logger zerolog.Logger
+	Client *http.Client
+}
+
+func InitPrometheus(
+	name string,
+	url string,
+	auth *http.Auth,
+	httpConfig config.HTTPConfig,
+	logger *zerolog.Logger,
+) *Prometheus {
+	return &Prometheus{
+		URL:    url,
+		Auth:   auth,
+		Logger: logger.With().Str("component", "prometheus").Logger(),
+		Client: http.NewClient(logger, "prometheus:"+name, httpConfig),
+	}
+}
+
+func (p *Prometheus) Query(ctx context.Context, query string, at time.Time) (*types.PrometheusQueryData, error) {
+	params := url.Values{}
+	params.Add("query", query)
+	params.Add("time", formatTimestamp(at))
+
+	return p.doQuery(ctx, "/api/v1/query?"+params.Encode())
+}
+
+func (p *Prometheus) QueryRange(
+	ctx context.Context,
+	query string,
+	start time.Time,
+	end time.Time,
+	step time.Duration,
+) (*types.PrometheusQueryData, error) {
+	params := url.Values{}
+	params.Add("query", query)
+	params.Add("start", formatTimestamp(start))
+	params.Add("end", formatTimestamp(end))
+	params.Add("step", strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+
+	return p.doQuery(ctx, "/api/v1/query_range?"+params.Encode())
+}
+
+func (p *Prometheus) doQuery(ctx context.Context, relativeURL string) (*types.PrometheusQueryData, error) {
+	response := types.PrometheusQueryResponse{}
+	if err := p.Client.Get(ctx, p.URL+relativeURL, &response, p.Auth); err != nil {
+		return nil, err
+	}
+
+	if response.Status != "success" {
+		if response.Error != "" {
+			return nil, fmt.Errorf("%s: %s", response.ErrorType, response.Error)
+		}
+
+		return nil, errors.New("query failed")
+	}
+
+	return &response.Data, nil
+}
+
+func formatTimestamp(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
@@ -0,0 +1,112 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"main/assets"
+	configPkg "main/pkg/config"
+	"main/pkg/http"
+	loggerPkg "main/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest
+func TestPrometheusQueryFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := InitPrometheus("Prometheus", "https://prometheus.com", nil, configPkg.HTTPConfig{}, loggerPkg.GetNopLogger())
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/query?query=up&time=1704110400.123",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	data, err := client.Query(context.Background(), "up", time.UnixMilli(1704110400123))
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Nil(t, data)
+}
+
+//nolint:paralleltest
+func TestPrometheusQueryError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := InitPrometheus("Prometheus", "https://prometheus.com", nil, configPkg.HTTPConfig{}, loggerPkg.GetNopLogger())
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/query?query=up%7B&time=1704110400",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-query-error.json")))
+
+	data, err := client.Query(context.Background(), "up{", time.Unix(1704110400, 0))
+	require.Error(t, err)
+	require.ErrorContains(t, err, "bad_data: invalid parameter")
+	require.Nil(t, data)
+}
+
+//nolint:paralleltest
+func TestPrometheusQueryFailedWithoutError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := InitPrometheus("Prometheus", "https://prometheus.com", nil, configPkg.HTTPConfig{}, loggerPkg.GetNopLogger())
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/query?query=up&time=1704110400",
+		httpmock.NewStringResponder(200, `{"status":"error"}`))
+
+	data, err := client.Query(context.Background(), "up", time.Unix(1704110400, 0))
+	require.Error(t, err)
+	require.ErrorContains(t, err, "query failed")
+	require.Nil(t, data)
+}
+
+//nolint:paralleltest
+func TestPrometheusQueryOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := InitPrometheus(
+		"Prometheus",
+		"https://prometheus.com",
+		&http.Auth{Username: "admin", Password: "admin"},
+		configPkg.HTTPConfig{},
+		loggerPkg.GetNopLogger(),
+	)
+
+	httpmock.RegisterMatcherResponder(
+		"GET",
+		"https://prometheus.com/api/v1/query?query=up&time=1704110400",
+		httpmock.HeaderIs("Authorization", "Basic YWRtaW46YWRtaW4="),
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-query-vector.json")))
+
+	data, err := client.Query(context.Background(), "up", time.Unix(1704110400, 0))
+	require.NoError(t, err)
+	require.NotNil(t, data)
+	require.Equal(t, "vector", data.ResultType)
+}
+
+//nolint:paralleltest
+func TestPrometheusQueryRangeOk(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	client := InitPrometheus("Prometheus", "https://prometheus.com", nil, configPkg.HTTPConfig{}, loggerPkg.GetNopLogger())
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://prometheus.com/api/v1/query_range?end=1704110580&query=up&start=1704110400&step=60",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("prometheus-query-range.json")))
+
+	data, err := client.QueryRange(context.Background(), "up", time.Unix(1704110400, 0), time.Unix(1704110580, 0), time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, data)
+	require.Equal(t, "matrix", data.ResultType)
+}
@@ -1,20 +1,43 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"main/pkg/utils/normalize"
+	"net/url"
+	"os"
+	"slices"
 	"time"
 
 	"github.com/guregu/null/v5"
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
 )
 
+// MaxNameLength is the max length of the normalized instance name. Names are used
+// as a part of callback prefixes, and Telegram limits the callback data to 64 bytes,
+// so buttons with these prefixes only get short cache keys as data, not silence IDs.
+const MaxNameLength = 20
+
 type Config struct {
-	Timezone     string              `default:"Etc/GMT"   yaml:"timezone"`
-	Log          LogConfig           `yaml:"log"`
-	CachePath    string              `yaml:"cache-path"`
-	Telegram     TelegramConfig      `yaml:"telegram"`
-	Grafana      GrafanaConfig       `yaml:"grafana"`
-	Alertmanager *AlertmanagerConfig `yaml:"alertmanager"`
-	Prometheus   *PrometheusConfig   `yaml:"prometheus"`
+	Timezone         string                         `default:"Etc/GMT"   yaml:"timezone"`
+	Log              LogConfig                      `yaml:"log"`
+	CachePath        string                         `yaml:"cache-path"`
+	AuditLogPath     string                         `yaml:"audit-log-path"`
+	Telegram         TelegramConfig                 `yaml:"telegram"`
+	Grafana          ConfigList[GrafanaConfig]      `default:"[{}]"     yaml:"grafana"`
+	Alertmanager     ConfigList[AlertmanagerConfig] `yaml:"alertmanager"`
+	Prometheus       ConfigList[PrometheusConfig]   `yaml:"prometheus"`
+	Rulers           ConfigList[RulerConfig]        `yaml:"rulers"`
+	Loki             *LokiConfig                    `yaml:"loki"`
+	Notifications    *NotificationsConfig           `yaml:"notifications"`
+	Webhook          *WebhookConfig                 `yaml:"webhook"`
+	Metrics          *MetricsConfig                 `yaml:"metrics"`
+	SilenceReminders *SilenceRemindersConfig        `yaml:"silence_reminders"`
+	Reports          []ReportConfig                 `yaml:"reports"`
+	Annotations      *AnnotationsConfig             `yaml:"annotations"`
+	DashboardIndex   DashboardIndexConfig           `yaml:"dashboard_index"`
 }
 
 type LogConfig struct {
@@ -23,11 +46,161 @@ type LogConfig struct {
 }
 
 type TelegramConfig struct {
-	Token  string  `yaml:"token"`
-	Admins []int64 `yaml:"admins"`
+	Token  string      `yaml:"token"`
+	Admins []int64     `yaml:"admins"`
+	Roles  RolesConfig `yaml:"roles"`
+}
+
+type RolesConfig struct {
+	Viewers            RoleConfig    `yaml:"viewers"`
+	Silencers          RoleConfig    `yaml:"silencers"`
+	Admins             RoleConfig    `yaml:"admins"`
+	MaxSilenceDuration time.Duration `yaml:"max_silence_duration"`
+}
+
+type RoleConfig struct {
+	Users []int64 `yaml:"users"`
+	Chats []int64 `yaml:"chats"`
+}
+
+func (c RoleConfig) Contains(userID, chatID int64) bool {
+	return slices.Contains(c.Users, userID) || slices.Contains(c.Chats, chatID)
+}
+
+// IsEmpty returns true if there are neither admins nor roles configured,
+// meaning anyone can access the bot.
+func (c TelegramConfig) IsEmpty() bool {
+	return len(c.Admins) == 0 &&
+		len(c.Roles.Viewers.Users) == 0 && len(c.Roles.Viewers.Chats) == 0 &&
+		len(c.Roles.Silencers.Users) == 0 && len(c.Roles.Silencers.Chats) == 0 &&
+		len(c.Roles.Admins.Users) == 0 && len(c.Roles.Admins.Chats) == 0
+}
+
+// ConfigList is a list of configs which can be specified in YAML either
+// as a list, or as a single object for backwards compatibility.
+type ConfigList[T any] []T
+
+func (l *ConfigList[T]) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.MappingNode {
+		var single T
+		if err := value.Decode(&single); err != nil {
+			return err
+		}
+
+		*l = ConfigList[T]{single}
+		return nil
+	}
+
+	var list []T
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+
+	*l = list
+	return nil
+}
+
+// HTTPConfig is a config for HTTP requests to an upstream, like Grafana or Prometheus.
+type HTTPConfig struct {
+	// Timeout is the max time a request can take, including reading its response.
+	Timeout time.Duration `default:"30s" yaml:"timeout"`
+	TLS     TLSConfig     `yaml:"tls"`
+	// ProxyURL is the HTTP(S) proxy to send requests through. If it is not set,
+	// the proxy from HTTP_PROXY, HTTPS_PROXY and NO_PROXY env variables is used.
+	ProxyURL string `yaml:"proxy_url"`
+	// Headers are added to every request to the upstream, like the ones
+	// required by an authenticating reverse proxy in front of it.
+	Headers map[string]string `yaml:"headers"`
+}
+
+func (c HTTPConfig) GetProxyURL() (*url.URL, error) {
+	proxyURL, err := url.Parse(c.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy_url: %s", err)
+	}
+
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy_url %q: scheme and host are required", c.ProxyURL)
+	}
+
+	return proxyURL, nil
+}
+
+func (c HTTPConfig) Validate() error {
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout should not be negative, got %s", c.Timeout)
+	}
+
+	if c.ProxyURL != "" {
+		if _, err := c.GetProxyURL(); err != nil {
+			return err
+		}
+	}
+
+	if !c.TLS.IsEmpty() {
+		if _, err := c.TLS.GetTLSConfig(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TLSConfig is a config for upstreams using certificates signed by a private CA,
+// or requiring the client certificate (mTLS).
+type TLSConfig struct {
+	// CAFile is the PEM bundle of CAs to verify the upstream certificate with,
+	// used instead of the system ones.
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+func (c TLSConfig) IsEmpty() bool {
+	return c.CAFile == "" && c.CertFile == "" && c.KeyFile == "" && !c.InsecureSkipVerify
+}
+
+// GetTLSConfig loads the CA bundle and the client certificate from files.
+func (c TLSConfig) GetTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		caBundle, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading tls ca_file: %s", err)
+		}
+
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("tls ca_file %s has no valid PEM certificates", c.CAFile)
+		}
+
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("both tls cert_file and key_file should be set")
+		}
+
+		certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading tls client certificate: %s", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
 }
 
 type GrafanaConfig struct {
+	HTTPConfig     `yaml:",inline"`
+	Name           string            `yaml:"name"`
 	URL            string            `default:"http://localhost:3000"                                 yaml:"url"`
 	User           string            `default:"admin"                                                 yaml:"user"`
 	Password       string            `default:"admin"                                                 yaml:"password"`
@@ -38,17 +211,246 @@ type GrafanaConfig struct {
 	MutesDurations []string          `default:"[\"1h\",\"8h\",\"24h\",\"168h\",\"99999h\"]"           yaml:"mutes_durations"`
 }
 
+func (c *GrafanaConfig) GetName() string {
+	if c == nil || c.Name == "" {
+		return "Grafana"
+	}
+
+	return c.Name
+}
+
 type PrometheusConfig struct {
-	URL      string `default:"http://localhost:9090" yaml:"url"`
-	User     string `default:"admin"                 yaml:"user"`
-	Password string `default:"admin"                 yaml:"password"`
+	HTTPConfig     `yaml:",inline"`
+	Name           string `yaml:"name"`
+	URL            string `default:"http://localhost:9090" yaml:"url"`
+	User           string `default:"admin"                 yaml:"user"`
+	Password       string `default:"admin"                 yaml:"password"`
+	SilenceManager string `yaml:"silence_manager"`
+}
+
+func (c *PrometheusConfig) GetName() string {
+	if c == nil || c.Name == "" {
+		return "Prometheus"
+	}
+
+	return c.Name
+}
+
+// GetHTTPConfig returns the HTTP config, or the empty one for a disabled instance.
+func (c *PrometheusConfig) GetHTTPConfig() HTTPConfig {
+	if c == nil {
+		return HTTPConfig{}
+	}
+
+	return c.HTTPConfig
+}
+
+// RulerConfig is a config for any ruler exposing the Prometheus-compatible
+// rules API, like Loki, Mimir, Cortex or vmalert.
+type RulerConfig struct {
+	HTTPConfig     `yaml:",inline"`
+	Name           string `yaml:"name"`
+	URL            string `yaml:"url"`
+	PathPrefix     string `yaml:"path_prefix"`
+	TenantID       string `yaml:"tenant_id"`
+	User           string `yaml:"user"`
+	Password       string `yaml:"password"`
+	Token          string `yaml:"token"`
+	SilenceManager string `yaml:"silence_manager"`
+}
+
+func (c *RulerConfig) GetName() string {
+	if c == nil || c.Name == "" {
+		return "Ruler"
+	}
+
+	return c.Name
+}
+
+// LokiConfig is a config for querying logs from Loki directly,
+// instead of using a Grafana Loki datasource.
+type LokiConfig struct {
+	HTTPConfig `yaml:",inline"`
+	URL        string `yaml:"url"`
+	TenantID   string `yaml:"tenant_id"`
+	User       string `yaml:"user"`
+	Password   string `yaml:"password"`
+	Token      string `yaml:"token"`
 }
 
 type AlertmanagerConfig struct {
-	URL            string   `default:"http://localhost:9093"                       yaml:"url"`
-	User           string   `yaml:"user"`
-	Password       string   `yaml:"password"`
-	MutesDurations []string `default:"[\"1h\",\"8h\",\"24h\",\"168h\",\"99999h\"]" yaml:"mutes_durations"`
+	HTTPConfig     `yaml:",inline"`
+	Name           string                    `yaml:"name"`
+	URL            string                    `default:"http://localhost:9093"                       yaml:"url"`
+	User           string                    `yaml:"user"`
+	Password       string                    `yaml:"password"`
+	MutesDurations []string                  `default:"[\"1h\",\"8h\",\"24h\",\"168h\",\"99999h\"]" yaml:"mutes_durations"`
+	Alerts         *AlertmanagerAlertsConfig `yaml:"alerts"`
+}
+
+// AlertmanagerAlertsConfig enables using Alertmanager as an alert source,
+// with filters passed to its /api/v2/alerts endpoint.
+type AlertmanagerAlertsConfig struct {
+	Active    null.Bool `default:"true"  yaml:"active"`
+	Silenced  null.Bool `default:"false" yaml:"silenced"`
+	Inhibited null.Bool `default:"false" yaml:"inhibited"`
+	Receiver  string    `yaml:"receiver"`
+}
+
+func (c *AlertmanagerConfig) GetName() string {
+	if c == nil || c.Name == "" {
+		return "Alertmanager"
+	}
+
+	return c.Name
+}
+
+// GetHTTPConfig returns the HTTP config, or the empty one for a disabled instance.
+func (c *AlertmanagerConfig) GetHTTPConfig() HTTPConfig {
+	if c == nil {
+		return HTTPConfig{}
+	}
+
+	return c.HTTPConfig
+}
+
+type SilenceRemindersConfig struct {
+	Interval     time.Duration `default:"1m"  yaml:"interval"`
+	RemindBefore time.Duration `default:"30m" yaml:"remind_before"`
+}
+
+type DashboardIndexConfig struct {
+	Enabled                  null.Bool     `default:"true" yaml:"enabled"`
+	RefreshInterval          time.Duration `default:"5m"   yaml:"refresh_interval"`
+	DashboardRefreshInterval time.Duration `default:"1h"   yaml:"dashboard_refresh_interval"`
+}
+
+type ReportConfig struct {
+	Name          string              `yaml:"name"`
+	Schedule      string              `yaml:"schedule"`
+	Chats         []int64             `yaml:"chats"`
+	RenderOptions map[string]string   `yaml:"render_options"`
+	Panels        []ReportPanelConfig `yaml:"panels"`
+}
+
+// ReportPanelConfig is a panel to render in a report, specified either
+// by its dashboard UID and panel ID, or by its name, like in /render.
+type ReportPanelConfig struct {
+	Dashboard string `yaml:"dashboard"`
+	PanelID   int    `yaml:"panel_id"`
+	Name      string `yaml:"name"`
+}
+
+func (c ReportPanelConfig) Validate() error {
+	if c.Name != "" && (c.Dashboard != "" || c.PanelID != 0) {
+		return fmt.Errorf("either name, or dashboard and panel_id should be set, not both")
+	}
+
+	if c.Name == "" && (c.Dashboard == "" || c.PanelID == 0) {
+		return fmt.Errorf("either name, or dashboard and panel_id should be set")
+	}
+
+	return nil
+}
+
+func (c ReportConfig) Validate() error {
+	if _, err := cron.ParseStandard(c.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule: %s", err)
+	}
+
+	if len(c.Chats) == 0 {
+		return fmt.Errorf("no chats")
+	}
+
+	if len(c.Panels) == 0 {
+		return fmt.Errorf("no panels")
+	}
+
+	for index, panel := range c.Panels {
+		if err := panel.Validate(); err != nil {
+			return fmt.Errorf("panel #%d: %s", index, err)
+		}
+	}
+
+	return nil
+}
+
+// AnnotationsConfig enables adding Grafana annotations on bot actions,
+// so it is visible on graphs when alerts were muted.
+type AnnotationsConfig struct {
+	Silences    bool     `yaml:"silences"`
+	SilenceTags []string `default:"[\"silence\"]" yaml:"silence_tags"`
+}
+
+type NotificationsConfig struct {
+	Interval time.Duration `default:"1m" yaml:"interval"`
+	Chats    []int64       `yaml:"chats"`
+}
+
+type MetricsConfig struct {
+	ListenAddress string `default:":9580" yaml:"listen_address"`
+}
+
+type WebhookConfig struct {
+	ListenAddress string                  `default:":9500"            yaml:"listen_address"`
+	SecretHeader  string                  `default:"X-Webhook-Secret" yaml:"secret_header"`
+	Secret        string                  `yaml:"secret"`
+	AckTTL        time.Duration           `default:"24h"              yaml:"ack_ttl"`
+	Receivers     []WebhookReceiverConfig `yaml:"receivers"`
+}
+
+type WebhookReceiverConfig struct {
+	Name           string  `yaml:"name"`
+	Chats          []int64 `yaml:"chats"`
+	SilenceManager string  `yaml:"silence_manager"`
+}
+
+func (c *WebhookConfig) FindReceiver(name string) (*WebhookReceiverConfig, bool) {
+	for _, receiver := range c.Receivers {
+		if receiver.Name == name {
+			return &receiver, true
+		}
+	}
+
+	return nil, false
+}
+
+// GetGrafanas returns all Grafana instances, or a single one with the default name,
+// if none are configured, as the bot always needs Grafana for dashboards and rendering.
+func (c *Config) GetGrafanas() ConfigList[GrafanaConfig] {
+	if len(c.Grafana) == 0 {
+		return ConfigList[GrafanaConfig]{{}}
+	}
+
+	return c.Grafana
+}
+
+// GetMainGrafana returns the first Grafana instance, used for dashboards, datasources
+// and rendering, while all of them are used for alerts and silences.
+func (c *Config) GetMainGrafana() GrafanaConfig {
+	return c.GetGrafanas()[0]
+}
+
+// FindAlertmanager returns the Alertmanager instance with the given name.
+func (c *Config) FindAlertmanager(name string) (*AlertmanagerConfig, bool) {
+	for index := range c.Alertmanager {
+		if c.Alertmanager[index].GetName() == name {
+			return &c.Alertmanager[index], true
+		}
+	}
+
+	return nil, false
+}
+
+// GetPrometheusSilenceManager returns the name of the Alertmanager instance used
+// for silencing the Prometheus alerts, which is the first one if it is not set,
+// as Prometheus usually sends alerts to the only Alertmanager there is.
+func (c *Config) GetPrometheusSilenceManager(prometheus *PrometheusConfig) string {
+	if prometheus.SilenceManager == "" && len(c.Alertmanager) > 0 {
+		return c.Alertmanager[0].GetName()
+	}
+
+	return prometheus.SilenceManager
 }
 
 func (c *Config) Validate() error {
@@ -56,5 +458,169 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("error parsing timezone: %s", err)
 	}
 
+	if c.Telegram.Roles.MaxSilenceDuration < 0 {
+		return fmt.Errorf("max silence duration should not be negative, got %s", c.Telegram.Roles.MaxSilenceDuration)
+	}
+
+	if c.Notifications != nil && c.Notifications.Interval <= 0 {
+		return fmt.Errorf("notifications interval should be positive, got %s", c.Notifications.Interval)
+	}
+
+	if c.SilenceReminders != nil && c.SilenceReminders.Interval <= 0 {
+		return fmt.Errorf("silence reminders interval should be positive, got %s", c.SilenceReminders.Interval)
+	}
+
+	if c.SilenceReminders != nil && c.SilenceReminders.RemindBefore <= 0 {
+		return fmt.Errorf("silence reminders remind_before should be positive, got %s", c.SilenceReminders.RemindBefore)
+	}
+
+	if c.DashboardIndex.Enabled.Bool && c.DashboardIndex.RefreshInterval <= 0 {
+		return fmt.Errorf("dashboard index refresh interval should be positive, got %s", c.DashboardIndex.RefreshInterval)
+	}
+
+	if c.DashboardIndex.Enabled.Bool && c.DashboardIndex.DashboardRefreshInterval <= 0 {
+		return fmt.Errorf(
+			"dashboard index dashboard refresh interval should be positive, got %s",
+			c.DashboardIndex.DashboardRefreshInterval,
+		)
+	}
+
+	if c.Loki != nil && c.Loki.URL == "" {
+		return fmt.Errorf("loki has no url")
+	}
+
+	if c.Loki != nil {
+		if err := c.Loki.HTTPConfig.Validate(); err != nil {
+			return fmt.Errorf("loki: %s", err)
+		}
+	}
+
+	if c.Metrics != nil && c.Webhook != nil && c.Metrics.ListenAddress == c.Webhook.ListenAddress {
+		return fmt.Errorf("metrics and webhook should listen on different addresses, got %s", c.Metrics.ListenAddress)
+	}
+
+	reportsNames := make(map[string]bool, len(c.Reports))
+
+	for index, report := range c.Reports {
+		if report.Name == "" {
+			return fmt.Errorf("report #%d has no name", index)
+		}
+
+		if reportsNames[report.Name] {
+			return fmt.Errorf("report name %q is not unique", report.Name)
+		}
+
+		reportsNames[report.Name] = true
+
+		if err := report.Validate(); err != nil {
+			return fmt.Errorf("report %s is invalid: %s", report.Name, err)
+		}
+	}
+
+	alertSourcesNames := make([]string, 0)
+	silenceManagersNames := make([]string, 0)
+
+	for _, grafana := range c.GetGrafanas() {
+		if err := grafana.HTTPConfig.Validate(); err != nil {
+			return fmt.Errorf("grafana %s: %s", grafana.GetName(), err)
+		}
+
+		alertSourcesNames = append(alertSourcesNames, grafana.GetName())
+		silenceManagersNames = append(silenceManagersNames, grafana.GetName())
+	}
+
+	for _, prometheus := range c.Prometheus {
+		if err := prometheus.HTTPConfig.Validate(); err != nil {
+			return fmt.Errorf("prometheus %s: %s", prometheus.GetName(), err)
+		}
+
+		if _, found := c.FindAlertmanager(prometheus.SilenceManager); prometheus.SilenceManager != "" && !found {
+			return fmt.Errorf("prometheus %s silence manager %q is not an Alertmanager instance", prometheus.GetName(), prometheus.SilenceManager)
+		}
+
+		alertSourcesNames = append(alertSourcesNames, prometheus.GetName())
+	}
+
+	for _, alertmanager := range c.Alertmanager {
+		if err := alertmanager.HTTPConfig.Validate(); err != nil {
+			return fmt.Errorf("alertmanager %s: %s", alertmanager.GetName(), err)
+		}
+
+		silenceManagersNames = append(silenceManagersNames, alertmanager.GetName())
+
+		if alertmanager.Alerts != nil {
+			alertSourcesNames = append(alertSourcesNames, alertmanager.GetName())
+		}
+	}
+
+	for index, ruler := range c.Rulers {
+		if ruler.URL == "" {
+			return fmt.Errorf("ruler #%d has no url", index)
+		}
+
+		if err := ruler.HTTPConfig.Validate(); err != nil {
+			return fmt.Errorf("ruler %s: %s", ruler.GetName(), err)
+		}
+
+		if _, found := c.FindAlertmanager(ruler.SilenceManager); ruler.SilenceManager != "" && !found {
+			return fmt.Errorf("ruler %s silence manager %q is not an Alertmanager instance", ruler.GetName(), ruler.SilenceManager)
+		}
+
+		alertSourcesNames = append(alertSourcesNames, ruler.GetName())
+	}
+
+	if err := ValidateNames("alert source", alertSourcesNames); err != nil {
+		return err
+	}
+
+	if err := ValidateNames("silence manager", silenceManagersNames); err != nil {
+		return err
+	}
+
+	if c.Webhook != nil {
+		for index, receiver := range c.Webhook.Receivers {
+			if receiver.Name == "" {
+				return fmt.Errorf("webhook receiver #%d has no name", index)
+			}
+
+			if len(receiver.Chats) == 0 {
+				return fmt.Errorf("webhook receiver %s has no chats", receiver.Name)
+			}
+
+			if receiver.SilenceManager != "" && !slices.Contains(silenceManagersNames, receiver.SilenceManager) {
+				return fmt.Errorf(
+					"webhook receiver %s silence manager %q is not a Grafana or Alertmanager instance",
+					receiver.Name,
+					receiver.SilenceManager,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateNames checks that all names can be used in commands and callback prefixes,
+// and that they won't clash with each other after being normalized.
+func ValidateNames(kind string, names []string) error {
+	normalizedNames := make(map[string]string, len(names))
+
+	for _, name := range names {
+		normalizedName := normalize.NormalizeCommand(name)
+		if normalizedName == "" {
+			return fmt.Errorf("%s name %q should contain at least one latin letter or digit", kind, name)
+		}
+
+		if len(normalizedName) > MaxNameLength {
+			return fmt.Errorf("%s name %q is too long, max length is %d", kind, name, MaxNameLength)
+		}
+
+		if existingName, found := normalizedNames[normalizedName]; found {
+			return fmt.Errorf("%s names %q and %q are not unique", kind, existingName, name)
+		}
+
+		normalizedNames[normalizedName] = name
+	}
+
 	return nil
 }
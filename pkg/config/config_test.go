@@ -2,8 +2,12 @@ package config
 
 import (
 	"testing"
+	"time"
 
+	"github.com/creasty/defaults"
+	"github.com/guregu/null/v5"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestLoadConfigInvalidTimezone(t *testing.T) {
@@ -22,3 +26,559 @@ func TestLoadConfigOk(t *testing.T) {
 	err := config.Validate()
 	require.NoError(t, err)
 }
+
+func TestLoadConfigInvalidNotificationsInterval(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{Timezone: "Etc/GMT", Notifications: &NotificationsConfig{}}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "notifications interval should be positive")
+}
+
+func TestLoadConfigWebhookReceiverWithoutName(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Webhook:  &WebhookConfig{Receivers: []WebhookReceiverConfig{{Chats: []int64{1}}}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "webhook receiver #0 has no name")
+}
+
+func TestLoadConfigWebhookReceiverWithoutChats(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Webhook:  &WebhookConfig{Receivers: []WebhookReceiverConfig{{Name: "receiver"}}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "webhook receiver receiver has no chats")
+}
+
+func TestLoadConfigWebhookReceiverUnknownSilenceManager(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:     "Etc/GMT",
+		Alertmanager: []AlertmanagerConfig{{Name: "EU"}},
+		Webhook: &WebhookConfig{Receivers: []WebhookReceiverConfig{
+			{Name: "receiver", Chats: []int64{1}, SilenceManager: "US"},
+		}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "webhook receiver receiver silence manager \"US\" is not a Grafana or Alertmanager instance")
+}
+
+func TestLoadConfigWebhookReceiverSilenceManagerOk(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:     "Etc/GMT",
+		Grafana:      []GrafanaConfig{{Name: "Grafana"}},
+		Alertmanager: []AlertmanagerConfig{{Name: "EU"}},
+		Webhook: &WebhookConfig{Receivers: []WebhookReceiverConfig{
+			{Name: "eu", Chats: []int64{1}, SilenceManager: "EU"},
+			{Name: "grafana", Chats: []int64{1}, SilenceManager: "Grafana"},
+			{Name: "default", Chats: []int64{1}},
+		}},
+	}
+	require.NoError(t, config.Validate())
+}
+
+func TestWebhookConfigFindReceiver(t *testing.T) {
+	t.Parallel()
+
+	config := &WebhookConfig{Receivers: []WebhookReceiverConfig{{Name: "receiver", Chats: []int64{1}}}}
+
+	receiver, found := config.FindReceiver("receiver")
+	require.True(t, found)
+	require.Equal(t, "receiver", receiver.Name)
+
+	receiver, found = config.FindReceiver("unknown")
+	require.False(t, found)
+	require.Nil(t, receiver)
+}
+
+func TestLoadConfigAlertSourcesNamesNotUnique(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:   "Etc/GMT",
+		Prometheus: ConfigList[PrometheusConfig]{{Name: "EU"}, {Name: "eu"}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "alert source names \"EU\" and \"eu\" are not unique")
+}
+
+func TestLoadConfigSilenceManagersNamesNotUnique(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:     "Etc/GMT",
+		Alertmanager: ConfigList[AlertmanagerConfig]{{Name: "Grafana"}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "silence manager names \"Grafana\" and \"Grafana\" are not unique")
+}
+
+func TestLoadConfigNamesWithDifferentKindsOk(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:     "Etc/GMT",
+		Grafana:      ConfigList[GrafanaConfig]{{}, {Name: "EU Grafana"}},
+		Prometheus:   ConfigList[PrometheusConfig]{{Name: "EU"}},
+		Alertmanager: ConfigList[AlertmanagerConfig]{{Name: "EU"}},
+	}
+	err := config.Validate()
+	require.NoError(t, err)
+}
+
+func TestLoadConfigAlertmanagerAlertSourceNameNotUnique(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:     "Etc/GMT",
+		Prometheus:   ConfigList[PrometheusConfig]{{Name: "EU"}},
+		Alertmanager: ConfigList[AlertmanagerConfig]{{Name: "EU", Alerts: &AlertmanagerAlertsConfig{}}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "alert source names \"EU\" and \"EU\" are not unique")
+}
+
+func TestLoadConfigRulerWithoutURL(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Rulers:   ConfigList[RulerConfig]{{Name: "Loki"}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "ruler #0 has no url")
+}
+
+func TestLoadConfigRulerUnknownSilenceManager(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:     "Etc/GMT",
+		Alertmanager: ConfigList[AlertmanagerConfig]{{Name: "EU"}},
+		Rulers:       ConfigList[RulerConfig]{{Name: "Loki", URL: "http://loki:3100", SilenceManager: "US"}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "ruler Loki silence manager \"US\" is not an Alertmanager instance")
+}
+
+func TestLoadConfigPrometheusUnknownSilenceManager(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:     "Etc/GMT",
+		Alertmanager: ConfigList[AlertmanagerConfig]{{Name: "EU"}},
+		Prometheus:   ConfigList[PrometheusConfig]{{Name: "US Prometheus", SilenceManager: "US"}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "prometheus US Prometheus silence manager \"US\" is not an Alertmanager instance")
+}
+
+func TestConfigGetPrometheusSilenceManager(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{Alertmanager: ConfigList[AlertmanagerConfig]{{Name: "EU"}, {Name: "US"}}}
+	require.Equal(t, "EU", config.GetPrometheusSilenceManager(&PrometheusConfig{}))
+	require.Equal(t, "US", config.GetPrometheusSilenceManager(&PrometheusConfig{SilenceManager: "US"}))
+
+	config = &Config{}
+	require.Empty(t, config.GetPrometheusSilenceManager(&PrometheusConfig{}))
+}
+
+func TestConfigGetGrafanas(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{}
+	require.Len(t, config.GetGrafanas(), 1)
+	require.Equal(t, "Grafana", config.GetGrafanas()[0].GetName())
+
+	config = &Config{Grafana: ConfigList[GrafanaConfig]{{Name: "EU"}, {Name: "US"}}}
+	require.Len(t, config.GetGrafanas(), 2)
+	require.Equal(t, "EU", config.GetMainGrafana().Name)
+}
+
+func TestLoadConfigRulerNameNotUnique(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:   "Etc/GMT",
+		Prometheus: ConfigList[PrometheusConfig]{{Name: "Metrics"}},
+		Rulers:     ConfigList[RulerConfig]{{Name: "metrics", URL: "http://mimir:8080"}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "alert source names \"Metrics\" and \"metrics\" are not unique")
+}
+
+func TestLoadConfigRulerOk(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:     "Etc/GMT",
+		Alertmanager: ConfigList[AlertmanagerConfig]{{Name: "EU"}},
+		Rulers:       ConfigList[RulerConfig]{{Name: "Loki", URL: "http://loki:3100", SilenceManager: "EU"}},
+	}
+	require.NoError(t, config.Validate())
+	require.Equal(t, "Ruler", (&RulerConfig{}).GetName())
+}
+
+func TestValidateNamesEmpty(t *testing.T) {
+	t.Parallel()
+
+	err := ValidateNames("alert source", []string{"!!!"})
+	require.Error(t, err)
+	require.ErrorContains(t, err, "should contain at least one latin letter or digit")
+}
+
+func TestValidateNamesTooLong(t *testing.T) {
+	t.Parallel()
+
+	err := ValidateNames("alert source", []string{"a very very long alert source name"})
+	require.Error(t, err)
+	require.ErrorContains(t, err, "is too long")
+}
+
+func TestConfigListUnmarshalSingle(t *testing.T) {
+	t.Parallel()
+
+	var list ConfigList[PrometheusConfig]
+	err := yaml.Unmarshal([]byte("url: http://localhost:9090"), &list)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "http://localhost:9090", list[0].URL)
+}
+
+func TestHTTPConfigUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	config := Config{}
+	err := yaml.Unmarshal([]byte("prometheus:\n- url: http://localhost:9090\n  timeout: 5s\n- name: us"), &config)
+	require.NoError(t, err)
+	require.Len(t, config.Prometheus, 2)
+
+	defaults.MustSet(&config)
+	require.Equal(t, 5*time.Second, config.Prometheus[0].Timeout)
+	require.Equal(t, 30*time.Second, config.Prometheus[1].Timeout)
+	require.Equal(t, 30*time.Second, config.Grafana[0].Timeout)
+}
+
+func TestGrafanaConfigUnmarshalList(t *testing.T) {
+	t.Parallel()
+
+	config := Config{}
+	err := yaml.Unmarshal([]byte("grafana:\n- name: EU\n  url: http://grafana-eu:3000\n- name: US"), &config)
+	require.NoError(t, err)
+	require.Len(t, config.Grafana, 2)
+
+	defaults.MustSet(&config)
+	require.Equal(t, "http://grafana-eu:3000", config.Grafana[0].URL)
+	require.Equal(t, "http://localhost:3000", config.Grafana[1].URL)
+}
+
+func TestDashboardIndexConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	config := Config{}
+	require.NoError(t, yaml.Unmarshal([]byte("timezone: Etc/GMT"), &config))
+
+	defaults.MustSet(&config)
+	require.True(t, config.DashboardIndex.Enabled.Bool)
+	require.Equal(t, 5*time.Minute, config.DashboardIndex.RefreshInterval)
+	require.Equal(t, time.Hour, config.DashboardIndex.DashboardRefreshInterval)
+
+	disabled := Config{}
+	require.NoError(t, yaml.Unmarshal([]byte("dashboard_index:\n  enabled: false"), &disabled))
+
+	defaults.MustSet(&disabled)
+	require.False(t, disabled.DashboardIndex.Enabled.Bool)
+}
+
+func TestConfigListUnmarshalList(t *testing.T) {
+	t.Parallel()
+
+	var list ConfigList[PrometheusConfig]
+	err := yaml.Unmarshal([]byte("- name: eu\n- name: us"), &list)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "eu", list[0].Name)
+	require.Equal(t, "us", list[1].Name)
+}
+
+func TestConfigListUnmarshalInvalid(t *testing.T) {
+	t.Parallel()
+
+	var list ConfigList[PrometheusConfig]
+	err := yaml.Unmarshal([]byte("test"), &list)
+	require.Error(t, err)
+}
+
+func TestConfigGetName(t *testing.T) {
+	t.Parallel()
+
+	var prometheus *PrometheusConfig
+	require.Equal(t, "Prometheus", prometheus.GetName())
+	require.Equal(t, "EU", (&AlertmanagerConfig{Name: "EU"}).GetName())
+	require.Equal(t, "Grafana", (&GrafanaConfig{}).GetName())
+}
+
+func TestLoadConfigNegativeMaxSilenceDuration(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Telegram: TelegramConfig{Roles: RolesConfig{MaxSilenceDuration: -time.Hour}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "max silence duration should not be negative")
+}
+
+func TestTelegramConfigIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, TelegramConfig{}.IsEmpty())
+	require.False(t, TelegramConfig{Admins: []int64{1}}.IsEmpty())
+	require.False(t, TelegramConfig{Roles: RolesConfig{Viewers: RoleConfig{Chats: []int64{1}}}}.IsEmpty())
+}
+
+func TestRoleConfigContains(t *testing.T) {
+	t.Parallel()
+
+	config := RoleConfig{Users: []int64{1}, Chats: []int64{2}}
+	require.True(t, config.Contains(1, 3))
+	require.True(t, config.Contains(3, 2))
+	require.False(t, config.Contains(3, 3))
+}
+
+func TestLoadConfigMetricsAndWebhookSameAddress(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Webhook:  &WebhookConfig{ListenAddress: ":9500"},
+		Metrics:  &MetricsConfig{ListenAddress: ":9500"},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "metrics and webhook should listen on different addresses")
+}
+
+func TestLoadConfigSilenceRemindersInvalidInterval(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:         "Etc/GMT",
+		SilenceReminders: &SilenceRemindersConfig{Interval: 0, RemindBefore: time.Minute},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "silence reminders interval should be positive")
+}
+
+func TestLoadConfigSilenceRemindersInvalidRemindBefore(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:         "Etc/GMT",
+		SilenceReminders: &SilenceRemindersConfig{Interval: time.Minute, RemindBefore: -time.Minute},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "silence reminders remind_before should be positive")
+}
+
+func TestLoadConfigDashboardIndexInvalidRefreshInterval(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone:       "Etc/GMT",
+		DashboardIndex: DashboardIndexConfig{Enabled: null.BoolFrom(true), RefreshInterval: -time.Minute},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "dashboard index refresh interval should be positive")
+}
+
+func TestLoadConfigDashboardIndexInvalidDashboardRefreshInterval(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		DashboardIndex: DashboardIndexConfig{
+			Enabled:         null.BoolFrom(true),
+			RefreshInterval: time.Minute,
+		},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "dashboard index dashboard refresh interval should be positive")
+}
+
+func TestLoadConfigReportWithoutName(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{Timezone: "Etc/GMT", Reports: []ReportConfig{{}}}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "report #0 has no name")
+}
+
+func TestLoadConfigReportNamesNotUnique(t *testing.T) {
+	t.Parallel()
+
+	report := ReportConfig{
+		Name:     "report",
+		Schedule: "0 9 * * *",
+		Chats:    []int64{1},
+		Panels:   []ReportPanelConfig{{Name: "panel"}},
+	}
+
+	config := &Config{Timezone: "Etc/GMT", Reports: []ReportConfig{report, report}}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "report name \"report\" is not unique")
+}
+
+func TestLoadConfigReportInvalid(t *testing.T) {
+	t.Parallel()
+
+	for expectedErr, report := range map[string]ReportConfig{
+		"invalid schedule": {Name: "report", Schedule: "0 9 * *"},
+		"no chats":         {Name: "report", Schedule: "0 9 * * *"},
+		"no panels":        {Name: "report", Schedule: "0 9 * * *", Chats: []int64{1}},
+		"panel #0: either name, or dashboard and panel_id should be set": {
+			Name:     "report",
+			Schedule: "0 9 * * *",
+			Chats:    []int64{1},
+			Panels:   []ReportPanelConfig{{Dashboard: "dashboard"}},
+		},
+		"panel #0: either name, or dashboard and panel_id should be set, not both": {
+			Name:     "report",
+			Schedule: "0 9 * * *",
+			Chats:    []int64{1},
+			Panels:   []ReportPanelConfig{{Dashboard: "dashboard", PanelID: 1, Name: "panel"}},
+		},
+	} {
+		config := &Config{Timezone: "Etc/GMT", Reports: []ReportConfig{report}}
+		err := config.Validate()
+		require.Error(t, err)
+		require.ErrorContains(t, err, "report report is invalid: "+expectedErr)
+	}
+}
+
+func TestLoadConfigReportOk(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Reports: []ReportConfig{{
+			Name:     "report",
+			Schedule: "0 9 * JAN-DEC MON-FRI",
+			Chats:    []int64{1},
+			Panels: []ReportPanelConfig{
+				{Name: "panel"},
+				{Dashboard: "dashboard", PanelID: 1},
+			},
+		}},
+	}
+	err := config.Validate()
+	require.NoError(t, err)
+}
+
+func TestLoadConfigLokiWithoutURL(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Loki:     &LokiConfig{TenantID: "tenant"},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "loki has no url")
+}
+
+func TestLoadConfigGrafanaNegativeTimeout(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Grafana:  []GrafanaConfig{{HTTPConfig: HTTPConfig{Timeout: -time.Second}}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "grafana Grafana: timeout should not be negative")
+}
+
+func TestLoadConfigAlertmanagerCertWithoutKey(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Alertmanager: []AlertmanagerConfig{{
+			HTTPConfig: HTTPConfig{TLS: TLSConfig{CertFile: "cert.pem"}},
+		}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "alertmanager Alertmanager: both tls cert_file and key_file should be set")
+}
+
+func TestLoadConfigPrometheusMissingCAFile(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Prometheus: []PrometheusConfig{{
+			HTTPConfig: HTTPConfig{TLS: TLSConfig{CAFile: "/nonexistent/ca.pem"}},
+		}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "prometheus Prometheus: error reading tls ca_file")
+}
+
+func TestLoadConfigLokiInvalidProxyURL(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Loki:     &LokiConfig{URL: "http://loki:3100", HTTPConfig: HTTPConfig{ProxyURL: "proxy:3128"}},
+	}
+	err := config.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "loki: invalid proxy_url")
+}
+
+func TestLoadConfigHTTPConfigOk(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{
+		Timezone: "Etc/GMT",
+		Grafana: []GrafanaConfig{{HTTPConfig: HTTPConfig{
+			ProxyURL: "http://proxy:3128",
+			TLS:      TLSConfig{InsecureSkipVerify: true},
+			Headers:  map[string]string{"X-Custom": "value"},
+		}}},
+	}
+	err := config.Validate()
+	require.NoError(t, err)
+}
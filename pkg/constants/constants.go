@@ -6,30 +6,62 @@ const (
 	SilenceMatcherEqual         string = "="
 	SilenceMatcherNotEqual      string = "!="
 
-	SilencesInOneMessage   = 5
-	AlertsInOneMessage     = 3
-	DashboardsInOneMessage = 5
-	PanelsInOneMessage     = 5
+	SilencesInOneMessage         = 5
+	AlertsInOneMessage           = 3
+	DashboardsInOneMessage       = 5
+	PanelsInOneMessage           = 5
+	AuditEntriesInOneMessage     = 5
+	VariableOptionsInOneMessage  = 10
+	SearchCandidatesInOneMessage = 5
 
-	GrafanaPaginatedFiringAlertsList    = "grafana_paginated_firing_alerts_list_"
-	PrometheusPaginatedFiringAlertsList = "prometheus_paginated_firing_alerts_list_"
-	GrafanaPaginatedSilencesList        = "grafana_paginated_silences_list_"
-	AlertmanagerPaginatedSilencesList   = "alertmanager_paginated_silences_list_"
-	GrafanaUnsilencePrefix              = "grafana_unsilence_"
-	AlertmanagerUnsilencePrefix         = "alertmanager_unsilence_"
-	GrafanaSilencePrefix                = "grafana_silence_"
-	AlertmanagerSilencePrefix           = "alertmanager_silence_"
-	GrafanaPrepareSilencePrefix         = "grafana_prepare_silence_"
-	AlertmanagerPrepareSilencePrefix    = "alertmanager_prepare_silence_"
-	GrafanaListSilencesCommand          = "grafana_silences"
-	AlertmanagerListSilencesCommand     = "alertmanager_silences"
-	GrafanaSilenceCommand               = "grafana_silence"
-	AlertmanagerSilenceCommand          = "alertmanager_silence"
-	GrafanaUnsilenceCommand             = "grafana_unsilence"
-	AlertmanagerUnsilenceCommand        = "alertmanager_unsilence"
+	// Telegram does not allow sending more media in one album.
+	MaxAlbumSize = 10
+	// Telegram rejects buttons with longer callback data, which includes
+	// the "\f" + prefix + "|" added by telebot.
+	MaxCallbackDataLength = 64
 
-	GrafanaRenderChooseDashboardPrefix = "render_choose_dashboard_"
-	GrafanaRenderChoosePanelPrefix     = "render_choose_panel_"
-	GrafanaRenderRenderPanelPrefix     = "render_render_panel"
-	ClearKeyboardPrefix                = "clear_keyboard_"
+	// These are suffixes, prefixed with an alert source or silence manager name,
+	// like "grafana_silence_" or "alertmanager_silences".
+	PaginatedFiringAlertsListSuffix = "paginated_firing_alerts_list_"
+	PaginatedSilencesListSuffix     = "paginated_silences_list_"
+	UnsilenceSuffix                 = "unsilence_"
+	SilenceSuffix                   = "silence_"
+	PrepareSilenceSuffix            = "prepare_silence_"
+	ListSilencesCommandSuffix       = "silences"
+	SilenceCommandSuffix            = "silence"
+	UnsilenceCommandSuffix          = "unsilence"
+	ExtendSilenceSuffix             = "extend_silence_"
+	EditSilenceCommandSuffix        = "edit_silence"
+	PrepareEditSilenceSuffix        = "prepare_edit_silence_"
+	EditSilenceSuffix               = "edit_silence_"
+
+	GrafanaRenderChooseDashboardPrefix  = "render_choose_dashboard_"
+	GrafanaRenderChoosePanelPrefix      = "render_choose_panel_"
+	GrafanaRenderRenderPanelPrefix      = "render_render_panel"
+	GrafanaRenderChooseVariablePrefix   = "render_choose_variable_"
+	GrafanaRenderVariablesPagePrefix    = "render_variables_page_"
+	GrafanaRenderAllPrefix              = "render_all_"
+	ClearKeyboardPrefix                 = "clear_keyboard_"
+	PaginatedAuditLogPrefix             = "paginated_audit_log_"
+	UnsubscribePrefix                   = "unsubscribe_"
+	AckAlertPrefix                      = "ack_alert_"
+	LogsMorePrefix                      = "logs_more_"
+	PauseRulePrefix                     = "pause_rule_"
+	GrafanaRenderChooseFoundPanelPrefix = "render_choose_found_panel_"
+	ShowDashboardPrefix                 = "show_dashboard_"
+	ShowAlertRulePrefix                 = "show_alert_rule_"
+	RenderChooseFoundDashboardPrefix    = "render_found_dashboard_"
+	AnnotateChooseDashboardPrefix       = "annotate_choose_dashboard_"
+
+	FiringAlertsSnapshotCachePrefix = "firing_alerts_snapshot_"
+	TrackedSilencesCacheKey         = "tracked_silences"
+	SubscriptionsCacheKey           = "subscriptions"
+	AlertAcksCacheKey               = "alert_acks"
+	AlertToAckCachePrefix           = "alert_to_ack_"
+	LogsQueryCachePrefix            = "logs_query_"
+	RuleToPauseCachePrefix          = "rule_to_pause_"
+	RenderPanelChoiceCachePrefix    = "render_panel_choice_"
+	AlertRuleChoiceCachePrefix      = "alert_rule_choice_"
+	RenderDashboardCachePrefix      = "render_dashboard_"
+	AnnotationChoiceCachePrefix     = "annotation_choice_"
 )
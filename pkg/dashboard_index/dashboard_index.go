@@ -0,0 +1,304 @@
+package dashboard_index
+
+import (
+	"context"
+	"main/pkg/clients"
+	configPkg "main/pkg/config"
+	"main/pkg/types"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+)
+
+// DashboardIndex keeps dashboards, their folders and panels in memory, so commands
+// like /render <panel> won't fetch every dashboard from Grafana on each call.
+// The dashboards list is refreshed in the background, and only new dashboards, the ones
+// with a changed version and the ones not fetched for a while are fetched again.
+// If it is disabled, all calls go to Grafana directly.
+type DashboardIndex struct {
+	Grafana *clients.Grafana
+	Config  *configPkg.DashboardIndexConfig
+	Logger  zerolog.Logger
+
+	// mutex guards the index data, refreshMutex makes sure only one refresh runs at a time.
+	mutex        sync.RWMutex
+	refreshMutex sync.Mutex
+
+	loaded     bool
+	dashboards types.GrafanaDashboardsInfo
+	details    map[string]indexedDashboard
+}
+
+type indexedDashboard struct {
+	Dashboard types.GrafanaDashboardResponse
+	FetchedAt time.Time
+}
+
+func NewDashboardIndex(
+	grafana *clients.Grafana,
+	config *configPkg.DashboardIndexConfig,
+	logger *zerolog.Logger,
+) *DashboardIndex {
+	return &DashboardIndex{
+		Grafana: grafana,
+		Config:  config,
+		Logger:  logger.With().Str("component", "dashboard_index").Logger(),
+		details: map[string]indexedDashboard{},
+	}
+}
+
+func (i *DashboardIndex) Enabled() bool {
+	return i.Config != nil && i.Config.Enabled.Bool
+}
+
+func (i *DashboardIndex) Start(ctx context.Context) {
+	if !i.Enabled() {
+		i.Logger.Debug().Msg("Dashboard index is disabled, not starting dashboard index refresher")
+		return
+	}
+
+	i.Logger.Info().
+		Dur("interval", i.Config.RefreshInterval).
+		Msg("Starting dashboard index refresher")
+
+	ticker := time.NewTicker(i.Config.RefreshInterval)
+	defer ticker.Stop()
+
+	i.refreshAndLog(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			i.Logger.Info().Msg("Stopping dashboard index refresher")
+			return
+		case <-ticker.C:
+			i.refreshAndLog(ctx)
+		}
+	}
+}
+
+func (i *DashboardIndex) refreshAndLog(ctx context.Context) {
+	if err := i.Refresh(ctx); err != nil {
+		i.Logger.Error().Err(err).Msg("Error refreshing dashboard index")
+	}
+}
+
+// Refresh fetches the dashboards list, then fetches the dashboards that need to be
+// fetched (see needsFetching) and removes the ones that no longer exist. If a single
+// dashboard cannot be fetched, its previous state is kept until the next refresh.
+func (i *DashboardIndex) Refresh(ctx context.Context) error {
+	i.refreshMutex.Lock()
+	defer i.refreshMutex.Unlock()
+
+	return i.refreshUnsafe(ctx)
+}
+
+func (i *DashboardIndex) refreshUnsafe(ctx context.Context) error {
+	dashboards, err := i.Grafana.GetAllDashboards(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	i.mutex.RLock()
+	toFetch := make([]bool, len(dashboards))
+	for index, dashboard := range dashboards {
+		previous, hasPrevious := i.details[dashboard.UID]
+		toFetch[index] = !hasPrevious || i.needsFetching(dashboard, previous, now)
+	}
+	i.mutex.RUnlock()
+
+	fetched := make([]*types.GrafanaDashboardResponse, len(dashboards))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(clients.MaxConcurrentDashboardFetches)
+
+	for index, dashboard := range dashboards {
+		if !toFetch[index] {
+			continue
+		}
+
+		group.Go(func() error {
+			dashboardEnriched, dashboardErr := i.Grafana.GetDashboard(groupCtx, dashboard.UID)
+			if dashboardErr != nil {
+				i.Logger.Warn().
+					Err(dashboardErr).
+					Str("dashboard", dashboard.UID).
+					Msg("Error fetching dashboard, keeping the previous version")
+				return nil
+			}
+
+			fetched[index] = dashboardEnriched
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	details := make(map[string]indexedDashboard, len(dashboards))
+	fetchedCount := 0
+	updatedCount := 0
+
+	for index, dashboard := range dashboards {
+		previous, hasPrevious := i.details[dashboard.UID]
+
+		switch {
+		case fetched[index] == nil && hasPrevious:
+			details[dashboard.UID] = previous
+		case fetched[index] == nil:
+			continue
+		case hasPrevious && previous.Dashboard.Dashboard.Version == fetched[index].Dashboard.Version:
+			details[dashboard.UID] = indexedDashboard{Dashboard: previous.Dashboard, FetchedAt: now}
+			fetchedCount++
+		default:
+			details[dashboard.UID] = indexedDashboard{Dashboard: *fetched[index], FetchedAt: now}
+			fetchedCount++
+			updatedCount++
+		}
+	}
+
+	removedCount := 0
+	for uid := range i.details {
+		if _, found := details[uid]; !found {
+			removedCount++
+		}
+	}
+
+	i.Logger.Debug().
+		Int("dashboards", len(dashboards)).
+		Int("fetched", fetchedCount).
+		Int("updated", updatedCount).
+		Int("removed", removedCount).
+		Msg("Refreshed dashboard index")
+
+	i.dashboards = dashboards
+	i.details = details
+	i.loaded = true
+
+	return nil
+}
+
+// needsFetching returns whether the indexed dashboard should be fetched again: if its
+// version in the dashboards list is known and differs from the indexed one, or if the version
+// is unknown, as Grafana does not always return it, and the dashboard was not fetched for a while.
+func (i *DashboardIndex) needsFetching(
+	dashboard types.GrafanaDashboardInfo,
+	previous indexedDashboard,
+	now time.Time,
+) bool {
+	if dashboard.Version != 0 {
+		return dashboard.Version != previous.Dashboard.Dashboard.Version
+	}
+
+	return now.Sub(previous.FetchedAt) >= i.Config.DashboardRefreshInterval
+}
+
+// ensureLoaded loads the index on first use, if the background refresh
+// has not finished yet, so the commands won't get an empty index.
+func (i *DashboardIndex) ensureLoaded(ctx context.Context) error {
+	i.mutex.RLock()
+	loaded := i.loaded
+	i.mutex.RUnlock()
+
+	if loaded {
+		return nil
+	}
+
+	i.refreshMutex.Lock()
+	defer i.refreshMutex.Unlock()
+
+	i.mutex.RLock()
+	loaded = i.loaded
+	i.mutex.RUnlock()
+
+	if loaded {
+		return nil
+	}
+
+	return i.refreshUnsafe(ctx)
+}
+
+func (i *DashboardIndex) GetAllDashboards(ctx context.Context) (types.GrafanaDashboardsInfo, error) {
+	if !i.Enabled() {
+		return i.Grafana.GetAllDashboards(ctx)
+	}
+
+	if err := i.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	return i.dashboards, nil
+}
+
+// GetDashboard returns the dashboard from the index, or fetches it from Grafana
+// and stores it in the index, if it was not there.
+func (i *DashboardIndex) GetDashboard(ctx context.Context, dashboardUID string) (*types.GrafanaDashboardResponse, error) {
+	if !i.Enabled() {
+		return i.Grafana.GetDashboard(ctx, dashboardUID)
+	}
+
+	i.mutex.RLock()
+	dashboard, found := i.details[dashboardUID]
+	i.mutex.RUnlock()
+
+	if found {
+		return &dashboard.Dashboard, nil
+	}
+
+	dashboardEnriched, err := i.Grafana.GetDashboard(ctx, dashboardUID)
+	if err != nil {
+		return nil, err
+	}
+
+	i.UpdateDashboard(*dashboardEnriched)
+	return dashboardEnriched, nil
+}
+
+func (i *DashboardIndex) GetAllPanels(ctx context.Context) (types.PanelsStruct, error) {
+	if !i.Enabled() {
+		return i.Grafana.GetAllPanels(ctx)
+	}
+
+	if err := i.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	panels := make(types.PanelsStruct, 0)
+
+	for _, dashboard := range i.dashboards {
+		if dashboardEnriched, found := i.details[dashboard.UID]; found {
+			panels = append(panels, dashboardEnriched.Dashboard.GetPanels()...)
+		}
+	}
+
+	return panels, nil
+}
+
+// UpdateDashboard stores the dashboard in the index, unless the index
+// already has the same or a newer version of it.
+func (i *DashboardIndex) UpdateDashboard(dashboard types.GrafanaDashboardResponse) {
+	if !i.Enabled() {
+		return
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if previous, found := i.details[dashboard.Dashboard.UID]; found &&
+		previous.Dashboard.Dashboard.Version >= dashboard.Dashboard.Version {
+		return
+	}
+
+	i.details[dashboard.Dashboard.UID] = indexedDashboard{Dashboard: dashboard, FetchedAt: time.Now()}
+}
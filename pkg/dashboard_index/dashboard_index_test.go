@@ -0,0 +1,339 @@
+package dashboard_index
+
+import (
+	"context"
+	"errors"
+	"main/assets"
+	"main/pkg/clients"
+	configPkg "main/pkg/config"
+	loggerPkg "main/pkg/logger"
+	"main/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/guregu/null/v5"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIndex(config *configPkg.DashboardIndexConfig) *DashboardIndex {
+	logger := loggerPkg.GetNopLogger()
+	grafana := clients.InitGrafana(configPkg.GrafanaConfig{URL: "https://example.com"}, logger)
+	return NewDashboardIndex(grafana, config, logger)
+}
+
+func registerDashboard(uid string, version int, panelTitle string) {
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/"+uid,
+		httpmock.NewJsonResponderOrPanic(200, map[string]any{
+			"dashboard": map[string]any{
+				"uid":     uid,
+				"title":   uid,
+				"version": version,
+				"panels":  []map[string]any{{"id": 1, "title": panelTitle, "type": "timeseries"}},
+			},
+			"meta": map[string]any{"url": "/d/" + uid},
+		}))
+}
+
+func registerDashboards(uids ...string) {
+	registerDashboardsWithVersions(make([]int, len(uids)), uids...)
+}
+
+func registerDashboardsWithVersions(versions []int, uids ...string) {
+	dashboards := make([]map[string]any, len(uids))
+	for index, uid := range uids {
+		dashboards[index] = map[string]any{"uid": uid, "title": uid, "url": "/d/" + uid}
+		if versions[index] != 0 {
+			dashboards[index]["version"] = versions[index]
+		}
+	}
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewJsonResponderOrPanic(200, dashboards))
+}
+
+//nolint:paralleltest
+func TestDashboardIndexDisabled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	index := newTestIndex(nil)
+	require.False(t, index.Enabled())
+
+	registerDashboards("first")
+	registerDashboard("first", 1, "Panel")
+
+	index.Start(context.Background())
+
+	for range 2 {
+		panels, err := index.GetAllPanels(context.Background())
+		require.NoError(t, err)
+		require.Len(t, panels, 1)
+	}
+
+	// Each call goes to Grafana directly.
+	require.Equal(t, 4, httpmock.GetTotalCallCount())
+
+	index.UpdateDashboard(types.GrafanaDashboardResponse{
+		Dashboard: types.GrafanaSingleDashboard{UID: "first", Version: 2},
+	})
+	require.Empty(t, index.details)
+}
+
+//nolint:paralleltest
+func TestDashboardIndexLoadFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	index := newTestIndex(&configPkg.DashboardIndexConfig{Enabled: null.BoolFrom(true), RefreshInterval: time.Minute})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	dashboards, err := index.GetAllDashboards(context.Background())
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Empty(t, dashboards)
+
+	panels, err := index.GetAllPanels(context.Background())
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Empty(t, panels)
+}
+
+//nolint:paralleltest
+func TestDashboardIndexLoadsOnce(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	index := newTestIndex(&configPkg.DashboardIndexConfig{Enabled: null.BoolFrom(true), RefreshInterval: time.Minute})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/search?type=dash-db",
+		httpmock.NewJsonResponderOrPanic(200, []map[string]any{
+			{"uid": "alertmanager", "title": "Alertmanager", "folderTitle": "monitoring"},
+		}))
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/alertmanager",
+		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("grafana-dashboard-ok.json")))
+
+	for range 2 {
+		dashboards, err := index.GetAllDashboards(context.Background())
+		require.NoError(t, err)
+		require.Len(t, dashboards, 1)
+		require.Equal(t, "monitoring", dashboards[0].FolderTitle)
+
+		panels, err := index.GetAllPanels(context.Background())
+		require.NoError(t, err)
+
+		// Panels nested into collapsed rows are included.
+		panel, found := panels.FindByName("Memory usage for $instance")
+		require.True(t, found)
+		require.Equal(t, 177, panel.PanelID)
+
+		dashboard, err := index.GetDashboard(context.Background(), "alertmanager")
+		require.NoError(t, err)
+		require.Equal(t, 2, dashboard.Dashboard.Version)
+	}
+
+	require.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+//nolint:paralleltest
+func TestDashboardIndexRefresh(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	index := newTestIndex(&configPkg.DashboardIndexConfig{Enabled: null.BoolFrom(true), RefreshInterval: time.Minute})
+
+	registerDashboards("first", "second")
+	registerDashboard("first", 1, "Old panel")
+	registerDashboard("second", 1, "Second panel")
+	require.NoError(t, index.Refresh(context.Background()))
+
+	// The first dashboard was changed, the second one was deleted.
+	registerDashboards("first")
+	registerDashboard("first", 2, "New panel")
+	require.NoError(t, index.Refresh(context.Background()))
+
+	dashboards, err := index.GetAllDashboards(context.Background())
+	require.NoError(t, err)
+	require.Len(t, dashboards, 1)
+
+	panels, err := index.GetAllPanels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, panels, 1)
+	require.Equal(t, "New panel", panels[0].Name)
+	require.Equal(t, "/d/first", panels[0].DashboardURL)
+}
+
+//nolint:paralleltest
+func TestDashboardIndexRefreshOnlyNewAndStale(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	index := newTestIndex(&configPkg.DashboardIndexConfig{
+		Enabled:                  null.BoolFrom(true),
+		RefreshInterval:          time.Minute,
+		DashboardRefreshInterval: time.Hour,
+	})
+
+	registerDashboards("first")
+	registerDashboard("first", 1, "Panel")
+	registerDashboard("second", 1, "Second panel")
+	require.NoError(t, index.Refresh(context.Background()))
+
+	// Only the new dashboard is fetched, the first one was fetched recently.
+	registerDashboards("first", "second")
+	require.NoError(t, index.Refresh(context.Background()))
+
+	callsCount := httpmock.GetCallCountInfo()
+	require.Equal(t, 1, callsCount["GET https://example.com/api/dashboards/uid/first"])
+	require.Equal(t, 1, callsCount["GET https://example.com/api/dashboards/uid/second"])
+
+	// Stale dashboards are fetched again.
+	index.mutex.Lock()
+	first := index.details["first"]
+	first.FetchedAt = first.FetchedAt.Add(-2 * time.Hour)
+	index.details["first"] = first
+	index.mutex.Unlock()
+
+	require.NoError(t, index.Refresh(context.Background()))
+
+	callsCount = httpmock.GetCallCountInfo()
+	require.Equal(t, 2, callsCount["GET https://example.com/api/dashboards/uid/first"])
+	require.Equal(t, 1, callsCount["GET https://example.com/api/dashboards/uid/second"])
+
+	panels, err := index.GetAllPanels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, panels, 2)
+}
+
+//nolint:paralleltest
+func TestDashboardIndexRefreshByVersion(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	index := newTestIndex(&configPkg.DashboardIndexConfig{
+		Enabled:                  null.BoolFrom(true),
+		RefreshInterval:          time.Minute,
+		DashboardRefreshInterval: time.Hour,
+	})
+
+	registerDashboardsWithVersions([]int{1}, "first")
+	registerDashboard("first", 1, "Old panel")
+	require.NoError(t, index.Refresh(context.Background()))
+
+	// The version has not changed, so the dashboard is not fetched.
+	require.NoError(t, index.Refresh(context.Background()))
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["GET https://example.com/api/dashboards/uid/first"])
+
+	// The version has changed, so the dashboard is fetched, even though it was fetched recently.
+	// Registering a responder again resets its calls count.
+	registerDashboardsWithVersions([]int{2}, "first")
+	registerDashboard("first", 2, "New panel")
+	require.NoError(t, index.Refresh(context.Background()))
+	require.Equal(t, 1, httpmock.GetCallCountInfo()["GET https://example.com/api/dashboards/uid/first"])
+
+	panels, err := index.GetAllPanels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, panels, 1)
+	require.Equal(t, "New panel", panels[0].Name)
+}
+
+//nolint:paralleltest
+func TestDashboardIndexRefreshDashboardFailed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	index := newTestIndex(&configPkg.DashboardIndexConfig{Enabled: null.BoolFrom(true), RefreshInterval: time.Minute})
+
+	registerDashboards("first")
+	registerDashboard("first", 1, "Panel")
+	require.NoError(t, index.Refresh(context.Background()))
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/first",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+	require.NoError(t, index.Refresh(context.Background()))
+
+	panels, err := index.GetAllPanels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, panels, 1)
+	require.Equal(t, "Panel", panels[0].Name)
+}
+
+//nolint:paralleltest
+func TestDashboardIndexGetDashboardNotIndexed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	index := newTestIndex(&configPkg.DashboardIndexConfig{Enabled: null.BoolFrom(true), RefreshInterval: time.Minute})
+
+	httpmock.RegisterResponder(
+		"GET",
+		"https://example.com/api/dashboards/uid/first",
+		httpmock.NewErrorResponder(errors.New("custom error")))
+
+	dashboard, err := index.GetDashboard(context.Background(), "first")
+	require.Error(t, err)
+	require.ErrorContains(t, err, "custom error")
+	require.Nil(t, dashboard)
+
+	registerDashboard("first", 1, "Panel")
+
+	for range 2 {
+		dashboard, err = index.GetDashboard(context.Background(), "first")
+		require.NoError(t, err)
+		require.Equal(t, "first", dashboard.Dashboard.UID)
+	}
+
+	require.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestDashboardIndexUpdateDashboard(t *testing.T) {
+	t.Parallel()
+
+	index := newTestIndex(&configPkg.DashboardIndexConfig{Enabled: null.BoolFrom(true), RefreshInterval: time.Minute})
+
+	index.UpdateDashboard(types.GrafanaDashboardResponse{
+		Dashboard: types.GrafanaSingleDashboard{UID: "first", Title: "New", Version: 2},
+	})
+	index.UpdateDashboard(types.GrafanaDashboardResponse{
+		Dashboard: types.GrafanaSingleDashboard{UID: "first", Title: "Old", Version: 1},
+	})
+
+	dashboard, err := index.GetDashboard(context.Background(), "first")
+	require.NoError(t, err)
+	require.Equal(t, "New", dashboard.Dashboard.Title)
+}
+
+//nolint:paralleltest
+func TestDashboardIndexStartStops(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	index := newTestIndex(&configPkg.DashboardIndexConfig{Enabled: null.BoolFrom(true), RefreshInterval: time.Minute})
+
+	registerDashboards("first")
+	registerDashboard("first", 1, "Panel")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	index.Start(ctx)
+
+	panels, err := index.GetAllPanels(context.Background())
+	require.NoError(t, err)
+	require.Len(t, panels, 1)
+	require.Equal(t, 2, httpmock.GetTotalCallCount())
+}
@@ -5,4 +5,5 @@ import "os"
 type FS interface {
 	ReadFile(name string) ([]byte, error)
 	WriteFile(name string, data []byte, perm os.FileMode) error
+	AppendFile(name string, data []byte, perm os.FileMode) error
 }
@@ -13,3 +13,17 @@ func (fs *OsFS) ReadFile(name string) ([]byte, error) {
 func (fs *OsFS) WriteFile(name string, data []byte, perm os.FileMode) error {
 	return os.WriteFile(name, data, perm)
 }
+
+func (fs *OsFS) AppendFile(name string, data []byte, perm os.FileMode) error {
+	file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -23,3 +23,18 @@ func TestOsFsWrite(t *testing.T) {
 	require.Error(t, fs.WriteFile("/etc/etc/etc/etc/etc", []byte{}, 0o755))
 	require.NoError(t, fs.WriteFile("/tmp/file.txt", []byte{}, 0o755))
 }
+
+func TestOsFsAppend(t *testing.T) {
+	t.Parallel()
+
+	fs := &OsFS{}
+	path := t.TempDir() + "/file.txt"
+
+	require.Error(t, fs.AppendFile("/etc/etc/etc/etc/etc", []byte{}, 0o644))
+	require.NoError(t, fs.AppendFile(path, []byte("first\n"), 0o644))
+	require.NoError(t, fs.AppendFile(path, []byte("second\n"), 0o644))
+
+	file, err := fs.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(file))
+}
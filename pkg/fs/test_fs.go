@@ -16,3 +16,7 @@ func (fs *TestFS) ReadFile(name string) ([]byte, error) {
 func (fs *TestFS) WriteFile(name string, data []byte, perm os.FileMode) error {
 	return fs.WriteError
 }
+
+func (fs *TestFS) AppendFile(name string, data []byte, perm os.FileMode) error {
+	return fs.WriteError
+}
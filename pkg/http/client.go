@@ -2,67 +2,168 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
+	configPkg "main/pkg/config"
+	"main/pkg/metrics"
+	"net"
 	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
+const (
+	// DefaultRetries is how many times idempotent requests are retried
+	// on server errors, rate limiting and connection errors.
+	DefaultRetries = 3
+	// DefaultRetryBackoff is the delay before the first retry, doubled on each next one.
+	DefaultRetryBackoff = 500 * time.Millisecond
+	// MaxRetryBackoff is the max delay before a retry. If the upstream asks
+	// to wait longer via Retry-After, the request is not retried.
+	MaxRetryBackoff = 10 * time.Second
+	// MaxIdleConnsPerHost is raised from the default 2, so bursts of concurrent
+	// requests, like fetching all dashboards, can reuse the connections afterwards.
+	MaxIdleConnsPerHost = 10
+)
+
 type Auth struct {
 	Username string
 	Password string
 	Token    string
+	// TenantID is passed as X-Scope-OrgID header, used by multi-tenant
+	// Loki, Mimir and Cortex to determine which tenant data to return.
+	TenantID string
 }
 
 type Client struct {
 	Logger  zerolog.Logger
 	Querier string
+	Config  configPkg.HTTPConfig
+	// Headers are added to every request done by this client.
+	Headers      map[string]string
+	Retries      int
+	RetryBackoff time.Duration
+
+	httpClient     *http.Client
+	httpClientErr  error
+	httpClientOnce sync.Once
 }
 
-func NewClient(logger *zerolog.Logger, querier string) *Client {
+// NewClient returns a client with the querier used in logs and metrics, like "grafana:Grafana",
+// so requests to different instances of the same type can be told apart.
+func NewClient(logger *zerolog.Logger, querier string, config configPkg.HTTPConfig) *Client {
 	return &Client{
 		Logger: logger.With().
 			Str("component", "http").
 			Str("querier", querier).
 			Logger(),
-		Querier: querier,
+		Querier:      querier,
+		Config:       config,
+		Retries:      DefaultRetries,
+		RetryBackoff: DefaultRetryBackoff,
 	}
 }
 
 func (c *Client) Get(
+	ctx context.Context,
 	url string,
 	target interface{},
 	auth *Auth,
 ) error {
-	return c.doQueryAndDecode(http.MethodGet, url, nil, auth, target, true)
+	return c.doQueryAndDecode(ctx, http.MethodGet, url, nil, auth, target, true)
 }
 
 func (c *Client) GetRaw(
+	ctx context.Context,
 	url string,
 	auth *Auth,
 ) (io.ReadCloser, error) {
-	return c.doQuery(http.MethodGet, url, nil, auth)
+	return c.doQuery(ctx, http.MethodGet, url, nil, auth)
 }
 
 func (c *Client) Post(
+	ctx context.Context,
+	url string,
+	body interface{},
+	target interface{},
+	auth *Auth,
+) error {
+	return c.doQueryAndDecode(ctx, http.MethodPost, url, body, auth, target, true)
+}
+
+func (c *Client) Put(
+	ctx context.Context,
 	url string,
 	body interface{},
 	target interface{},
 	auth *Auth,
 ) error {
-	return c.doQueryAndDecode(http.MethodPost, url, body, auth, target, true)
+	return c.doQueryAndDecode(ctx, http.MethodPut, url, body, auth, target, true)
 }
 
 func (c *Client) Delete(
+	ctx context.Context,
 	url string,
 	auth *Auth,
 ) error {
-	return c.doQueryAndDecode(http.MethodDelete, url, nil, auth, nil, false)
+	return c.doQueryAndDecode(ctx, http.MethodDelete, url, nil, auth, nil, false)
+}
+
+// GetHTTPClient returns the client reused for all the requests of this querier, so the
+// connections are kept alive between them. It is created on the first request, as tests
+// replace http.DefaultTransport with a mock one after the clients are created.
+func (c *Client) GetHTTPClient() (*http.Client, error) {
+	c.httpClientOnce.Do(func() {
+		transport, err := c.newTransport()
+		if err != nil {
+			c.httpClientErr = err
+			return
+		}
+
+		c.httpClient = &http.Client{Transport: transport, Timeout: c.Config.Timeout}
+	})
+
+	return c.httpClient, c.httpClientErr
+}
+
+func (c *Client) newTransport() (http.RoundTripper, error) {
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return http.DefaultTransport, nil
+	}
+
+	transport := defaultTransport.Clone()
+	transport.MaxIdleConnsPerHost = MaxIdleConnsPerHost
+
+	if !c.Config.TLS.IsEmpty() {
+		tlsConfig, err := c.Config.TLS.GetTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if c.Config.ProxyURL != "" {
+		proxyURL, err := c.Config.GetProxyURL()
+		if err != nil {
+			return nil, err
+		}
+
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return transport, nil
 }
 
 func (c *Client) doQueryAndDecode(
+	ctx context.Context,
 	method string,
 	url string,
 	body interface{},
@@ -70,7 +171,7 @@ func (c *Client) doQueryAndDecode(
 	target interface{},
 	parseResponse bool,
 ) error {
-	res, err := c.doQuery(method, url, body, auth)
+	res, err := c.doQuery(ctx, method, url, body, auth)
 	if err != nil {
 		return err
 	}
@@ -85,24 +186,13 @@ func (c *Client) doQueryAndDecode(
 }
 
 func (c *Client) doQuery(
+	ctx context.Context,
 	method string,
 	url string,
 	body interface{},
 	auth *Auth,
 ) (io.ReadCloser, error) {
-	var transport http.RoundTripper
-
-	transportRaw, ok := http.DefaultTransport.(*http.Transport)
-	if ok {
-		transport = transportRaw.Clone()
-	} else {
-		transport = http.DefaultTransport
-	}
-
-	client := &http.Client{Transport: transport}
-
-	var req *http.Request
-	var err error
+	var bodyBytes []byte
 
 	if body != nil {
 		buffer := new(bytes.Buffer)
@@ -111,11 +201,49 @@ func (c *Client) doQuery(
 			return nil, encodeErr
 		}
 
-		req, err = http.NewRequest(method, url, buffer)
-	} else {
-		req, err = http.NewRequest(method, url, nil)
+		bodyBytes = buffer.Bytes()
 	}
 
+	for attempt := 0; ; attempt++ {
+		res, err := c.doRequest(ctx, method, url, bodyBytes, auth)
+
+		delay, retry := c.getRetryDelay(method, attempt, res, err)
+		if !retry {
+			return c.handleResponse(method, url, res, err)
+		}
+
+		if res != nil {
+			res.Body.Close()
+		}
+
+		c.Logger.Warn().
+			Str("url", StripCredentials(url)).
+			Str("method", method).
+			Int("attempt", attempt+1).
+			Dur("delay", delay).
+			Msg("Query failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *Client) doRequest(
+	ctx context.Context,
+	method string,
+	url string,
+	body []byte,
+	auth *Auth,
+) (*http.Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, err
 	}
@@ -123,33 +251,130 @@ func (c *Client) doQuery(
 	req.Header.Set("User-Agent", "grafana-interacter")
 	req.Header.Set("Content-Type", "application/json")
 
+	for key, value := range c.Headers {
+		req.Header.Set(key, value)
+	}
+
+	for key, value := range c.Config.Headers {
+		req.Header.Set(key, value)
+	}
+
 	if auth != nil {
 		if auth.Token != "" {
 			req.Header.Set("Authorization", "Bearer "+auth.Token)
-		} else {
+		} else if auth.Username != "" || auth.Password != "" {
 			req.SetBasicAuth(auth.Username, auth.Password)
 		}
+
+		if auth.TenantID != "" {
+			req.Header.Set("X-Scope-OrgID", auth.TenantID)
+		}
 	}
 
 	c.Logger.Debug().
-		Str("url", url).
+		Str("url", StripCredentials(url)).
 		Str("method", method).
 		Msg("Doing a query...")
 
-	res, err := client.Do(req)
+	httpClient, err := c.GetHTTPClient()
 	if err != nil {
-		c.Logger.Warn().Str("url", url).Err(err).Msg("Query failed")
+		return nil, err
+	}
+
+	start := time.Now()
+	res, err := httpClient.Do(req)
+	metrics.ObserveUpstreamRequest(c.Querier, time.Since(start), err != nil || res.StatusCode >= http.StatusBadRequest)
+
+	return res, err
+}
+
+func (c *Client) handleResponse(method, url string, res *http.Response, err error) (io.ReadCloser, error) {
+	if err != nil {
+		c.Logger.Warn().Str("url", StripCredentials(url)).Err(err).Msg("Query failed")
 		return nil, err
 	}
 
 	if res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+
+		upstreamErr := NewError(method, url, res)
+
 		c.Logger.Error().
-			Str("url", url).
+			Str("url", upstreamErr.URL).
 			Str("method", method).
 			Int("status", res.StatusCode).
+			Str("message", upstreamErr.Message).
 			Msg("Got error code")
-		return nil, fmt.Errorf("Could not fetch request. Status code: %d", res.StatusCode)
+		return nil, upstreamErr
 	}
 
 	return res.Body, nil
 }
+
+// getRetryDelay returns whether the request should be retried and after which delay.
+// Only GET requests are retried, as others might be not idempotent.
+func (c *Client) getRetryDelay(method string, attempt int, res *http.Response, err error) (time.Duration, bool) {
+	if method != http.MethodGet || attempt >= c.Retries {
+		return 0, false
+	}
+
+	if err != nil && !IsConnectionError(err) {
+		return 0, false
+	}
+
+	if err == nil && res.StatusCode != http.StatusTooManyRequests && res.StatusCode < http.StatusInternalServerError {
+		return 0, false
+	}
+
+	delay := min(c.RetryBackoff<<attempt, MaxRetryBackoff)
+
+	if res != nil {
+		if retryAfter, ok := ParseRetryAfter(res.Header.Get("Retry-After")); ok {
+			if retryAfter > MaxRetryBackoff {
+				return 0, false
+			}
+
+			delay = max(delay, retryAfter)
+		}
+	}
+
+	return delay, true
+}
+
+// IsConnectionError returns true if the request failed because the connection could not
+// be established or was dropped. Timeouts are not included, as retrying a request
+// to a hung upstream would only make the user wait longer, as well as TLS errors,
+// like a rejected client certificate, as they would fail the same way.
+func IsConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return !opErr.Timeout()
+	}
+
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// ParseRetryAfter parses the Retry-After header value, which is either
+// a number of seconds to wait, or a date after which to retry.
+func ParseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// MaxErrorBodySize is how much of the error response body is read to find the error message.
+const MaxErrorBodySize = 64 * 1024
+
+// MaxErrorMessageLength is the max length of the error message taken from
+// a non-JSON response body, so a whole HTML page won't end up in a reply.
+const MaxErrorMessageLength = 200
+
+// Error is returned when the upstream responds with an error status code.
+type Error struct {
+	Method string
+	// URL is the request URL without credentials, so it can be safely logged and displayed.
+	URL        string
+	StatusCode int
+	// Message is the error message from the response body, if there was any.
+	Message string
+}
+
+func NewError(method, rawURL string, res *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, MaxErrorBodySize))
+
+	return &Error{
+		Method:     method,
+		URL:        StripCredentials(rawURL),
+		StatusCode: res.StatusCode,
+		Message:    ParseErrorMessage(body),
+	}
+}
+
+// Error is formatted the same way as connection errors returned by net/http,
+// like `Post "https://example.com": 400 Bad Request: invalid matcher`.
+func (e *Error) Error() string {
+	operation := e.Method
+	if operation != "" {
+		operation = operation[:1] + strings.ToLower(operation[1:])
+	}
+
+	status := fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+
+	if e.Message == "" {
+		return fmt.Sprintf("%s %q: %s", operation, e.URL, status)
+	}
+
+	return fmt.Sprintf("%s %q: %s: %s", operation, e.URL, status, e.Message)
+}
+
+// StripCredentials removes the user and password from the URL.
+func StripCredentials(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.User == nil {
+		return rawURL
+	}
+
+	parsedURL.User = nil
+
+	return parsedURL.String()
+}
+
+// ParseErrorMessage returns the error message from the response body. Grafana and
+// Alertmanager return it as {"message":"..."}, Prometheus-compatible APIs as
+// {"errorType":"...","error":"..."}, Alertmanager silences API as a JSON string,
+// Grafana datasources queries as {"results":{"A":{"error":"..."}}}, and Loki as a plain text.
+func ParseErrorMessage(body []byte) string {
+	trimmed := strings.TrimSpace(string(body))
+	if trimmed == "" {
+		return ""
+	}
+
+	var message string
+	if err := json.Unmarshal(body, &message); err == nil {
+		return strings.TrimSpace(message)
+	}
+
+	var response struct {
+		Message   string `json:"message"`
+		Error     string `json:"error"`
+		ErrorType string `json:"errorType"`
+		Results   map[string]struct {
+			Error string `json:"error"`
+		} `json:"results"`
+	}
+
+	if err := json.Unmarshal(body, &response); err == nil {
+		switch {
+		case response.Message != "":
+			return response.Message
+		case response.Error != "" && response.ErrorType != "":
+			return response.ErrorType + ": " + response.Error
+		case response.Error != "":
+			return response.Error
+		}
+
+		refIDs := make([]string, 0, len(response.Results))
+		for refID := range response.Results {
+			refIDs = append(refIDs, refID)
+		}
+
+		slices.Sort(refIDs)
+
+		messages := make([]string, 0, len(refIDs))
+
+		for _, refID := range refIDs {
+			if result := response.Results[refID]; result.Error != "" {
+				messages = append(messages, result.Error)
+			}
+		}
+
+		return strings.Join(messages, "; ")
+	}
+
+	// Not a JSON, likely an HTML error page of a reverse proxy, which is useless in replies.
+	if strings.HasPrefix(trimmed, "<") {
+		return ""
+	}
+
+	line, _, _ := strings.Cut(trimmed, "\n")
+	if runes := []rune(line); len(runes) > MaxErrorMessageLength {
+		return string(runes[:MaxErrorMessageLength]) + "..."
+	}
+
+	return line
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "grafana_interacter"
+
+// Registry contains all the app metrics. A separate registry is used instead of
+// the default one, so only the app metrics and the Go runtime ones are exposed.
+var Registry = newRegistry()
+
+var (
+	CommandsTotal = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "commands_total",
+		Help:      "Telegram commands handled, by command name.",
+	}, []string{"command"})
+
+	CallbacksTotal = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "callbacks_total",
+		Help:      "Telegram inline button callbacks handled, by callback prefix.",
+	}, []string{"callback"})
+
+	UpstreamRequestDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Duration of requests to Grafana, Prometheus, Alertmanager, Loki and rulers, by querier.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"querier"})
+
+	UpstreamErrorsTotal = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_errors_total",
+		Help:      "Failed requests to Grafana, Prometheus, Alertmanager, Loki and rulers, by querier.",
+	}, []string{"querier"})
+
+	CacheSize = promauto.With(Registry).NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cache_size",
+		Help:      "Amount of items in the callbacks cache.",
+	})
+
+	TemplateRenderErrorsTotal = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "template_render_errors_total",
+		Help:      "Errors when rendering templates, by template name.",
+	}, []string{"template"})
+)
+
+func newRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return registry
+}
+
+func ObserveUpstreamRequest(querier string, duration time.Duration, failed bool) {
+	UpstreamRequestDuration.WithLabelValues(querier).Observe(duration.Seconds())
+
+	if failed {
+		UpstreamErrorsTotal.WithLabelValues(querier).Inc()
+	}
+}
@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveUpstreamRequest(t *testing.T) {
+	t.Parallel()
+
+	ObserveUpstreamRequest("test", time.Second, false)
+	require.InDelta(t, 0, testutil.ToFloat64(UpstreamErrorsTotal.WithLabelValues("test")), 0.001)
+
+	ObserveUpstreamRequest("test", time.Second, true)
+	require.InDelta(t, 1, testutil.ToFloat64(UpstreamErrorsTotal.WithLabelValues("test")), 0.001)
+	require.Equal(t, 1, testutil.CollectAndCount(UpstreamRequestDuration))
+}
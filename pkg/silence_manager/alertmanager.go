@@ -1,9 +1,9 @@
 package silence_manager
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/config"
-	"main/pkg/constants"
 	"main/pkg/http"
 	"main/pkg/types"
 
@@ -20,7 +20,7 @@ func InitAlertmanager(config *config.AlertmanagerConfig, logger *zerolog.Logger)
 	return &Alertmanager{
 		Config: config,
 		Logger: logger.With().Str("component", "alertmanager").Logger(),
-		Client: http.NewClient(logger, "alertmanager"),
+		Client: http.NewClient(logger, "alertmanager:"+config.GetName(), config.GetHTTPConfig()),
 	}
 }
 
@@ -29,19 +29,11 @@ func (g *Alertmanager) Enabled() bool {
 }
 
 func (g *Alertmanager) Name() string {
-	return "Alertmanager"
+	return g.Config.GetName()
 }
 
 func (g *Alertmanager) Prefixes() Prefixes {
-	return Prefixes{
-		PaginatedSilencesList: constants.AlertmanagerPaginatedSilencesList,
-		Silence:               constants.AlertmanagerSilencePrefix,
-		PrepareSilence:        constants.AlertmanagerPrepareSilencePrefix,
-		Unsilence:             constants.AlertmanagerUnsilencePrefix,
-		ListSilencesCommand:   constants.AlertmanagerListSilencesCommand,
-		SilenceCommand:        constants.AlertmanagerSilenceCommand,
-		UnsilenceCommand:      constants.AlertmanagerUnsilenceCommand,
-	}
+	return NewPrefixes(g.Name())
 }
 
 func (g *Alertmanager) GetMutesDurations() []string {
@@ -56,41 +48,41 @@ func (g *Alertmanager) GetAuth() *http.Auth {
 	return &http.Auth{Username: g.Config.User, Password: g.Config.Password}
 }
 
-func (g *Alertmanager) CreateSilence(silence types.Silence) (types.SilenceCreateResponse, error) {
+func (g *Alertmanager) CreateSilence(ctx context.Context, silence types.Silence) (types.SilenceCreateResponse, error) {
 	url := g.RelativeLink("/api/v2/silences")
 	res := types.SilenceCreateResponse{}
-	err := g.Client.Post(url, silence, &res, g.GetAuth())
+	err := g.Client.Post(ctx, url, silence, &res, g.GetAuth())
 	return res, err
 }
 
-func (g *Alertmanager) GetMatchingAlerts(matchers types.SilenceMatchers) ([]types.AlertmanagerAlert, error) {
+func (g *Alertmanager) GetMatchingAlerts(ctx context.Context, matchers types.SilenceMatchers) ([]types.AlertmanagerAlert, error) {
 	relativeUrl := fmt.Sprintf(
 		"/api/v2/alerts?%s&silenced=true&inhibited=true&active=true",
 		matchers.GetFilterQueryString(),
 	)
 	url := g.RelativeLink(relativeUrl)
 	var res []types.AlertmanagerAlert
-	err := g.Client.Get(url, &res, g.GetAuth())
+	err := g.Client.Get(ctx, url, &res, g.GetAuth())
 	return res, err
 }
 
-func (g *Alertmanager) GetSilences() (types.Silences, error) {
+func (g *Alertmanager) GetSilences(ctx context.Context) (types.Silences, error) {
 	silences := types.Silences{}
 	url := g.RelativeLink("/api/v2/silences")
-	err := g.Client.Get(url, &silences, g.GetAuth())
+	err := g.Client.Get(ctx, url, &silences, g.GetAuth())
 	return silences, err
 }
 
-func (g *Alertmanager) GetSilence(silenceID string) (types.Silence, error) {
+func (g *Alertmanager) GetSilence(ctx context.Context, silenceID string) (types.Silence, error) {
 	silence := types.Silence{}
 	url := g.RelativeLink("/api/v2/silence/" + silenceID)
-	err := g.Client.Get(url, &silence, g.GetAuth())
+	err := g.Client.Get(ctx, url, &silence, g.GetAuth())
 	return silence, err
 }
 
-func (g *Alertmanager) DeleteSilence(silenceID string) error {
+func (g *Alertmanager) DeleteSilence(ctx context.Context, silenceID string) error {
 	url := g.RelativeLink("/api/v2/silence/" + silenceID)
-	return g.Client.Delete(url, g.GetAuth())
+	return g.Client.Delete(ctx, url, g.GetAuth())
 }
 
 func (g *Alertmanager) RelativeLink(url string) string {
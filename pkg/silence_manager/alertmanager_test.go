@@ -1,6 +1,7 @@
 package silence_manager
 
 import (
+	"context"
 	"errors"
 	"main/assets"
 	configPkg "main/pkg/config"
@@ -22,6 +23,31 @@ func TestAlertmanagerBasic(t *testing.T) {
 	require.True(t, client.Enabled())
 	require.Equal(t, "Alertmanager", client.Name())
 	require.Equal(t, []string{"1h"}, client.GetMutesDurations())
+	require.Equal(t, "alertmanager_silences", client.Prefixes().ListSilencesCommand)
+}
+
+func TestAlertmanagerCustomName(t *testing.T) {
+	t.Parallel()
+
+	logger := loggerPkg.GetNopLogger()
+	config := &configPkg.AlertmanagerConfig{Name: "EU", URL: "http://localhost:9090"}
+	client := InitAlertmanager(config, logger)
+
+	require.Equal(t, "EU", client.Name())
+	require.Equal(t, "alertmanager:EU", client.Client.Querier)
+	require.Equal(t, Prefixes{
+		PaginatedSilencesList: "eu_paginated_silences_list_",
+		Silence:               "eu_silence_",
+		PrepareSilence:        "eu_prepare_silence_",
+		Unsilence:             "eu_unsilence_",
+		ListSilencesCommand:   "eu_silences",
+		SilenceCommand:        "eu_silence",
+		UnsilenceCommand:      "eu_unsilence",
+		ExtendSilence:         "eu_extend_silence_",
+		EditSilenceCommand:    "eu_edit_silence",
+		PrepareEditSilence:    "eu_prepare_edit_silence_",
+		EditSilence:           "eu_edit_silence_",
+	}, client.Prefixes())
 }
 
 //nolint:paralleltest
@@ -38,7 +64,7 @@ func TestAlertmanagerGetSilencesFailed(t *testing.T) {
 		"https://example.com/api/v2/silences",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	silences, err := client.GetSilences()
+	silences, err := client.GetSilences(context.Background())
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, silences)
@@ -58,7 +84,7 @@ func TestAlertmanagerGetSilencesOk(t *testing.T) {
 		"https://example.com/api/v2/silences",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silences-ok.json")))
 
-	silences, err := client.GetSilences()
+	silences, err := client.GetSilences(context.Background())
 	require.NoError(t, err)
 	require.NotEmpty(t, silences)
 }
@@ -77,7 +103,7 @@ func TestAlertmanagerGetSilenceFailed(t *testing.T) {
 		"https://example.com/api/v2/silence/id",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	silence, err := client.GetSilence("id")
+	silence, err := client.GetSilence(context.Background(), "id")
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, silence)
@@ -97,7 +123,7 @@ func TestAlertmanagerGetSilenceOk(t *testing.T) {
 		"https://example.com/api/v2/silence/id",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
 
-	silence, err := client.GetSilence("id")
+	silence, err := client.GetSilence(context.Background(), "id")
 	require.NoError(t, err)
 	require.NotEmpty(t, silence)
 }
@@ -116,7 +142,7 @@ func TestAlertmanagerCreateSilenceFailed(t *testing.T) {
 		"https://example.com/api/v2/silences",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	_, err := client.CreateSilence(types.Silence{})
+	_, err := client.CreateSilence(context.Background(), types.Silence{})
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 }
@@ -135,7 +161,7 @@ func TestAlertmanagerCreateSilenceOk(t *testing.T) {
 		"https://example.com/api/v2/silences",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-create-silence-ok.json")))
 
-	silence, err := client.CreateSilence(types.Silence{ID: "test"})
+	silence, err := client.CreateSilence(context.Background(), types.Silence{ID: "test"})
 	require.NoError(t, err)
 	require.NotEmpty(t, silence)
 }
@@ -154,7 +180,7 @@ func TestAlertmanagerDeleteSilenceFailed(t *testing.T) {
 		"https://example.com/api/v2/silence/id",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	err := client.DeleteSilence("id")
+	err := client.DeleteSilence(context.Background(), "id")
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 }
@@ -173,7 +199,7 @@ func TestAlertmanagerDeleteSilenceOk(t *testing.T) {
 		"https://example.com/api/v2/silence/id",
 		httpmock.NewBytesResponder(200, []byte{}))
 
-	err := client.DeleteSilence("id")
+	err := client.DeleteSilence(context.Background(), "id")
 	require.NoError(t, err)
 }
 
@@ -191,7 +217,7 @@ func TestAlertmanagerListSilenceAlertsFailed(t *testing.T) {
 		"https://example.com/api/v2/alerts?filter=key%3D%22value%22&silenced=true&inhibited=true&active=true",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	alerts, err := client.GetMatchingAlerts(types.SilenceMatchers{
+	alerts, err := client.GetMatchingAlerts(context.Background(), types.SilenceMatchers{
 		{IsEqual: true, IsRegex: false, Name: "key", Value: "value"},
 	})
 	require.Error(t, err)
@@ -213,7 +239,7 @@ func TestAlertmanagerListSilenceAlertsOk(t *testing.T) {
 		"https://example.com/api/v2/alerts?filter=key%3D%22value%22&silenced=true&inhibited=true&active=true",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-alerts.json")))
 
-	alerts, err := client.GetMatchingAlerts(types.SilenceMatchers{
+	alerts, err := client.GetMatchingAlerts(context.Background(), types.SilenceMatchers{
 		{IsEqual: true, IsRegex: false, Name: "key", Value: "value"},
 	})
 	require.NoError(t, err)
@@ -1,9 +1,9 @@
 package silence_manager
 
 import (
+	"context"
 	"fmt"
 	"main/pkg/config"
-	"main/pkg/constants"
 	"main/pkg/http"
 	"main/pkg/types"
 
@@ -20,24 +20,16 @@ func InitGrafana(config config.GrafanaConfig, logger *zerolog.Logger) *Grafana {
 	return &Grafana{
 		Config: config,
 		Logger: logger.With().Str("component", "grafana").Logger(),
-		Client: http.NewClient(logger, "grafana"),
+		Client: http.NewClient(logger, "grafana:"+config.GetName(), config.HTTPConfig),
 	}
 }
 
 func (g *Grafana) Prefixes() Prefixes {
-	return Prefixes{
-		PaginatedSilencesList: constants.GrafanaPaginatedSilencesList,
-		Silence:               constants.GrafanaSilencePrefix,
-		PrepareSilence:        constants.GrafanaPrepareSilencePrefix,
-		Unsilence:             constants.GrafanaUnsilencePrefix,
-		ListSilencesCommand:   constants.GrafanaListSilencesCommand,
-		SilenceCommand:        constants.GrafanaSilenceCommand,
-		UnsilenceCommand:      constants.GrafanaUnsilenceCommand,
-	}
+	return NewPrefixes(g.Name())
 }
 
 func (g *Grafana) Name() string {
-	return "Grafana"
+	return g.Config.GetName()
 }
 
 func (g *Grafana) Enabled() bool {
@@ -64,39 +56,39 @@ func (g *Grafana) RelativeLink(url string) string {
 	return fmt.Sprintf("%s%s", g.Config.URL, url)
 }
 
-func (g *Grafana) CreateSilence(silence types.Silence) (types.SilenceCreateResponse, error) {
+func (g *Grafana) CreateSilence(ctx context.Context, silence types.Silence) (types.SilenceCreateResponse, error) {
 	url := g.RelativeLink("/api/alertmanager/grafana/api/v2/silences")
 	res := types.SilenceCreateResponse{}
-	err := g.Client.Post(url, silence, &res, g.GetAuth())
+	err := g.Client.Post(ctx, url, silence, &res, g.GetAuth())
 	return res, err
 }
 
-func (g *Grafana) GetSilences() (types.Silences, error) {
+func (g *Grafana) GetSilences(ctx context.Context) (types.Silences, error) {
 	silences := types.Silences{}
 	url := g.RelativeLink("/api/alertmanager/grafana/api/v2/silences")
-	err := g.Client.Get(url, &silences, g.GetAuth())
+	err := g.Client.Get(ctx, url, &silences, g.GetAuth())
 	return silences, err
 }
 
-func (g *Grafana) GetSilence(silenceID string) (types.Silence, error) {
+func (g *Grafana) GetSilence(ctx context.Context, silenceID string) (types.Silence, error) {
 	silence := types.Silence{}
 	url := g.RelativeLink("/api/alertmanager/grafana/api/v2/silence/" + silenceID)
-	err := g.Client.Get(url, &silence, g.GetAuth())
+	err := g.Client.Get(ctx, url, &silence, g.GetAuth())
 	return silence, err
 }
 
-func (g *Grafana) DeleteSilence(silenceID string) error {
+func (g *Grafana) DeleteSilence(ctx context.Context, silenceID string) error {
 	url := g.RelativeLink("/api/alertmanager/grafana/api/v2/silence/" + silenceID)
-	return g.Client.Delete(url, g.GetAuth())
+	return g.Client.Delete(ctx, url, g.GetAuth())
 }
 
-func (g *Grafana) GetMatchingAlerts(matchers types.SilenceMatchers) ([]types.AlertmanagerAlert, error) {
+func (g *Grafana) GetMatchingAlerts(ctx context.Context, matchers types.SilenceMatchers) ([]types.AlertmanagerAlert, error) {
 	relativeUrl := fmt.Sprintf(
 		"/api/alertmanager/grafana/api/v2/alerts?%s&silenced=true&inhibited=true&active=true",
 		matchers.GetFilterQueryString(),
 	)
 	url := g.RelativeLink(relativeUrl)
 	var res []types.AlertmanagerAlert
-	err := g.Client.Get(url, &res, g.GetAuth())
+	err := g.Client.Get(ctx, url, &res, g.GetAuth())
 	return res, err
 }
@@ -1,6 +1,7 @@
 package silence_manager
 
 import (
+	"context"
 	"errors"
 	"main/assets"
 	configPkg "main/pkg/config"
@@ -43,7 +44,7 @@ func TestGrafanaCreateSilenceFailed(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/silences",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	_, err := client.CreateSilence(types.Silence{})
+	_, err := client.CreateSilence(context.Background(), types.Silence{})
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 }
@@ -62,7 +63,7 @@ func TestGrafanaCreateSilenceOk(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/silences",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-create-silence-ok.json")))
 
-	silence, err := client.CreateSilence(types.Silence{ID: "test"})
+	silence, err := client.CreateSilence(context.Background(), types.Silence{ID: "test"})
 	require.NoError(t, err)
 	require.NotEmpty(t, silence)
 }
@@ -81,7 +82,7 @@ func TestGrafanaGetSilencesFailed(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/silences",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	silences, err := client.GetSilences()
+	silences, err := client.GetSilences(context.Background())
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, silences)
@@ -101,7 +102,7 @@ func TestGrafanaGetSilencesOk(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/silences",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silences-ok.json")))
 
-	silences, err := client.GetSilences()
+	silences, err := client.GetSilences(context.Background())
 	require.NoError(t, err)
 	require.NotEmpty(t, silences)
 }
@@ -120,7 +121,7 @@ func TestGrafanaGetSilenceFailed(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/silence/id",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	silence, err := client.GetSilence("id")
+	silence, err := client.GetSilence(context.Background(), "id")
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, silence)
@@ -140,7 +141,7 @@ func TestGrafanaGetSilenceOk(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/silence/id",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-silence-ok.json")))
 
-	silence, err := client.GetSilence("id")
+	silence, err := client.GetSilence(context.Background(), "id")
 	require.NoError(t, err)
 	require.NotEmpty(t, silence)
 }
@@ -159,7 +160,7 @@ func TestGrafanaDeleteSilenceFailed(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/silence/id",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	err := client.DeleteSilence("id")
+	err := client.DeleteSilence(context.Background(), "id")
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 }
@@ -178,7 +179,7 @@ func TestGrafanaDeleteSilenceOk(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/silence/id",
 		httpmock.NewBytesResponder(200, []byte{}))
 
-	err := client.DeleteSilence("id")
+	err := client.DeleteSilence(context.Background(), "id")
 	require.NoError(t, err)
 }
 
@@ -196,7 +197,7 @@ func TestGrafanaListSilenceAlertsFailed(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/alerts?filter=key%3D%22value%22&silenced=true&inhibited=true&active=true",
 		httpmock.NewErrorResponder(errors.New("custom error")))
 
-	alerts, err := client.GetMatchingAlerts(types.SilenceMatchers{
+	alerts, err := client.GetMatchingAlerts(context.Background(), types.SilenceMatchers{
 		{IsEqual: true, IsRegex: false, Name: "key", Value: "value"},
 	})
 	require.Error(t, err)
@@ -218,7 +219,7 @@ func TestGrafanaListSilenceAlertsOk(t *testing.T) {
 		"https://example.com/api/alertmanager/grafana/api/v2/alerts?filter=key%3D%22value%22&silenced=true&inhibited=true&active=true",
 		httpmock.NewBytesResponder(200, assets.GetBytesOrPanic("alertmanager-alerts.json")))
 
-	alerts, err := client.GetMatchingAlerts(types.SilenceMatchers{
+	alerts, err := client.GetMatchingAlerts(context.Background(), types.SilenceMatchers{
 		{IsEqual: true, IsRegex: false, Name: "key", Value: "value"},
 	})
 	require.NoError(t, err)
@@ -1,9 +1,12 @@
 package silence_manager
 
 import (
+	"context"
 	"fmt"
+	"main/pkg/constants"
 	"main/pkg/types"
 	"main/pkg/utils/generic"
+	"main/pkg/utils/normalize"
 	"sync"
 )
 
@@ -15,14 +18,36 @@ type Prefixes struct {
 	ListSilencesCommand   string
 	SilenceCommand        string
 	UnsilenceCommand      string
+	ExtendSilence         string
+	EditSilenceCommand    string
+	PrepareEditSilence    string
+	EditSilence           string
+}
+
+func NewPrefixes(name string) Prefixes {
+	prefix := normalize.NormalizeCommand(name) + "_"
+
+	return Prefixes{
+		PaginatedSilencesList: prefix + constants.PaginatedSilencesListSuffix,
+		Silence:               prefix + constants.SilenceSuffix,
+		PrepareSilence:        prefix + constants.PrepareSilenceSuffix,
+		Unsilence:             prefix + constants.UnsilenceSuffix,
+		ListSilencesCommand:   prefix + constants.ListSilencesCommandSuffix,
+		SilenceCommand:        prefix + constants.SilenceCommandSuffix,
+		UnsilenceCommand:      prefix + constants.UnsilenceCommandSuffix,
+		ExtendSilence:         prefix + constants.ExtendSilenceSuffix,
+		EditSilenceCommand:    prefix + constants.EditSilenceCommandSuffix,
+		PrepareEditSilence:    prefix + constants.PrepareEditSilenceSuffix,
+		EditSilence:           prefix + constants.EditSilenceSuffix,
+	}
 }
 
 type SilenceManager interface {
-	GetSilences() (types.Silences, error)
-	GetSilence(silenceID string) (types.Silence, error)
-	CreateSilence(silence types.Silence) (types.SilenceCreateResponse, error)
-	GetMatchingAlerts(matchers types.SilenceMatchers) ([]types.AlertmanagerAlert, error)
-	DeleteSilence(silenceID string) error
+	GetSilences(ctx context.Context) (types.Silences, error)
+	GetSilence(ctx context.Context, silenceID string) (types.Silence, error)
+	CreateSilence(ctx context.Context, silence types.Silence) (types.SilenceCreateResponse, error)
+	GetMatchingAlerts(ctx context.Context, matchers types.SilenceMatchers) ([]types.AlertmanagerAlert, error)
+	DeleteSilence(ctx context.Context, silenceID string) error
 	Prefixes() Prefixes
 	Name() string
 	Enabled() bool
@@ -30,11 +55,12 @@ type SilenceManager interface {
 }
 
 func GetSilencesWithAlerts(
+	ctx context.Context,
 	manager SilenceManager,
 	page int,
 	perPage int,
 ) ([]types.SilenceWithAlerts, int, int, error) {
-	allSilences, err := manager.GetSilences()
+	allSilences, err := manager.GetSilences(ctx)
 	if err != nil {
 		return []types.SilenceWithAlerts{}, 0, 0, err
 	}
@@ -56,7 +82,7 @@ func GetSilencesWithAlerts(
 		go func(index int, silence types.Silence) {
 			defer wg.Done()
 
-			alerts, alertsErr := manager.GetMatchingAlerts(silence.Matchers)
+			alerts, alertsErr := manager.GetMatchingAlerts(ctx, silence.Matchers)
 			if alertsErr != nil {
 				mutex.Lock()
 				errs = append(errs, alertsErr)
@@ -1,6 +1,7 @@
 package silence_manager
 
 import (
+	"context"
 	"errors"
 	"main/pkg/types"
 	"testing"
@@ -15,7 +16,7 @@ func TestGetSilencesWithAlertsFetchSilencesError(t *testing.T) {
 	manager := NewStubSilenceManager()
 	manager.GetSilencesError = errors.New("custom error")
 
-	silences, _, _, err := GetSilencesWithAlerts(manager, 0, 100)
+	silences, _, _, err := GetSilencesWithAlerts(context.Background(), manager, 0, 100)
 	require.Error(t, err)
 	require.ErrorContains(t, err, "custom error")
 	require.Empty(t, silences)
@@ -26,7 +27,7 @@ func TestGetSilencesWithAlertsFetchSilenceError(t *testing.T) {
 
 	manager := NewStubSilenceManager()
 	manager.GetSilenceMatchingAlertsError = errors.New("custom error")
-	_, err := manager.CreateSilence(types.Silence{
+	_, err := manager.CreateSilence(context.Background(), types.Silence{
 		ID:     "silence",
 		EndsAt: time.Now().Add(time.Hour),
 		Status: types.SilenceStatus{State: "active"},
@@ -36,7 +37,7 @@ func TestGetSilencesWithAlertsFetchSilenceError(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	silences, _, _, err := GetSilencesWithAlerts(manager, 0, 100)
+	silences, _, _, err := GetSilencesWithAlerts(context.Background(), manager, 0, 100)
 	require.Error(t, err)
 	require.ErrorContains(t, err, "Error getting alerts for silence on 1 silences!")
 	require.Empty(t, silences)
@@ -46,7 +47,7 @@ func TestGetSilencesWithAlertsOk(t *testing.T) {
 	t.Parallel()
 
 	manager := NewStubSilenceManager()
-	_, err := manager.CreateSilence(types.Silence{
+	_, err := manager.CreateSilence(context.Background(), types.Silence{
 		ID:     "silence",
 		EndsAt: time.Now().Add(time.Hour),
 		Status: types.SilenceStatus{State: "active"},
@@ -56,7 +57,7 @@ func TestGetSilencesWithAlertsOk(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	silences, _, _, err := GetSilencesWithAlerts(manager, 0, 100)
+	silences, _, _, err := GetSilencesWithAlerts(context.Background(), manager, 0, 100)
 	require.NoError(t, err)
 	require.NotEmpty(t, silences)
 }
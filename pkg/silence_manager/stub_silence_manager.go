@@ -1,6 +1,7 @@
 package silence_manager
 
 import (
+	"context"
 	"errors"
 	"main/pkg/types"
 )
@@ -22,7 +23,7 @@ func NewStubSilenceManager() *StubSilenceManager {
 	}
 }
 
-func (m *StubSilenceManager) GetSilences() (types.Silences, error) {
+func (m *StubSilenceManager) GetSilences(ctx context.Context) (types.Silences, error) {
 	if m.GetSilencesError != nil {
 		return nil, m.GetSilencesError
 	}
@@ -38,16 +39,16 @@ func (m *StubSilenceManager) GetSilences() (types.Silences, error) {
 	return silences, nil
 }
 
-func (m *StubSilenceManager) GetSilence(silenceID string) (types.Silence, error) {
+func (m *StubSilenceManager) GetSilence(ctx context.Context, silenceID string) (types.Silence, error) {
 	return types.Silence{}, errors.New("Silence was not found!")
 }
 
-func (m *StubSilenceManager) CreateSilence(silence types.Silence) (types.SilenceCreateResponse, error) {
+func (m *StubSilenceManager) CreateSilence(ctx context.Context, silence types.Silence) (types.SilenceCreateResponse, error) {
 	m.Silences[silence.ID] = silence
 	return types.SilenceCreateResponse{SilenceID: silence.ID}, nil
 }
 
-func (m *StubSilenceManager) GetMatchingAlerts(matchers types.SilenceMatchers) ([]types.AlertmanagerAlert, error) {
+func (m *StubSilenceManager) GetMatchingAlerts(ctx context.Context, matchers types.SilenceMatchers) ([]types.AlertmanagerAlert, error) {
 	if m.GetSilenceMatchingAlertsError != nil {
 		return nil, m.GetSilenceMatchingAlertsError
 	}
@@ -55,7 +56,7 @@ func (m *StubSilenceManager) GetMatchingAlerts(matchers types.SilenceMatchers) (
 	return []types.AlertmanagerAlert{}, nil
 }
 
-func (m *StubSilenceManager) DeleteSilence(silenceID string) error {
+func (m *StubSilenceManager) DeleteSilence(ctx context.Context, silenceID string) error {
 	return nil
 }
 
@@ -65,6 +66,10 @@ func (m *StubSilenceManager) Prefixes() Prefixes {
 		Unsilence:             "stub_unsilence",
 		PaginatedSilencesList: "stub_paginated_silences_list",
 		PrepareSilence:        "stub_prepare_silence",
+		ExtendSilence:         "stub_extend_silence",
+		EditSilenceCommand:    "stub_edit_silence",
+		PrepareEditSilence:    "stub_prepare_edit_silence",
+		EditSilence:           "stub_edit_silence_",
 	}
 }
 
@@ -1,6 +1,7 @@
 package silence_manager
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -12,11 +13,11 @@ func TestStubSilenceManager(t *testing.T) {
 	silenceManager := NewStubSilenceManager()
 	require.NotEmpty(t, silenceManager.Prefixes())
 	require.NotEmpty(t, silenceManager.Name())
-	require.NoError(t, silenceManager.DeleteSilence("123"))
+	require.NoError(t, silenceManager.DeleteSilence(context.Background(), "123"))
 	require.True(t, silenceManager.Enabled())
 	require.Empty(t, silenceManager.GetMutesDurations())
 
-	silence, err := silenceManager.GetSilence("123")
+	silence, err := silenceManager.GetSilence(context.Background(), "123")
 	require.Error(t, err)
 	require.NotNil(t, silence)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"html/template"
 	"io/fs"
+	"main/pkg/metrics"
 	"main/pkg/types/render"
 	"main/pkg/utils"
 	"time"
@@ -48,12 +49,14 @@ func (manager *TemplateManager) GetTemplate(name string) (*template.Template, er
 func (manager *TemplateManager) Render(name string, data render.RenderStruct) (string, error) {
 	t, err := manager.GetTemplate(name)
 	if err != nil {
+		metrics.TemplateRenderErrorsTotal.WithLabelValues(name).Inc()
 		return "", err
 	}
 
 	var buffer bytes.Buffer
 	err = t.Execute(&buffer, data)
 	if err != nil {
+		metrics.TemplateRenderErrorsTotal.WithLabelValues(name).Inc()
 		return "", err
 	}
 
@@ -2,6 +2,7 @@ package templates
 
 import (
 	"main/assets"
+	"main/pkg/types"
 	"main/pkg/types/render"
 	templatesList "main/templates"
 	"testing"
@@ -43,11 +44,11 @@ func TestTemplateRenderOk(t *testing.T) {
 	require.NoError(t, err)
 
 	manager := NewTemplateManager(timezone, templatesList.Templates)
-	result, err := manager.Render("help", render.RenderStruct{Data: "v1.2.3"})
+	result, err := manager.Render("help", render.RenderStruct{Data: types.HelpStruct{Version: "v1.2.3"}})
 	require.NoError(t, err)
 	require.NotEmpty(t, result)
 
-	result2, err2 := manager.Render("help", render.RenderStruct{Data: "v1.2.3"})
+	result2, err2 := manager.Render("help", render.RenderStruct{Data: types.HelpStruct{Version: "v1.2.3"}})
 	require.NoError(t, err2)
 	require.NotEmpty(t, result2)
 }
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// AlertAck is a record of someone acknowledging a firing alert, meaning
+// they are looking into it, so others in the chat do not have to.
+type AlertAck struct {
+	AlertSourceName string
+	AlertName       string
+	Labels          map[string]string
+	AckedBy         string
+	AckedAt         time.Time
+	// TTL is how long the ack is kept if nothing reports the alert resolved,
+	// zero means it is kept until the alert is resolved.
+	TTL time.Duration
+}
+
+// IsExpired returns whether the ack TTL, if any, has passed.
+func (a AlertAck) IsExpired(now time.Time) bool {
+	return a.TTL > 0 && now.After(a.AckedAt.Add(a.TTL))
+}
+
+// AlertAcks are alerts acks, keyed by alert labels hash.
+type AlertAcks map[string]AlertAck
+
+func (a AlertAcks) Get(hash string) *AlertAck {
+	ack, found := a[hash]
+	if !found {
+		return nil
+	}
+
+	return &ack
+}
+
+func (a AlertAcks) ToSortedList() []AlertAck {
+	acks := make([]AlertAck, 0, len(a))
+	for _, ack := range a {
+		acks = append(acks, ack)
+	}
+
+	sort.Slice(acks, func(i, j int) bool {
+		return acks[i].AckedAt.Before(acks[j].AckedAt)
+	})
+
+	return acks
+}
+
+type AlertAcksListStruct struct {
+	Acks []AlertAck
+}
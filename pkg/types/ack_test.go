@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertAcksGet(t *testing.T) {
+	t.Parallel()
+
+	acks := AlertAcks{"hash": {AlertName: "HighLatency", AckedBy: "@testuser"}}
+
+	require.Nil(t, acks.Get("not-existing"))
+
+	ack := acks.Get("hash")
+	require.NotNil(t, ack)
+	require.Equal(t, "@testuser", ack.AckedBy)
+}
+
+func TestAlertAcksToSortedList(t *testing.T) {
+	t.Parallel()
+
+	acks := AlertAcks{
+		"second": {AlertName: "second", AckedAt: time.Unix(200, 0)},
+		"first":  {AlertName: "first", AckedAt: time.Unix(100, 0)},
+		"third":  {AlertName: "third", AckedAt: time.Unix(300, 0)},
+	}
+
+	list := acks.ToSortedList()
+	require.Len(t, list, 3)
+	require.Equal(t, "first", list[0].AlertName)
+	require.Equal(t, "second", list[1].AlertName)
+	require.Equal(t, "third", list[2].AlertName)
+}
+
+func TestAlertAckIsExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+
+	require.False(t, AlertAck{AckedAt: time.Unix(0, 0)}.IsExpired(now))
+	require.False(t, AlertAck{AckedAt: time.Unix(500, 0), TTL: time.Hour}.IsExpired(now))
+	require.True(t, AlertAck{AckedAt: time.Unix(0, 0), TTL: time.Minute}.IsExpired(now))
+}
@@ -5,7 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"main/pkg/utils/generic"
-	"main/pkg/utils/normalize"
+	"main/pkg/utils/search"
 	"strings"
 	"time"
 
@@ -30,6 +30,37 @@ type GrafanaAlertRule struct {
 	State  string         `json:"state"`
 	Name   string         `json:"name"`
 	Alerts []GrafanaAlert `json:"alerts"`
+	// UID and IsPaused are only returned for Grafana-managed rules.
+	UID      string `json:"uid"`
+	IsPaused bool   `json:"isPaused"`
+}
+
+type GrafanaAlertRuleWithGroup struct {
+	GroupName string
+	Rule      GrafanaAlertRule
+}
+
+// SearchNames returns the names the rule is matched by when searching,
+// which are its name, with or without its group name.
+func (r GrafanaAlertRuleWithGroup) SearchNames() []string {
+	return []string{r.Rule.Name, r.GroupName + " " + r.Rule.Name}
+}
+
+// GrafanaAlertRuleWithSource is the alert rule with the alert source it belongs to,
+// used when searching for a rule across multiple alert sources.
+type GrafanaAlertRuleWithSource struct {
+	AlertSourceName string
+	GrafanaAlertRuleWithGroup
+}
+
+type GrafanaAlertRulesWithSource []GrafanaAlertRuleWithSource
+
+// SearchByName returns the alert rules matching the name, best matches first,
+// ranking the rules from all alert sources together.
+func (r GrafanaAlertRulesWithSource) SearchByName(name string) search.Results[GrafanaAlertRuleWithSource] {
+	return search.Search(r, name, func(rule GrafanaAlertRuleWithSource) []string {
+		return rule.SearchNames()
+	})
 }
 
 func (g GrafanaAlertRule) SerializeAlertsCount() string {
@@ -66,26 +97,11 @@ type GrafanaAlert struct {
 }
 
 func (a GrafanaAlert) GetHash() string {
-	hash := md5.Sum([]byte(a.SerializeLabels()))
-	return hex.EncodeToString(hash[:])[0:8]
+	return GetLabelsHash(a.Labels)
 }
 
 func (a GrafanaAlert) SerializeLabels() string {
-	keys := make([]string, len(a.Labels))
-	index := 0
-	for key := range a.Labels {
-		keys[index] = key
-		index++
-	}
-
-	slices.Sort(keys)
-
-	labels := make([]string, len(a.Labels))
-	for keyIndex, key := range keys {
-		labels[keyIndex] = fmt.Sprintf("%s=%s", key, a.Labels[key])
-	}
-
-	return strings.Join(labels, " ")
+	return SerializeLabels(a.Labels)
 }
 
 func (a GrafanaAlert) ActiveSince() time.Duration {
@@ -94,19 +110,58 @@ func (a GrafanaAlert) ActiveSince() time.Duration {
 
 type GrafanaAlertGroups []GrafanaAlertGroup
 
+// SearchAlertRulesByName returns the alert rules matching the name, best matches first.
+// Rules are matched by their name, with or without their group name.
+func (g GrafanaAlertGroups) SearchAlertRulesByName(name string) search.Results[GrafanaAlertRuleWithGroup] {
+	rules := make([]GrafanaAlertRuleWithGroup, 0)
+
+	for _, group := range g {
+		for _, rule := range group.Rules {
+			rules = append(rules, GrafanaAlertRuleWithGroup{GroupName: group.Name, Rule: rule})
+		}
+	}
+
+	return search.Search(rules, name, GrafanaAlertRuleWithGroup.SearchNames)
+}
+
 func (g GrafanaAlertGroups) FindAlertRuleByName(name string) (*GrafanaAlertRule, bool) {
-	normalizedName := normalize.NormalizeString(name)
+	rule, found := g.SearchAlertRulesByName(name).Best()
+	if !found {
+		return nil, false
+	}
+
+	return &rule.Rule, true
+}
+
+// FindAlertRule returns the rule with exactly this group and rule name.
+func (g GrafanaAlertGroups) FindAlertRule(groupName, ruleName string) (*GrafanaAlertRule, bool) {
+	for _, group := range g {
+		if group.Name != groupName {
+			continue
+		}
+
+		if rule, found := generic.Find(group.Rules, func(rule GrafanaAlertRule) bool {
+			return rule.Name == ruleName
+		}); found {
+			return rule, true
+		}
+	}
+
+	return nil, false
+}
+
+func (g GrafanaAlertGroups) GetPausedRules() []PausedAlertRule {
+	pausedRules := make([]PausedAlertRule, 0)
 
 	for _, group := range g {
 		for _, rule := range group.Rules {
-			ruleName := normalize.NormalizeString(group.Name + rule.Name)
-			if strings.Contains(ruleName, normalizedName) {
-				return &rule, true
+			if rule.IsPaused {
+				pausedRules = append(pausedRules, PausedAlertRule{GroupName: group.Name, RuleName: rule.Name})
 			}
 		}
 	}
 
-	return nil, false
+	return pausedRules
 }
 
 func (g GrafanaAlertGroups) FilterFiringOrPendingAlertGroups(leavePending bool) GrafanaAlertGroups {
@@ -178,6 +233,131 @@ func (g GrafanaAlertGroups) ToFiringAlerts() []FiringAlert {
 	return firingAlerts
 }
 
+func (g GrafanaAlertGroups) ToFiringAlertsSnapshot() FiringAlertsSnapshot {
+	snapshot := make(FiringAlertsSnapshot)
+
+	for _, firingAlert := range g.ToFiringAlerts() {
+		snapshot[firingAlert.Alert.GetHash()] = firingAlert
+	}
+
+	return snapshot
+}
+
+func SerializeLabels(labels map[string]string) string {
+	keys := make([]string, len(labels))
+	index := 0
+
+	for key := range labels {
+		keys[index] = key
+		index++
+	}
+
+	slices.Sort(keys)
+
+	serialized := make([]string, len(labels))
+
+	for keyIndex, key := range keys {
+		serialized[keyIndex] = fmt.Sprintf("%s=%s", key, labels[key])
+	}
+
+	return strings.Join(serialized, " ")
+}
+
+func GetLabelsHash(labels map[string]string) string {
+	hash := md5.Sum([]byte(SerializeLabels(labels)))
+	return hex.EncodeToString(hash[:])[0:8]
+}
+
 type AlertmanagerAlert struct {
-	Labels map[string]string `json:"labels"`
+	Labels       map[string]string       `json:"labels"`
+	Annotations  map[string]string       `json:"annotations"`
+	StartsAt     time.Time               `json:"startsAt"`
+	EndsAt       time.Time               `json:"endsAt"`
+	Fingerprint  string                  `json:"fingerprint"`
+	GeneratorURL string                  `json:"generatorURL"`
+	Receivers    []AlertmanagerReceiver  `json:"receivers"`
+	Status       AlertmanagerAlertStatus `json:"status"`
+}
+
+type AlertmanagerReceiver struct {
+	Name string `json:"name"`
+}
+
+type AlertmanagerAlertStatus struct {
+	State       string   `json:"state"`
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+func (a AlertmanagerAlert) SerializeLabels() string {
+	return SerializeLabels(a.Labels)
+}
+
+type AlertmanagerAlerts []AlertmanagerAlert
+
+// GetState maps the Alertmanager alert state to the alerting rules one: suppressed
+// (silenced or inhibited) alerts are kept as "suppressed", so they are neither listed
+// as firing nor notified about, and not yet processed alerts are considered pending.
+func (a AlertmanagerAlert) GetState() string {
+	switch a.Status.State {
+	case "suppressed":
+		return "suppressed"
+	case "unprocessed":
+		return "pending"
+	default:
+		return "firing"
+	}
+}
+
+// ToAlertGroups converts Alertmanager alerts into the same shape as the alerting
+// rules returned by Grafana and Prometheus. Alertmanager knows nothing about
+// rules and their groups, so alerts are grouped by their alertname, with one
+// group and one rule per each alertname. Resolved alerts are not returned
+// by Alertmanager at all, and the rule state is the most severe of its alerts states.
+func (a AlertmanagerAlerts) ToAlertGroups() GrafanaAlertGroups {
+	alertsByName := make(map[string][]GrafanaAlert)
+	names := make([]string, 0)
+
+	for _, alert := range a {
+		name := alert.Labels["alertname"]
+
+		if _, found := alertsByName[name]; !found {
+			names = append(names, name)
+		}
+
+		alertsByName[name] = append(alertsByName[name], GrafanaAlert{
+			Labels:   alert.Labels,
+			State:    alert.GetState(),
+			ActiveAt: alert.StartsAt,
+		})
+	}
+
+	slices.Sort(names)
+
+	groups := make(GrafanaAlertGroups, len(names))
+
+	for index, name := range names {
+		alerts := alertsByName[name]
+		state := "suppressed"
+
+		for _, alertState := range []string{"firing", "pending"} {
+			if slices.ContainsFunc(alerts, func(alert GrafanaAlert) bool {
+				return alert.State == alertState
+			}) {
+				state = alertState
+				break
+			}
+		}
+
+		groups[index] = GrafanaAlertGroup{
+			Name: name,
+			Rules: []GrafanaAlertRule{{
+				State:  state,
+				Name:   name,
+				Alerts: alerts,
+			}},
+		}
+	}
+
+	return groups
 }
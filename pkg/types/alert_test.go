@@ -52,6 +52,74 @@ func TestFindAlertRuleByName(t *testing.T) {
 	require.False(t, found2)
 }
 
+func TestSearchAlertRulesByName(t *testing.T) {
+	t.Parallel()
+
+	groups := GrafanaAlertGroups{
+		{Name: "node", Rules: []GrafanaAlertRule{{Name: "DiskFull"}, {Name: "DiskFullSoon"}}},
+		{Name: "postgres", Rules: []GrafanaAlertRule{{Name: "DiskFull"}}},
+	}
+
+	results := groups.SearchAlertRulesByName("diskfull")
+	require.Len(t, results, 3)
+	require.True(t, results.IsAmbiguous())
+
+	// Rules are matched by group name too.
+	results = groups.SearchAlertRulesByName("postgres diskfull")
+	require.False(t, results.IsAmbiguous())
+
+	rule, found := results.Best()
+	require.True(t, found)
+	require.Equal(t, "postgres", rule.GroupName)
+	require.Equal(t, "DiskFull", rule.Rule.Name)
+}
+
+func TestGrafanaAlertRulesWithSourceSearchByName(t *testing.T) {
+	t.Parallel()
+
+	rule := func(alertSourceName, ruleName string) GrafanaAlertRuleWithSource {
+		return GrafanaAlertRuleWithSource{
+			AlertSourceName: alertSourceName,
+			GrafanaAlertRuleWithGroup: GrafanaAlertRuleWithGroup{
+				GroupName: "node",
+				Rule:      GrafanaAlertRule{Name: ruleName},
+			},
+		}
+	}
+
+	// Rules from all alert sources are ranked together, not in the alert sources order.
+	rules := GrafanaAlertRulesWithSource{rule("Grafana", "DiskFullSoon"), rule("Staging", "DiskFull")}
+
+	results := rules.SearchByName("diskfull")
+	require.Len(t, results, 2)
+	require.False(t, results.IsAmbiguous())
+
+	best, found := results.Best()
+	require.True(t, found)
+	require.Equal(t, "Staging", best.AlertSourceName)
+
+	// Rules are matched by group name too.
+	results = rules.SearchByName("node diskfull")
+	require.Len(t, results, 2)
+}
+
+func TestFindAlertRule(t *testing.T) {
+	t.Parallel()
+
+	groups := GrafanaAlertGroups{
+		{Name: "node", Rules: []GrafanaAlertRule{{Name: "DiskFull", State: "firing"}}},
+		{Name: "postgres", Rules: []GrafanaAlertRule{{Name: "DiskFull", State: "inactive"}}},
+	}
+
+	rule, found := groups.FindAlertRule("postgres", "DiskFull")
+	require.True(t, found)
+	require.Equal(t, "inactive", rule.State)
+
+	rule, found = groups.FindAlertRule("postgres", "DiskFullSoon")
+	require.False(t, found)
+	require.Nil(t, rule)
+}
+
 func TestFilterFiringOrPendingGrous(t *testing.T) {
 	t.Parallel()
 
@@ -92,3 +160,77 @@ func TestFilterFiringOrPendingGrous(t *testing.T) {
 	rule := group.Rules[0]
 	require.Len(t, rule.Alerts, 1)
 }
+
+func TestAlertmanagerAlertsToAlertGroups(t *testing.T) {
+	t.Parallel()
+
+	startsAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	alerts := AlertmanagerAlerts{
+		{Labels: map[string]string{"alertname": "second", "host": "a"}, StartsAt: startsAt},
+		{Labels: map[string]string{"alertname": "first", "host": "a"}, StartsAt: startsAt},
+		{Labels: map[string]string{"alertname": "second", "host": "b"}, StartsAt: startsAt},
+	}
+
+	groups := alerts.ToAlertGroups()
+	require.Len(t, groups, 2)
+
+	require.Equal(t, "first", groups[0].Name)
+	require.Len(t, groups[0].Rules, 1)
+	require.Equal(t, "first", groups[0].Rules[0].Name)
+	require.Len(t, groups[0].Rules[0].Alerts, 1)
+
+	require.Equal(t, "second", groups[1].Name)
+	require.Len(t, groups[1].Rules[0].Alerts, 2)
+	require.Equal(t, "firing", groups[1].Rules[0].Alerts[0].State)
+	require.Equal(t, startsAt, groups[1].Rules[0].Alerts[0].ActiveAt)
+	require.Equal(t, "b", groups[1].Rules[0].Alerts[1].Labels["host"])
+
+	require.Len(t, groups.FilterFiringOrPendingAlertGroups(false).ToFiringAlerts(), 3)
+}
+
+func TestAlertmanagerAlertsToAlertGroupsStates(t *testing.T) {
+	t.Parallel()
+
+	alerts := AlertmanagerAlerts{
+		{Labels: map[string]string{"alertname": "mixed", "host": "a"}, Status: AlertmanagerAlertStatus{State: "suppressed"}},
+		{Labels: map[string]string{"alertname": "mixed", "host": "b"}, Status: AlertmanagerAlertStatus{State: "active"}},
+		{Labels: map[string]string{"alertname": "pending"}, Status: AlertmanagerAlertStatus{State: "unprocessed"}},
+		{Labels: map[string]string{"alertname": "silenced"}, Status: AlertmanagerAlertStatus{State: "suppressed"}},
+	}
+
+	groups := alerts.ToAlertGroups()
+	require.Len(t, groups, 3)
+
+	require.Equal(t, "firing", groups[0].Rules[0].State)
+	require.Equal(t, "suppressed", groups[0].Rules[0].Alerts[0].State)
+	require.Equal(t, "firing", groups[0].Rules[0].Alerts[1].State)
+	require.Equal(t, "pending", groups[1].Rules[0].State)
+	require.Equal(t, "suppressed", groups[2].Rules[0].State)
+
+	// suppressed alerts are neither firing nor pending
+	firing := groups.FilterFiringOrPendingAlertGroups(false).ToFiringAlerts()
+	require.Len(t, firing, 1)
+	require.Equal(t, "b", firing[0].Alert.Labels["host"])
+	require.Len(t, groups.FilterFiringOrPendingAlertGroups(true).ToFiringAlerts(), 2)
+}
+
+func TestGrafanaAlertGroupsGetPausedRules(t *testing.T) {
+	t.Parallel()
+
+	groups := GrafanaAlertGroups{
+		{Name: "Disks", Rules: []GrafanaAlertRule{
+			{Name: "DiskFull"},
+			{Name: "DiskSlow", IsPaused: true},
+		}},
+		{Name: "Network", Rules: []GrafanaAlertRule{
+			{Name: "PacketLoss", IsPaused: true},
+		}},
+	}
+
+	require.Equal(t, []PausedAlertRule{
+		{GroupName: "Disks", RuleName: "DiskSlow"},
+		{GroupName: "Network", RuleName: "PacketLoss"},
+	}, groups.GetPausedRules())
+	require.Empty(t, GrafanaAlertGroups{}.GetPausedRules())
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+type GrafanaAnnotation struct {
+	ID           int64    `json:"id,omitempty"`
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	Time         int64    `json:"time"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+	Login        string   `json:"login,omitempty"`
+}
+
+func (a GrafanaAnnotation) GetTime() time.Time {
+	return time.UnixMilli(a.Time)
+}
+
+type GrafanaAnnotationCreateResponse struct {
+	ID      int64  `json:"id"`
+	Message string `json:"message"`
+}
+
+// ParseAnnotationTags parses tags passed as a comma-separated list, ignoring empty ones.
+func ParseAnnotationTags(tagsRaw string) []string {
+	tags := make([]string, 0)
+
+	for _, tag := range strings.Split(tagsRaw, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+type AnnotationWithDashboard struct {
+	Annotation GrafanaAnnotation
+	Dashboard  *GrafanaDashboardInfo
+}
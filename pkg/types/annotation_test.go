@@ -0,0 +1,23 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAnnotationTags(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, ParseAnnotationTags(""))
+	require.Empty(t, ParseAnnotationTags(" , ,"))
+	require.Equal(t, []string{"a", "b c"}, ParseAnnotationTags("a, ,b c,"))
+}
+
+func TestGrafanaAnnotationGetTime(t *testing.T) {
+	t.Parallel()
+
+	annotation := GrafanaAnnotation{Time: 1704110400000}
+	require.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), annotation.GetTime().UTC())
+}
@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+const (
+	AuditActionCreateSilence = "create_silence"
+	AuditActionDeleteSilence = "delete_silence"
+	AuditActionEditSilence   = "edit_silence"
+)
+
+type AuditEntry struct {
+	Time           time.Time       `json:"time"`
+	Action         string          `json:"action"`
+	UserID         int64           `json:"user_id"`
+	Username       string          `json:"username"`
+	ChatID         int64           `json:"chat_id"`
+	SilenceManager string          `json:"silence_manager"`
+	Matchers       SilenceMatchers `json:"matchers"`
+	Duration       string          `json:"duration,omitempty"`
+	SilenceID      string          `json:"silence_id"`
+}
+
+func (e AuditEntry) GetActionName() string {
+	switch e.Action {
+	case AuditActionCreateSilence:
+		return "🔇 Created silence"
+	case AuditActionDeleteSilence:
+		return "🔊 Deleted silence"
+	case AuditActionEditSilence:
+		return "✏️ Edited silence"
+	default:
+		return e.Action
+	}
+}
+
+type AuditLogStruct struct {
+	Entries      []AuditEntry
+	Start        int
+	End          int
+	EntriesCount int
+}
@@ -1,29 +1,33 @@
 package types
 
 import (
-	"main/pkg/utils/normalize"
-	"strings"
+	"encoding/json"
+	"main/pkg/utils/search"
 )
 
 type GrafanaDashboardInfo struct {
-	ID    int    `json:"id"`
-	UID   string `json:"uid"`
-	Title string `json:"title"`
-	URL   string `json:"url"`
+	ID          int    `json:"id"`
+	UID         string `json:"uid"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	FolderUID   string `json:"folderUid"`
+	FolderTitle string `json:"folderTitle"`
+	// Version is the dashboard version, if the search returns it, 0 otherwise.
+	Version int `json:"version"`
 }
 
 type GrafanaDashboardsInfo []GrafanaDashboardInfo
 
-func (i GrafanaDashboardsInfo) FindDashboardByName(name string) (*GrafanaDashboardInfo, bool) {
-	normalizedName := normalize.NormalizeString(name)
-
-	for _, dashboard := range i {
-		if strings.Contains(normalize.NormalizeString(dashboard.Title), normalizedName) {
-			return &dashboard, true
-		}
-	}
+// SearchDashboardsByName returns the dashboards matching the name, best matches first.
+// Dashboards are matched by their title, with or without their folder title.
+func (i GrafanaDashboardsInfo) SearchDashboardsByName(name string) search.Results[GrafanaDashboardInfo] {
+	return search.Search(i, name, func(dashboard GrafanaDashboardInfo) []string {
+		return []string{dashboard.Title, dashboard.FolderTitle + " " + dashboard.Title}
+	})
+}
 
-	return nil, false
+func (i GrafanaDashboardsInfo) FindDashboardByName(name string) (*GrafanaDashboardInfo, bool) {
+	return i.SearchDashboardsByName(name).Best()
 }
 
 type GrafanaDashboardResponse struct {
@@ -32,9 +36,161 @@ type GrafanaDashboardResponse struct {
 }
 
 type GrafanaSingleDashboard struct {
-	Title  string         `json:"title"`
-	UID    string         `json:"uid"`
-	Panels []GrafanaPanel `json:"panels"`
+	Title      string                     `json:"title"`
+	UID        string                     `json:"uid"`
+	Version    int                        `json:"version"`
+	Panels     []GrafanaPanel             `json:"panels"`
+	Templating GrafanaDashboardTemplating `json:"templating"`
+}
+
+// GetPanels returns all dashboard panels, including the ones nested into collapsed rows.
+func (r GrafanaDashboardResponse) GetPanels() []PanelStruct {
+	panels := r.Dashboard.GetAllPanels()
+	result := make([]PanelStruct, len(panels))
+
+	for index, panel := range panels {
+		result[index] = PanelStruct{
+			Name:          panel.Title,
+			DashboardName: r.Dashboard.Title,
+			DashboardID:   r.Dashboard.UID,
+			DashboardURL:  r.Meta.URL,
+			PanelID:       panel.ID,
+		}
+	}
+
+	return result
+}
+
+type GrafanaDashboardTemplating struct {
+	List []GrafanaTemplateVariable `json:"list"`
+}
+
+type GrafanaTemplateVariable struct {
+	Name       string                          `json:"name"`
+	Label      string                          `json:"label"`
+	Type       string                          `json:"type"`
+	Hide       int                             `json:"hide"`
+	IncludeAll bool                            `json:"includeAll"`
+	AllValue   string                          `json:"allValue"`
+	Query      GrafanaTemplateVariableQuery    `json:"query"`
+	Regex      string                          `json:"regex"`
+	Datasource GrafanaDatasourceRef            `json:"datasource"`
+	Current    GrafanaTemplateVariableOption   `json:"current"`
+	Options    []GrafanaTemplateVariableOption `json:"options"`
+}
+
+// IsSelectable returns whether the variable value can be chosen when rendering,
+// as only variables with a list of options that are not hidden can be chosen.
+func (v GrafanaTemplateVariable) IsSelectable() bool {
+	if v.Hide == 2 {
+		return false
+	}
+
+	return v.Type == "query" || v.Type == "custom" || v.Type == "datasource"
+}
+
+func (v GrafanaTemplateVariable) GetLabel() string {
+	if v.Label != "" {
+		return v.Label
+	}
+
+	return v.Name
+}
+
+// GetValue returns the variable value to substitute into other variables queries.
+func (v GrafanaTemplateVariable) GetValue(chosen string) string {
+	value := chosen
+	if value == "" {
+		value = string(v.Current.Value)
+	}
+
+	if value != "$__all" {
+		return value
+	}
+
+	if v.AllValue != "" {
+		return v.AllValue
+	}
+
+	return ".*"
+}
+
+type GrafanaTemplateVariableOption struct {
+	Text  GrafanaTemplateVariableValue `json:"text"`
+	Value GrafanaTemplateVariableValue `json:"value"`
+}
+
+// GrafanaTemplateVariableValue is a variable value, which is an array
+// for variables with multiple values selected. Only the first one is used then.
+type GrafanaTemplateVariableValue string
+
+func (v *GrafanaTemplateVariableValue) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = GrafanaTemplateVariableValue(value)
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	if len(values) > 0 {
+		*v = GrafanaTemplateVariableValue(values[0])
+	}
+
+	return nil
+}
+
+// GrafanaTemplateVariableQuery is a variable query, which is either a string,
+// or an object with the query inside for newer datasources, like Prometheus.
+type GrafanaTemplateVariableQuery string
+
+func (q *GrafanaTemplateVariableQuery) UnmarshalJSON(data []byte) error {
+	var query string
+	if err := json.Unmarshal(data, &query); err == nil {
+		*q = GrafanaTemplateVariableQuery(query)
+		return nil
+	}
+
+	var queryObject struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(data, &queryObject); err != nil {
+		return err
+	}
+
+	*q = GrafanaTemplateVariableQuery(queryObject.Query)
+	return nil
+}
+
+// GrafanaDatasourceRef is a reference to a datasource, which is either
+// a datasource name in older dashboards, or an object with its UID.
+type GrafanaDatasourceRef struct {
+	UID  string `json:"uid"`
+	Type string `json:"type"`
+	Name string `json:"-"`
+}
+
+func (r *GrafanaDatasourceRef) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		r.Name = name
+		return nil
+	}
+
+	type ref GrafanaDatasourceRef
+	return json.Unmarshal(data, (*ref)(r))
+}
+
+// GetReference returns a datasource UID or name, which might be a variable.
+func (r GrafanaDatasourceRef) GetReference() string {
+	if r.UID != "" {
+		return r.UID
+	}
+
+	return r.Name
 }
 
 type GrafanaDashboardMeta struct {
@@ -45,4 +201,52 @@ type GrafanaPanel struct {
 	ID    int    `json:"id"`
 	Title string `json:"title"`
 	Type  string `json:"type"`
+
+	// Only present for collapsed rows, as their panels are nested.
+	Panels []GrafanaPanel `json:"panels"`
+}
+
+type GrafanaDashboardRow struct {
+	Row    GrafanaPanel
+	Panels []GrafanaPanel
+}
+
+// GetAllPanels returns all dashboard panels except rows,
+// including the ones nested into collapsed rows.
+func (d GrafanaSingleDashboard) GetAllPanels() []GrafanaPanel {
+	panels := make([]GrafanaPanel, 0, len(d.Panels))
+
+	for _, panel := range d.Panels {
+		if panel.Type != "row" {
+			panels = append(panels, panel)
+			continue
+		}
+
+		for _, nestedPanel := range panel.Panels {
+			if nestedPanel.Type != "row" {
+				panels = append(panels, nestedPanel)
+			}
+		}
+	}
+
+	return panels
+}
+
+// GetRows returns dashboard rows with their panels. Panels of collapsed rows
+// are nested into them, and panels of expanded rows are the ones following them.
+func (d GrafanaSingleDashboard) GetRows() []GrafanaDashboardRow {
+	rows := make([]GrafanaDashboardRow, 0)
+
+	for _, panel := range d.Panels {
+		if panel.Type == "row" {
+			rows = append(rows, GrafanaDashboardRow{Row: panel, Panels: panel.Panels})
+			continue
+		}
+
+		if len(rows) > 0 {
+			rows[len(rows)-1].Panels = append(rows[len(rows)-1].Panels, panel)
+		}
+	}
+
+	return rows
 }
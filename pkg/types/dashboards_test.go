@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -21,3 +22,135 @@ func TestFindDashboardByName(t *testing.T) {
 	require.Nil(t, dashboard2)
 	require.False(t, found2)
 }
+
+func TestSearchDashboardsByName(t *testing.T) {
+	t.Parallel()
+
+	dashboards := GrafanaDashboardsInfo{
+		{UID: "node-exporter-full", Title: "Node Exporter Full", FolderTitle: "hardware"},
+		{UID: "node-exporter", Title: "Node Exporter", FolderTitle: "hardware"},
+		{UID: "node-exporter-old", Title: "Node Exporter", FolderTitle: "archive"},
+		{UID: "alertmanager", Title: "Alertmanager", FolderTitle: "monitoring"},
+	}
+
+	results := dashboards.SearchDashboardsByName("node exporter")
+	require.Len(t, results, 3)
+	require.True(t, results.IsAmbiguous())
+
+	// Dashboards are matched by folder title too.
+	results = dashboards.SearchDashboardsByName("archive node exporter")
+	require.False(t, results.IsAmbiguous())
+
+	dashboard, found := results.Best()
+	require.True(t, found)
+	require.Equal(t, "node-exporter-old", dashboard.UID)
+
+	dashboard, found = dashboards.FindDashboardByName("alertmanagr")
+	require.True(t, found)
+	require.Equal(t, "alertmanager", dashboard.UID)
+}
+
+func TestGrafanaTemplateVariableUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	var variables []GrafanaTemplateVariable
+
+	err := json.Unmarshal([]byte(`[
+		{
+			"name": "instance",
+			"type": "query",
+			"query": {"query": "label_values(instance)", "refId": "A"},
+			"datasource": {"type": "prometheus", "uid": "prometheus"},
+			"current": {"text": ["All"], "value": ["$__all"]}
+		},
+		{
+			"name": "env",
+			"label": "Environment",
+			"type": "custom",
+			"query": "prod,staging",
+			"datasource": "Prometheus",
+			"current": {"text": "prod", "value": "prod"}
+		}
+	]`), &variables)
+	require.NoError(t, err)
+	require.Len(t, variables, 2)
+
+	require.Equal(t, GrafanaTemplateVariableQuery("label_values(instance)"), variables[0].Query)
+	require.Equal(t, "prometheus", variables[0].Datasource.GetReference())
+	require.Equal(t, GrafanaTemplateVariableValue("$__all"), variables[0].Current.Value)
+	require.Equal(t, "instance", variables[0].GetLabel())
+
+	require.Equal(t, GrafanaTemplateVariableQuery("prod,staging"), variables[1].Query)
+	require.Equal(t, "Prometheus", variables[1].Datasource.GetReference())
+	require.Equal(t, GrafanaTemplateVariableValue("prod"), variables[1].Current.Value)
+	require.Equal(t, "Environment", variables[1].GetLabel())
+
+	err = json.Unmarshal([]byte(`[{"query": 1}]`), &variables)
+	require.Error(t, err)
+}
+
+func TestGrafanaTemplateVariableIsSelectable(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, GrafanaTemplateVariable{Type: "query"}.IsSelectable())
+	require.True(t, GrafanaTemplateVariable{Type: "custom"}.IsSelectable())
+	require.True(t, GrafanaTemplateVariable{Type: "datasource"}.IsSelectable())
+	require.False(t, GrafanaTemplateVariable{Type: "textbox"}.IsSelectable())
+	require.False(t, GrafanaTemplateVariable{Type: "query", Hide: 2}.IsSelectable())
+}
+
+func TestGrafanaTemplateVariableGetValue(t *testing.T) {
+	t.Parallel()
+
+	variable := GrafanaTemplateVariable{
+		Current: GrafanaTemplateVariableOption{Value: "$__all"},
+	}
+
+	require.Equal(t, "chosen", variable.GetValue("chosen"))
+	require.Equal(t, ".*", variable.GetValue(""))
+
+	variable.AllValue = "all"
+	require.Equal(t, "all", variable.GetValue(""))
+}
+
+func TestGrafanaDashboardGetAllPanels(t *testing.T) {
+	t.Parallel()
+
+	dashboard := GrafanaSingleDashboard{
+		Panels: []GrafanaPanel{
+			{ID: 1, Type: "timeseries"},
+			{ID: 2, Type: "row", Title: "Expanded"},
+			{ID: 3, Type: "stat"},
+			{ID: 4, Type: "row", Title: "Collapsed", Panels: []GrafanaPanel{{ID: 5, Type: "timeseries"}}},
+		},
+	}
+
+	require.Equal(t, []GrafanaPanel{
+		{ID: 1, Type: "timeseries"},
+		{ID: 3, Type: "stat"},
+		{ID: 5, Type: "timeseries"},
+	}, dashboard.GetAllPanels())
+}
+
+func TestGrafanaDashboardGetRows(t *testing.T) {
+	t.Parallel()
+
+	dashboard := GrafanaSingleDashboard{
+		Panels: []GrafanaPanel{
+			{ID: 1, Type: "timeseries"},
+			{ID: 2, Type: "row", Title: "Expanded"},
+			{ID: 3, Type: "timeseries"},
+			{ID: 4, Type: "stat"},
+			{ID: 5, Type: "row", Title: "Collapsed", Panels: []GrafanaPanel{{ID: 6, Type: "timeseries"}}},
+		},
+	}
+
+	rows := dashboard.GetRows()
+	require.Len(t, rows, 2)
+	require.Equal(t, "Expanded", rows[0].Row.Title)
+	require.Equal(t, []GrafanaPanel{{ID: 3, Type: "timeseries"}, {ID: 4, Type: "stat"}}, rows[0].Panels)
+	require.Equal(t, "Collapsed", rows[1].Row.Title)
+	require.Equal(t, []GrafanaPanel{{ID: 6, Type: "timeseries"}}, rows[1].Panels)
+
+	require.Empty(t, GrafanaSingleDashboard{}.GetRows())
+}
@@ -1,8 +1,9 @@
 package types
 
 type GrafanaDatasource struct {
-	ID   int    `json:"id"`
-	UID  string `json:"uid"`
-	Name string `json:"name"`
-	Type string `json:"type"`
+	ID        int    `json:"id"`
+	UID       string `json:"uid"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	IsDefault bool   `json:"isDefault"`
 }
@@ -0,0 +1,180 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type DatasourceQueryRequest struct {
+	Queries []map[string]interface{} `json:"queries"`
+	From    string                   `json:"from"`
+	To      string                   `json:"to"`
+}
+
+type DatasourceQueryResponse struct {
+	Results map[string]DatasourceQueryResult `json:"results"`
+}
+
+type DatasourceQueryResult struct {
+	Status int         `json:"status"`
+	Error  string      `json:"error"`
+	Frames []DataFrame `json:"frames"`
+}
+
+// DataFrame is the Grafana representation of a query result: a list of fields
+// (columns) described in schema, and their values, stored column by column.
+type DataFrame struct {
+	Schema DataFrameSchema `json:"schema"`
+	Data   DataFrameData   `json:"data"`
+}
+
+type DataFrameSchema struct {
+	Name   string           `json:"name"`
+	RefID  string           `json:"refId"`
+	Fields []DataFrameField `json:"fields"`
+}
+
+type DataFrameField struct {
+	Name   string               `json:"name"`
+	Type   string               `json:"type"`
+	Labels map[string]string    `json:"labels"`
+	Config DataFrameFieldConfig `json:"config"`
+}
+
+type DataFrameFieldConfig struct {
+	DisplayNameFromDS string `json:"displayNameFromDS"`
+}
+
+type DataFrameData struct {
+	Values [][]interface{} `json:"values"`
+}
+
+// DatasourceQueryMaxRows is how many rows of each frame are displayed,
+// and how many log lines are requested from Loki.
+const DatasourceQueryMaxRows = 50
+
+// buildDatasourceQuery returns a query for /api/ds/query for supported datasource types.
+// Prometheus instant queries are requested as a table, so each label has its own column.
+func buildDatasourceQuery(datasourceType, query string) (map[string]interface{}, bool) {
+	switch datasourceType {
+	case "prometheus":
+		return map[string]interface{}{"expr": query, "instant": true, "range": false, "format": "table"}, true
+	case "loki":
+		return map[string]interface{}{"expr": query, "queryType": "range", "maxLines": DatasourceQueryMaxRows}, true
+	case "postgres", "grafana-postgresql-datasource", "mysql", "mssql":
+		return map[string]interface{}{"rawSql": query, "rawQuery": true, "format": "table"}, true
+	default:
+		return nil, false
+	}
+}
+
+func NewDatasourceQueryRequest(datasource GrafanaDatasource, query, from, to string) (*DatasourceQueryRequest, error) {
+	datasourceQuery, ok := buildDatasourceQuery(datasource.Type, query)
+	if !ok {
+		return nil, fmt.Errorf("datasource type '%s' is not supported", datasource.Type)
+	}
+
+	datasourceQuery["refId"] = "A"
+	datasourceQuery["datasource"] = map[string]string{"uid": datasource.UID, "type": datasource.Type}
+
+	return &DatasourceQueryRequest{
+		Queries: []map[string]interface{}{datasourceQuery},
+		From:    from,
+		To:      to,
+	}, nil
+}
+
+func (f DataFrameField) GetName() string {
+	if f.Config.DisplayNameFromDS != "" {
+		return f.Config.DisplayNameFromDS
+	}
+
+	if len(f.Labels) == 0 {
+		return f.Name
+	}
+
+	return f.Name + "{" + SerializeLabels(f.Labels) + "}"
+}
+
+func (f DataFrame) RowsCount() int {
+	if len(f.Data.Values) == 0 {
+		return 0
+	}
+
+	return len(f.Data.Values[0])
+}
+
+// ToTable converts the frame columns into rows, formatting times in the given timezone.
+func (f DataFrame) ToTable(timezone *time.Location) ([]string, [][]string) {
+	headers := make([]string, len(f.Schema.Fields))
+	for index, field := range f.Schema.Fields {
+		headers[index] = field.GetName()
+	}
+
+	rows := make([][]string, f.RowsCount())
+
+	for rowIndex := range rows {
+		row := make([]string, len(headers))
+
+		for fieldIndex, field := range f.Schema.Fields {
+			if fieldIndex >= len(f.Data.Values) || rowIndex >= len(f.Data.Values[fieldIndex]) {
+				continue
+			}
+
+			row[fieldIndex] = formatDataFrameValue(f.Data.Values[fieldIndex][rowIndex], field.Type, timezone)
+		}
+
+		rows[rowIndex] = row
+	}
+
+	return headers, rows
+}
+
+func formatDataFrameValue(value interface{}, fieldType string, timezone *time.Location) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		if fieldType == "time" {
+			return time.UnixMilli(int64(typed)).In(timezone).Format(time.DateTime)
+		}
+
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case string:
+		// Log lines can be multiline, which would break the table.
+		return strings.Join(strings.Fields(typed), " ")
+	case bool:
+		return strconv.FormatBool(typed)
+	case map[string]interface{}:
+		// Loki labels are returned as objects, displaying them same way as alert labels.
+		labels := make(map[string]string, len(typed))
+		for key, labelValue := range typed {
+			labels[key] = fmt.Sprint(labelValue)
+		}
+
+		return SerializeLabels(labels)
+	default:
+		bytes, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Sprint(typed)
+		}
+
+		return string(bytes)
+	}
+}
+
+type DatasourceQueryFrame struct {
+	Name       string
+	RowsCount  int
+	HiddenRows int
+	Table      []string
+}
+
+type DatasourceQueryResultStruct struct {
+	Datasource GrafanaDatasource
+	Query      string
+	Frames     []DatasourceQueryFrame
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDatasourceQueryRequest(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewDatasourceQueryRequest(GrafanaDatasource{Type: "tempo"}, "{}", "now-1h", "now")
+	require.ErrorContains(t, err, "datasource type 'tempo' is not supported")
+
+	request, err := NewDatasourceQueryRequest(GrafanaDatasource{UID: "mysql", Type: "mysql"}, "select 1", "now-1h", "now")
+	require.NoError(t, err)
+	require.Equal(t, &DatasourceQueryRequest{
+		Queries: []map[string]interface{}{{
+			"refId":      "A",
+			"datasource": map[string]string{"uid": "mysql", "type": "mysql"},
+			"rawSql":     "select 1",
+			"rawQuery":   true,
+			"format":     "table",
+		}},
+		From: "now-1h",
+		To:   "now",
+	}, request)
+}
+
+func TestDataFrameFieldGetName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "Value", DataFrameField{Name: "Value"}.GetName())
+	require.Equal(t, "Value{job=node}", DataFrameField{
+		Name:   "Value",
+		Labels: map[string]string{"job": "node"},
+	}.GetName())
+	require.Equal(t, "up", DataFrameField{
+		Name:   "Value",
+		Labels: map[string]string{"job": "node"},
+		Config: DataFrameFieldConfig{DisplayNameFromDS: "up"},
+	}.GetName())
+}
+
+func TestDataFrameToTable(t *testing.T) {
+	t.Parallel()
+
+	frame := DataFrame{
+		Schema: DataFrameSchema{
+			Fields: []DataFrameField{
+				{Name: "time", Type: "time"},
+				{Name: "value", Type: "number"},
+				{Name: "enabled", Type: "boolean"},
+				{Name: "other", Type: "other"},
+				{Name: "missing", Type: "string"},
+			},
+		},
+		Data: DataFrameData{
+			Values: [][]interface{}{
+				{float64(1704110400000), nil},
+				{1.5, float64(2)},
+				{true, false},
+				{[]interface{}{"a"}, map[string]interface{}{"key": "value"}},
+			},
+		},
+	}
+
+	require.Equal(t, 2, frame.RowsCount())
+	require.Zero(t, DataFrame{}.RowsCount())
+
+	headers, rows := frame.ToTable(time.UTC)
+	require.Equal(t, []string{"time", "value", "enabled", "other", "missing"}, headers)
+	require.Equal(t, [][]string{
+		{"2024-01-01 12:00:00", "1.5", "true", "[\"a\"]", ""},
+		{"", "2", "false", "key=value", ""},
+	}, rows)
+}
@@ -1,8 +1,12 @@
 package types
 
 import (
-	"main/pkg/utils/normalize"
-	"strings"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"main/pkg/utils/search"
+	"net/url"
+	"sort"
 	"time"
 )
 
@@ -21,23 +25,21 @@ type PanelStruct struct {
 
 type PanelsStruct []PanelStruct
 
-func (s PanelsStruct) FindByName(name string) (*PanelStruct, bool) {
-	normalizedName := normalize.NormalizeString(name)
-
-	for _, panel := range s {
-		panelNameWithDashboardName := normalize.NormalizeString(panel.DashboardName + panel.Name)
-
-		if strings.Contains(panelNameWithDashboardName, normalizedName) {
-			return &panel, true
-		}
-	}
+// SearchByName returns the panels matching the name, best matches first.
+// Panels are matched by their name, with or without their dashboard name.
+func (s PanelsStruct) SearchByName(name string) search.Results[PanelStruct] {
+	return search.Search(s, name, func(panel PanelStruct) []string {
+		return []string{panel.Name, panel.DashboardName + " " + panel.Name}
+	})
+}
 
-	return nil, false
+func (s PanelsStruct) FindByName(name string) (*PanelStruct, bool) {
+	return s.SearchByName(name).Best()
 }
 
 type AlertsListForAlertSourceStruct struct {
 	AlertSourceName string
-	AlertGroups     []GrafanaAlertGroup
+	AlertGroups     GrafanaAlertGroups
 }
 
 type AlertsListStruct struct {
@@ -50,12 +52,63 @@ type FiringAlert struct {
 	Alert         GrafanaAlert
 }
 
+func (a FiringAlert) ToAlertAck(alertSourceName string) AlertAck {
+	return AlertAck{
+		AlertSourceName: alertSourceName,
+		AlertName:       a.GroupName + " -> " + a.AlertRuleName,
+		Labels:          a.Alert.Labels,
+	}
+}
+
+type FiringAlertsSnapshot map[string]FiringAlert
+
+func (s FiringAlertsSnapshot) Diff(previous FiringAlertsSnapshot) ([]FiringAlert, []FiringAlert) {
+	started := make([]FiringAlert, 0)
+	resolved := make([]FiringAlert, 0)
+
+	for hash, alert := range s {
+		if _, found := previous[hash]; !found {
+			started = append(started, alert)
+		}
+	}
+
+	for hash, alert := range previous {
+		if _, found := s[hash]; !found {
+			resolved = append(resolved, alert)
+		}
+	}
+
+	sortFiringAlerts(started)
+	sortFiringAlerts(resolved)
+
+	return started, resolved
+}
+
+func sortFiringAlerts(alerts []FiringAlert) {
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Alert.ActiveAt.Before(alerts[j].Alert.ActiveAt)
+	})
+}
+
+type AlertNotificationStruct struct {
+	AlertSourceName string
+	Alert           FiringAlert
+	Resolved        bool
+	Ack             *AlertAck
+	RenderTime      time.Time
+}
+
+func (n AlertNotificationStruct) GetAlertFiringFor() time.Duration {
+	return n.RenderTime.Sub(n.Alert.Alert.ActiveAt)
+}
+
 type FiringAlertsListStruct struct {
 	AlertSourceName string
 	Alerts          []FiringAlert
 	AlertsCount     int
 	Start           int
 	End             int
+	Acks            AlertAcks
 	RenderTime      time.Time
 }
 
@@ -63,6 +116,10 @@ func (f FiringAlertsListStruct) GetAlertFiringFor(alert FiringAlert) time.Durati
 	return f.RenderTime.Sub(alert.Alert.ActiveAt)
 }
 
+func (f FiringAlertsListStruct) GetAck(alert FiringAlert) *AlertAck {
+	return f.Acks.Get(alert.Alert.GetHash())
+}
+
 type SilencesListStruct struct {
 	Silences      []SilenceWithAlerts
 	Start         int
@@ -98,3 +155,157 @@ type SilencePrepareStruct struct {
 	Matchers    QueryMatchers
 	AlertsCount int
 }
+
+type SilenceManagerCommands struct {
+	Name                string
+	ListSilencesCommand string
+	SilenceCommand      string
+	UnsilenceCommand    string
+	EditSilenceCommand  string
+}
+
+type HelpStruct struct {
+	Version         string
+	SilenceManagers []SilenceManagerCommands
+}
+
+type HealthCheckResult struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type HealthStatus struct {
+	OK     bool                `json:"ok"`
+	Checks []HealthCheckResult `json:"checks"`
+}
+
+type SilenceReminderStruct struct {
+	Silence        Silence
+	SilenceManager string
+	RenderTime     time.Time
+}
+
+func (s SilenceReminderStruct) GetExpiresIn() time.Duration {
+	return s.Silence.EndsAt.Sub(s.RenderTime)
+}
+
+type SilenceExtendedStruct struct {
+	PreviousSilence Silence
+	Silence         Silence
+}
+
+type SilenceEditStruct struct {
+	PreviousSilence Silence
+	Silence         Silence
+	Alerts          []AlertmanagerAlert
+}
+
+func (s SilenceEditStruct) EndsAtChanged() bool {
+	return !s.PreviousSilence.EndsAt.Equal(s.Silence.EndsAt)
+}
+
+func (s SilenceEditStruct) CommentChanged() bool {
+	return s.PreviousSilence.Comment != s.Silence.Comment
+}
+
+// GetMatchersDiff returns the matchers of both silences, with the ones
+// that were removed or added during editing marked as such.
+func (s SilenceEditStruct) GetMatchersDiff() []SilenceMatcherDiff {
+	diff := make([]SilenceMatcherDiff, 0)
+
+	for _, matcher := range s.PreviousSilence.Matchers {
+		if s.Silence.Matchers.Contains(matcher) {
+			diff = append(diff, SilenceMatcherDiff{Matcher: matcher, Status: SilenceMatcherUnchanged})
+		} else {
+			diff = append(diff, SilenceMatcherDiff{Matcher: matcher, Status: SilenceMatcherRemoved})
+		}
+	}
+
+	for _, matcher := range s.Silence.Matchers {
+		if !s.PreviousSilence.Matchers.Contains(matcher) {
+			diff = append(diff, SilenceMatcherDiff{Matcher: matcher, Status: SilenceMatcherAdded})
+		}
+	}
+
+	return diff
+}
+
+const (
+	SilenceMatcherUnchanged = "unchanged"
+	SilenceMatcherAdded     = "added"
+	SilenceMatcherRemoved   = "removed"
+)
+
+type SilenceMatcherDiff struct {
+	Matcher *SilenceMatcher
+	Status  string
+}
+
+func (d SilenceMatcherDiff) GetSign() string {
+	switch d.Status {
+	case SilenceMatcherAdded:
+		return "+"
+	case SilenceMatcherRemoved:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+type SilencePrepareEditStruct struct {
+	Silence     Silence
+	Command     string
+	AlertsCount int
+}
+
+// RenderVariablesState is the state of choosing dashboard variables values
+// before rendering a panel, stored in cache between callbacks.
+type RenderVariablesState struct {
+	DashboardUID string                          `json:"dashboard_uid"`
+	PanelID      int                             `json:"panel_id"`
+	ChatID       int64                           `json:"chat_id"`
+	MessageID    int                             `json:"message_id"`
+	Variables    map[string]string               `json:"variables"`
+	Variable     string                          `json:"variable"`
+	Options      []GrafanaTemplateVariableOption `json:"options"`
+}
+
+func (s RenderVariablesState) GetHash() string {
+	hash := md5.Sum([]byte(fmt.Sprintf("%s %d %d %d", s.DashboardUID, s.PanelID, s.ChatID, s.MessageID)))
+	return hex.EncodeToString(hash[:])[0:8]
+}
+
+// GetParams returns the chosen variables values as render query params.
+func (s RenderVariablesState) GetParams() map[string]string {
+	params := map[string]string{}
+
+	for name, value := range s.Variables {
+		if value != "" {
+			params["var-"+name] = url.QueryEscape(value)
+		}
+	}
+
+	return params
+}
+
+type RenderChooseVariableStruct struct {
+	Dashboard    GrafanaSingleDashboard
+	Panel        GrafanaPanel
+	Variable     GrafanaTemplateVariable
+	Start        int
+	End          int
+	OptionsCount int
+}
+
+type ReportPanelError struct {
+	Name  string
+	Error string
+}
+
+type ReportStruct struct {
+	Name         string
+	Panels       []PanelStruct
+	FailedPanels []ReportPanelError
+	RenderTime   time.Time
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -25,3 +26,120 @@ func TestFindPanel(t *testing.T) {
 	require.Nil(t, panel3)
 	require.False(t, found3)
 }
+
+func TestSearchPanelsByName(t *testing.T) {
+	t.Parallel()
+
+	panels := PanelsStruct{
+		{DashboardName: "Node Exporter", Name: "CPU usage per core", PanelID: 1},
+		{DashboardName: "Node Exporter", Name: "CPU usage", PanelID: 2},
+		{DashboardName: "Postgres", Name: "CPU usage", PanelID: 3},
+		{DashboardName: "Node Exporter", Name: "GPU", PanelID: 4},
+	}
+
+	// The exact match is ranked first, though it is not the first one in the list.
+	results := panels.SearchByName("node exporter cpu usage")
+	require.False(t, results.IsAmbiguous())
+
+	panel, found := results.Best()
+	require.True(t, found)
+	require.Equal(t, 2, panel.PanelID)
+
+	// Panels with the same name on different dashboards are ambiguous.
+	results = panels.SearchByName("cpu usage")
+	require.True(t, results.IsAmbiguous())
+	require.Equal(t, []int{2, 3, 1}, []int{results[0].Item.PanelID, results[1].Item.PanelID, results[2].Item.PanelID})
+	require.Len(t, results.Top(2), 2)
+}
+
+func TestFiringAlertsSnapshotDiff(t *testing.T) {
+	t.Parallel()
+
+	previous := GrafanaAlertGroups{
+		{
+			Name: "group",
+			Rules: []GrafanaAlertRule{
+				{
+					Name: "rule",
+					Alerts: []GrafanaAlert{
+						{Labels: map[string]string{"host": "first"}, State: "firing"},
+						{Labels: map[string]string{"host": "second"}, State: "firing"},
+					},
+				},
+			},
+		},
+	}.ToFiringAlertsSnapshot()
+
+	current := GrafanaAlertGroups{
+		{
+			Name: "group",
+			Rules: []GrafanaAlertRule{
+				{
+					Name: "rule",
+					Alerts: []GrafanaAlert{
+						{Labels: map[string]string{"host": "second"}, State: "firing"},
+						{Labels: map[string]string{"host": "fourth"}, State: "firing", ActiveAt: time.Unix(2, 0)},
+						{Labels: map[string]string{"host": "third"}, State: "firing", ActiveAt: time.Unix(1, 0)},
+					},
+				},
+			},
+		},
+	}.ToFiringAlertsSnapshot()
+
+	started, resolved := current.Diff(previous)
+	require.Len(t, started, 2)
+	require.Equal(t, "third", started[0].Alert.Labels["host"])
+	require.Equal(t, "fourth", started[1].Alert.Labels["host"])
+	require.Len(t, resolved, 1)
+	require.Equal(t, "first", resolved[0].Alert.Labels["host"])
+}
+
+func TestSilenceEditStruct(t *testing.T) {
+	t.Parallel()
+
+	unchanged := &SilenceMatcher{IsEqual: true, Name: "key1", Value: "value1"}
+	removed := &SilenceMatcher{IsEqual: true, Name: "key2", Value: "value2"}
+	added := &SilenceMatcher{IsEqual: false, Name: "key3", Value: "value3"}
+
+	edit := SilenceEditStruct{
+		PreviousSilence: Silence{
+			Comment:  "comment",
+			EndsAt:   time.Unix(1, 0),
+			Matchers: SilenceMatchers{unchanged, removed},
+		},
+		Silence: Silence{
+			Comment:  "comment",
+			EndsAt:   time.Unix(2, 0),
+			Matchers: SilenceMatchers{unchanged, added},
+		},
+	}
+
+	require.True(t, edit.EndsAtChanged())
+	require.False(t, edit.CommentChanged())
+
+	diff := edit.GetMatchersDiff()
+	require.Equal(t, []SilenceMatcherDiff{
+		{Matcher: unchanged, Status: SilenceMatcherUnchanged},
+		{Matcher: removed, Status: SilenceMatcherRemoved},
+		{Matcher: added, Status: SilenceMatcherAdded},
+	}, diff)
+	require.Equal(t, " ", diff[0].GetSign())
+	require.Equal(t, "-", diff[1].GetSign())
+	require.Equal(t, "+", diff[2].GetSign())
+}
+
+func TestRenderVariablesState(t *testing.T) {
+	t.Parallel()
+
+	state := RenderVariablesState{
+		DashboardUID: "dashboard",
+		PanelID:      1,
+		ChatID:       2,
+		MessageID:    3,
+		Variables:    map[string]string{"env": "prod staging", "instance": ""},
+	}
+
+	require.Len(t, state.GetHash(), 8)
+	require.NotEqual(t, state.GetHash(), RenderVariablesState{DashboardUID: "dashboard"}.GetHash())
+	require.Equal(t, map[string]string{"var-env": "prod+staging"}, state.GetParams())
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type LokiQueryResponse struct {
+	Status    string        `json:"status"`
+	ErrorType string        `json:"errorType"`
+	Error     string        `json:"error"`
+	Data      LokiQueryData `json:"data"`
+}
+
+type LokiQueryData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// LokiStream is a set of log lines with the same labels, each of them
+// serialized by Loki as ["<unix time in nanoseconds>", "<line>"].
+type LokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][]string        `json:"values"`
+}
+
+type LogLine struct {
+	Time   time.Time
+	Labels map[string]string
+	Line   string
+}
+
+// GetLines returns log lines from all streams, sorted from the oldest to the newest.
+func (d LokiQueryData) GetLines() ([]LogLine, error) {
+	if d.ResultType != "streams" {
+		return nil, fmt.Errorf("expected a log query, got %s result", d.ResultType)
+	}
+
+	streams := []LokiStream{}
+	if err := json.Unmarshal(d.Result, &streams); err != nil {
+		return nil, err
+	}
+
+	lines := make([]LogLine, 0)
+
+	for _, stream := range streams {
+		for _, value := range stream.Values {
+			if len(value) != 2 {
+				return nil, fmt.Errorf("expected log line to have 2 values, got %d", len(value))
+			}
+
+			timestamp, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			lines = append(lines, LogLine{
+				Time:   time.Unix(0, timestamp),
+				Labels: stream.Stream,
+				Line:   value[1],
+			})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Time.Before(lines[j].Time)
+	})
+
+	return lines, nil
+}
+
+// LogsQuery is a /logs query, stored in cache so it can be paginated with buttons.
+type LogsQuery struct {
+	Datasource string
+	Query      string
+	Limit      int
+	Start      time.Time
+}
+
+func (q LogsQuery) GetHash() string {
+	return GetLabelsHash(map[string]string{
+		"datasource": q.Datasource,
+		"query":      q.Query,
+		"limit":      strconv.Itoa(q.Limit),
+		"start":      strconv.FormatInt(q.Start.UnixNano(), 10),
+	})
+}
+
+type LogLineStruct struct {
+	Time string
+	Line string
+}
+
+type LogsStruct struct {
+	Query string
+	Lines []LogLineStruct
+}
+
+func (s LogsStruct) ToText() string {
+	var sb strings.Builder
+
+	for _, line := range s.Lines {
+		sb.WriteString(line.Time + " " + line.Line + "\n")
+	}
+
+	return sb.String()
+}
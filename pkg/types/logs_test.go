@@ -0,0 +1,70 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLokiQueryDataGetLines(t *testing.T) {
+	t.Parallel()
+
+	_, err := LokiQueryData{ResultType: "matrix"}.GetLines()
+	require.ErrorContains(t, err, "expected a log query, got matrix result")
+
+	_, err = LokiQueryData{ResultType: "streams", Result: json.RawMessage(`{}`)}.GetLines()
+	require.Error(t, err)
+
+	_, err = LokiQueryData{
+		ResultType: "streams",
+		Result:     json.RawMessage(`[{"stream":{},"values":[["1"]]}]`),
+	}.GetLines()
+	require.ErrorContains(t, err, "expected log line to have 2 values, got 1")
+
+	_, err = LokiQueryData{
+		ResultType: "streams",
+		Result:     json.RawMessage(`[{"stream":{},"values":[["abc","line"]]}]`),
+	}.GetLines()
+	require.Error(t, err)
+
+	lines, err := LokiQueryData{
+		ResultType: "streams",
+		Result: json.RawMessage(`[
+			{"stream":{"level":"error"},"values":[["2000","second"]]},
+			{"stream":{"level":"info"},"values":[["3000","third"],["1000","first"]]}
+		]`),
+	}.GetLines()
+	require.NoError(t, err)
+	require.Equal(t, []LogLine{
+		{Time: time.Unix(0, 1000), Labels: map[string]string{"level": "info"}, Line: "first"},
+		{Time: time.Unix(0, 2000), Labels: map[string]string{"level": "error"}, Line: "second"},
+		{Time: time.Unix(0, 3000), Labels: map[string]string{"level": "info"}, Line: "third"},
+	}, lines)
+}
+
+func TestLogsQueryGetHash(t *testing.T) {
+	t.Parallel()
+
+	query := LogsQuery{Query: "{app=\"api\"}", Limit: 50, Start: time.Unix(1704110400, 0)}
+	require.Len(t, query.GetHash(), 8)
+	require.Equal(t, query.GetHash(), query.GetHash())
+
+	otherQuery := query
+	otherQuery.Limit = 100
+	require.NotEqual(t, query.GetHash(), otherQuery.GetHash())
+}
+
+func TestLogsStructToText(t *testing.T) {
+	t.Parallel()
+
+	logs := LogsStruct{
+		Lines: []LogLineStruct{
+			{Time: "2024-01-01 12:00:00", Line: "first"},
+			{Time: "2024-01-01 12:01:00", Line: "second"},
+		},
+	}
+
+	require.Equal(t, "2024-01-01 12:00:00 first\n2024-01-01 12:01:00 second\n", logs.ToText())
+}
@@ -0,0 +1,166 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type PrometheusQueryResponse struct {
+	Status    string              `json:"status"`
+	ErrorType string              `json:"errorType"`
+	Error     string              `json:"error"`
+	Data      PrometheusQueryData `json:"data"`
+}
+
+type PrometheusQueryData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// PrometheusSample is a single value at a given time, serialized
+// by Prometheus as [<unix time>, "<value>"].
+type PrometheusSample struct {
+	Time  time.Time
+	Value string
+}
+
+func (s *PrometheusSample) UnmarshalJSON(data []byte) error {
+	var raw []interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if len(raw) != 2 {
+		return fmt.Errorf("expected sample to have 2 values, got %d", len(raw))
+	}
+
+	timestamp, ok := raw[0].(float64)
+	if !ok {
+		return errors.New("sample timestamp is not a number")
+	}
+
+	value, ok := raw[1].(string)
+	if !ok {
+		return errors.New("sample value is not a string")
+	}
+
+	seconds := int64(timestamp)
+	nanoseconds := int64((timestamp - float64(seconds)) * float64(time.Second))
+
+	s.Time = time.Unix(seconds, nanoseconds)
+	s.Value = value
+	return nil
+}
+
+func (s PrometheusSample) FloatValue() (float64, error) {
+	return strconv.ParseFloat(s.Value, 64)
+}
+
+// PrometheusSeries is either an instant vector element, having a single value,
+// or a range vector element, having multiple values.
+type PrometheusSeries struct {
+	Metric map[string]string  `json:"metric"`
+	Value  *PrometheusSample  `json:"value"`
+	Values []PrometheusSample `json:"values"`
+}
+
+func (s PrometheusSeries) GetName() string {
+	if len(s.Metric) == 0 {
+		return "{}"
+	}
+
+	labels := make(map[string]string, len(s.Metric))
+	for key, value := range s.Metric {
+		if key != "__name__" {
+			labels[key] = value
+		}
+	}
+
+	return s.Metric["__name__"] + "{" + SerializeLabels(labels) + "}"
+}
+
+// GetSeries returns the query result as a list of series. Scalar and string
+// results are returned as a single series without labels.
+func (d PrometheusQueryData) GetSeries() ([]PrometheusSeries, error) {
+	switch d.ResultType {
+	case "vector", "matrix":
+		series := []PrometheusSeries{}
+		if err := json.Unmarshal(d.Result, &series); err != nil {
+			return nil, err
+		}
+
+		return series, nil
+	case "scalar", "string":
+		sample := PrometheusSample{}
+		if err := json.Unmarshal(d.Result, &sample); err != nil {
+			return nil, err
+		}
+
+		return []PrometheusSeries{{Metric: map[string]string{}, Value: &sample}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported result type: %s", d.ResultType)
+	}
+}
+
+// PrometheusSeriesToTable converts instant query results into a table, with
+// a column per each label found in any of the series and the value column.
+// For range vectors, the latest value is displayed.
+func PrometheusSeriesToTable(series []PrometheusSeries) ([]string, [][]string) {
+	labelsMap := make(map[string]bool)
+	hasName := false
+
+	for _, s := range series {
+		for key := range s.Metric {
+			if key == "__name__" {
+				hasName = true
+				continue
+			}
+
+			labelsMap[key] = true
+		}
+	}
+
+	labels := make([]string, 0, len(labelsMap))
+	for key := range labelsMap {
+		labels = append(labels, key)
+	}
+
+	sort.Strings(labels)
+
+	if hasName {
+		labels = append([]string{"__name__"}, labels...)
+	}
+
+	headers := make([]string, 0, len(labels)+1)
+	headers = append(headers, labels...)
+	headers = append(headers, "value")
+	rows := make([][]string, len(series))
+
+	for index, s := range series {
+		row := make([]string, len(headers))
+		for labelIndex, label := range labels {
+			row[labelIndex] = s.Metric[label]
+		}
+
+		if s.Value != nil {
+			row[len(labels)] = s.Value.Value
+		} else if len(s.Values) > 0 {
+			row[len(labels)] = s.Values[len(s.Values)-1].Value
+		}
+
+		rows[index] = row
+	}
+
+	return headers, rows
+}
+
+type QueryResultStruct struct {
+	Query       string
+	ResultType  string
+	SeriesCount int
+	Table       []string
+}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusSampleUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	sample := PrometheusSample{}
+	require.NoError(t, json.Unmarshal([]byte(`[1704110400.5, "1.5"]`), &sample))
+	require.Equal(t, time.Unix(1704110400, 500000000), sample.Time)
+	require.Equal(t, "1.5", sample.Value)
+
+	value, err := sample.FloatValue()
+	require.NoError(t, err)
+	require.InDelta(t, 1.5, value, 0.001)
+
+	require.Error(t, json.Unmarshal([]byte(`{}`), &sample))
+	require.ErrorContains(t, json.Unmarshal([]byte(`[1]`), &sample), "expected sample to have 2 values")
+	require.ErrorContains(t, json.Unmarshal([]byte(`["1", "1"]`), &sample), "sample timestamp is not a number")
+	require.ErrorContains(t, json.Unmarshal([]byte(`[1, 1]`), &sample), "sample value is not a string")
+}
+
+func TestPrometheusSeriesGetName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "{}", PrometheusSeries{}.GetName())
+	require.Equal(t, "up{job=node}", PrometheusSeries{
+		Metric: map[string]string{"__name__": "up", "job": "node"},
+	}.GetName())
+}
+
+func TestPrometheusQueryDataGetSeries(t *testing.T) {
+	t.Parallel()
+
+	vector, err := PrometheusQueryData{
+		ResultType: "vector",
+		Result:     json.RawMessage(`[{"metric":{"job":"node"},"value":[1704110400,"1"]}]`),
+	}.GetSeries()
+	require.NoError(t, err)
+	require.Len(t, vector, 1)
+	require.Equal(t, "1", vector[0].Value.Value)
+
+	scalar, err := PrometheusQueryData{
+		ResultType: "scalar",
+		Result:     json.RawMessage(`[1704110400,"42"]`),
+	}.GetSeries()
+	require.NoError(t, err)
+	require.Len(t, scalar, 1)
+	require.Equal(t, "42", scalar[0].Value.Value)
+
+	_, err = PrometheusQueryData{ResultType: "vector", Result: json.RawMessage(`{}`)}.GetSeries()
+	require.Error(t, err)
+
+	_, err = PrometheusQueryData{ResultType: "scalar", Result: json.RawMessage(`{}`)}.GetSeries()
+	require.Error(t, err)
+
+	_, err = PrometheusQueryData{ResultType: "unknown"}.GetSeries()
+	require.ErrorContains(t, err, "unsupported result type: unknown")
+}
+
+func TestPrometheusSeriesToTable(t *testing.T) {
+	t.Parallel()
+
+	headers, rows := PrometheusSeriesToTable([]PrometheusSeries{
+		{
+			Metric: map[string]string{"__name__": "up", "job": "node"},
+			Value:  &PrometheusSample{Value: "1"},
+		},
+		{
+			Metric: map[string]string{"instance": "localhost", "job": "prometheus"},
+			Values: []PrometheusSample{{Value: "0"}, {Value: "2"}},
+		},
+	})
+
+	require.Equal(t, []string{"__name__", "instance", "job", "value"}, headers)
+	require.Equal(t, [][]string{
+		{"up", "", "node", "1"},
+		{"", "localhost", "prometheus", "2"},
+	}, rows)
+}
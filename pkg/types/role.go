@@ -0,0 +1,25 @@
+package types
+
+// Role is a set of actions a Telegram user or chat is allowed to do with the bot.
+// Roles are ordered, so each role is allowed to do everything the previous ones can.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleViewer
+	RoleSilencer
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleSilencer:
+		return "silencer"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,16 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "none", RoleNone.String())
+	require.Equal(t, "viewer", RoleViewer.String())
+	require.Equal(t, "silencer", RoleSilencer.String())
+	require.Equal(t, "admin", RoleAdmin.String())
+}
@@ -0,0 +1,25 @@
+package types
+
+import "strconv"
+
+type PausedAlertRule struct {
+	GroupName string
+	RuleName  string
+}
+
+// AlertRulePause is a pending request to pause or resume a Grafana-managed rule,
+// stored in cache until it is confirmed.
+type AlertRulePause struct {
+	AlertSourceName string
+	RuleUID         string
+	RuleName        string
+	Paused          bool
+}
+
+func (p AlertRulePause) GetHash() string {
+	return GetLabelsHash(map[string]string{
+		"alert_source": p.AlertSourceName,
+		"uid":          p.RuleUID,
+		"paused":       strconv.FormatBool(p.Paused),
+	})
+}
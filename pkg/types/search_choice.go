@@ -0,0 +1,72 @@
+package types
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+)
+
+// RenderPanelChoice is the list of panels matching an ambiguous /render query,
+// stored in cache until the user chooses one of them.
+type RenderPanelChoice struct {
+	ChatID    int64
+	MessageID int
+	Panels    []PanelStruct
+	Params    map[string]string
+}
+
+func (c RenderPanelChoice) GetHash() string {
+	return choiceHash(c.ChatID, c.MessageID)
+}
+
+type AlertRuleReference struct {
+	GroupName string
+	RuleName  string
+}
+
+// AlertRuleChoice is the list of alert rules matching an ambiguous /alert query,
+// stored in cache until the user chooses one of them. Only the rules names are stored,
+// so the chosen rule is fetched again to display its current state.
+type AlertRuleChoice struct {
+	ChatID    int64
+	MessageID int
+	Rules     []AlertRuleReference
+}
+
+func (c AlertRuleChoice) GetHash() string {
+	return choiceHash(c.ChatID, c.MessageID)
+}
+
+// RenderDashboardChoice is the list of dashboards matching an ambiguous /render_dashboard
+// query, stored in cache until the user chooses one of them.
+type RenderDashboardChoice struct {
+	ChatID     int64
+	MessageID  int
+	Dashboards []GrafanaDashboardInfo
+	Params     map[string]string
+}
+
+func (c RenderDashboardChoice) GetHash() string {
+	return choiceHash(c.ChatID, c.MessageID)
+}
+
+// AnnotationDashboardChoice is the annotation to create and the list of dashboards
+// matching an ambiguous dashboard name passed to /annotate, stored in cache
+// until the user chooses one of them.
+type AnnotationDashboardChoice struct {
+	ChatID     int64
+	MessageID  int
+	Annotation GrafanaAnnotation
+	Dashboards []GrafanaDashboardInfo
+}
+
+func (c AnnotationDashboardChoice) GetHash() string {
+	return choiceHash(c.ChatID, c.MessageID)
+}
+
+// choiceHash returns the cache key suffix for the choice replied to the message,
+// so each message with choice buttons has its own choice stored.
+func choiceHash(chatID int64, messageID int) string {
+	hash := md5.Sum([]byte(fmt.Sprintf("%d %d", chatID, messageID)))
+	return hex.EncodeToString(hash[:])[0:8]
+}
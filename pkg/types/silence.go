@@ -1,6 +1,8 @@
 package types
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
 	"main/pkg/constants"
 	"main/pkg/utils/generic"
@@ -102,6 +104,14 @@ func (matchers SilenceMatchers) Equals(otherMatchers SilenceMatchers) bool {
 	return true
 }
 
+func (matchers SilenceMatchers) Contains(matcher *SilenceMatcher) bool {
+	_, found := generic.Find(matchers, func(m *SilenceMatcher) bool {
+		return m.Equals(matcher)
+	})
+
+	return found
+}
+
 func (matchers SilenceMatchers) GetFilterQueryString() string {
 	filtersParts := generic.Map(matchers, func(m *SilenceMatcher) string {
 		return "filter=" + url.QueryEscape(m.SerializeQueryString())
@@ -115,3 +125,22 @@ type SilenceWithAlerts struct {
 	AlertsPresent bool
 	Alerts        []AlertmanagerAlert
 }
+
+func (s Silence) GetHash() string {
+	hash := md5.Sum([]byte(s.ID))
+	return hex.EncodeToString(hash[:])[0:8]
+}
+
+// TrackedSilence is a silence created via the bot, which is tracked to remind
+// about it in the chat it was created in before it expires.
+type TrackedSilence struct {
+	SilenceID      string    `json:"silence_id"`
+	SilenceManager string    `json:"silence_manager"`
+	ChatID         int64     `json:"chat_id"`
+	MessageID      int       `json:"message_id"`
+	EndsAt         time.Time `json:"ends_at"`
+	Reminded       bool      `json:"reminded"`
+}
+
+// TrackedSilences is a map of silence ID to tracked silence.
+type TrackedSilences map[string]TrackedSilence
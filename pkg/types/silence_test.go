@@ -82,3 +82,21 @@ func TestSilenceMatchersEquals(t *testing.T) {
 		{IsEqual: true, IsRegex: false, Name: "key2", Value: "value2"},
 	}))
 }
+
+func TestSilenceGetHash(t *testing.T) {
+	t.Parallel()
+
+	silence := Silence{ID: "4de5faa2-8c0c-4c66-bd31-25c3bf5fa231"}
+	require.Len(t, silence.GetHash(), 8)
+	require.Equal(t, silence.GetHash(), Silence{ID: silence.ID}.GetHash())
+	require.NotEqual(t, silence.GetHash(), Silence{ID: "other"}.GetHash())
+}
+
+func TestSilenceMatchersContains(t *testing.T) {
+	t.Parallel()
+
+	matchers := SilenceMatchers{{IsEqual: true, IsRegex: false, Name: "key", Value: "value"}}
+
+	require.True(t, matchers.Contains(&SilenceMatcher{IsEqual: true, IsRegex: false, Name: "key", Value: "value"}))
+	require.False(t, matchers.Contains(&SilenceMatcher{IsEqual: false, IsRegex: false, Name: "key", Value: "value"}))
+}
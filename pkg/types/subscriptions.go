@@ -0,0 +1,80 @@
+package types
+
+import (
+	"slices"
+)
+
+// Subscriptions are label matchers for alerts that chats want to be notified about,
+// keyed by chat ID. A chat is notified if any of its subscriptions match.
+type Subscriptions map[int64][]QueryMatchers
+
+// Add adds a subscription for the chat, returning false if the chat
+// is already subscribed with the same matchers.
+func (s Subscriptions) Add(chatID int64, matchers QueryMatchers) bool {
+	if _, found := s.Find(chatID, matchers.GetHash()); found {
+		return false
+	}
+
+	s[chatID] = append(s[chatID], matchers)
+	return true
+}
+
+func (s Subscriptions) Find(chatID int64, hash string) (QueryMatchers, bool) {
+	for _, matchers := range s[chatID] {
+		if matchers.GetHash() == hash {
+			return matchers, true
+		}
+	}
+
+	return nil, false
+}
+
+// Remove removes the chat subscription by its matchers hash,
+// returning false if there is no such subscription.
+func (s Subscriptions) Remove(chatID int64, hash string) bool {
+	index := slices.IndexFunc(s[chatID], func(matchers QueryMatchers) bool {
+		return matchers.GetHash() == hash
+	})
+	if index == -1 {
+		return false
+	}
+
+	s[chatID] = slices.Delete(s[chatID], index, index+1)
+	if len(s[chatID]) == 0 {
+		delete(s, chatID)
+	}
+
+	return true
+}
+
+// Clone returns a copy of the subscriptions that can be read while the original is modified.
+// Matchers themselves are shared, as they are not modified after being added.
+func (s Subscriptions) Clone() Subscriptions {
+	cloned := make(Subscriptions, len(s))
+	for chatID, subscriptions := range s {
+		cloned[chatID] = slices.Clone(subscriptions)
+	}
+
+	return cloned
+}
+
+// GetMatchingChats returns IDs of the chats having subscriptions matching the labels.
+func (s Subscriptions) GetMatchingChats(labels map[string]string) []int64 {
+	chats := make([]int64, 0)
+
+	for chatID, subscriptions := range s {
+		if slices.ContainsFunc(subscriptions, func(matchers QueryMatchers) bool {
+			return matchers.Matches(labels)
+		}) {
+			chats = append(chats, chatID)
+		}
+	}
+
+	slices.Sort(chats)
+
+	return chats
+}
+
+type SubscriptionsListStruct struct {
+	Subscriptions []QueryMatchers
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionsAddAndRemove(t *testing.T) {
+	t.Parallel()
+
+	subscriptions := Subscriptions{}
+	matchers := QueryMatcherFromKeyValueString("team=payments")
+
+	require.True(t, subscriptions.Add(1, matchers))
+	require.False(t, subscriptions.Add(1, QueryMatcherFromKeyValueString("team=payments")))
+	require.True(t, subscriptions.Add(2, matchers))
+
+	found, ok := subscriptions.Find(1, matchers.GetHash())
+	require.True(t, ok)
+	require.Equal(t, "team=payments", found.ToQueryString())
+
+	_, ok = subscriptions.Find(3, matchers.GetHash())
+	require.False(t, ok)
+
+	require.False(t, subscriptions.Remove(3, matchers.GetHash()))
+	require.True(t, subscriptions.Remove(1, matchers.GetHash()))
+	require.False(t, subscriptions.Remove(1, matchers.GetHash()))
+	require.NotContains(t, subscriptions, int64(1))
+	require.Len(t, subscriptions[2], 1)
+}
+
+func TestSubscriptionsGetMatchingChats(t *testing.T) {
+	t.Parallel()
+
+	subscriptions := Subscriptions{}
+	subscriptions.Add(3, QueryMatcherFromKeyValueString("team=payments"))
+	subscriptions.Add(1, QueryMatcherFromKeyValueString("team=infra"))
+	subscriptions.Add(1, QueryMatcherFromKeyValueString("severity=critical"))
+	subscriptions.Add(2, QueryMatcherFromKeyValueString("team=infra"))
+
+	require.Equal(t, []int64{1, 3}, subscriptions.GetMatchingChats(map[string]string{
+		"team":     "payments",
+		"severity": "critical",
+	}))
+	require.Empty(t, subscriptions.GetMatchingChats(map[string]string{"team": "other"}))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"main/pkg/constants"
+	"regexp"
 	"sort"
 	"strings"
 	"unicode"
@@ -23,12 +24,72 @@ type QueryMatcher struct {
 	Key      string
 	Operator string
 	Value    string
+
+	// regex is the anchored regex for regex matchers, set by Compile,
+	// so it is not recompiled on every match.
+	regex *regexp.Regexp
 }
 
 func (matcher *QueryMatcher) Serialize() string {
 	return fmt.Sprintf("%s %s %s", matcher.Key, matcher.Operator, matcher.Value)
 }
 
+func (matcher *QueryMatcher) IsRegex() bool {
+	return matcher.Operator == constants.SilenceMatcherRegexEqual ||
+		matcher.Operator == constants.SilenceMatcherRegexNotEqual
+}
+
+// Validate checks that the regex matchers have valid regular expressions.
+func (matcher *QueryMatcher) Validate() error {
+	if !matcher.IsRegex() {
+		return nil
+	}
+
+	if _, err := regexp.Compile(matcher.Value); err != nil {
+		return fmt.Errorf("invalid regex in matcher %s: %s", matcher.Serialize(), err)
+	}
+
+	return nil
+}
+
+// Compile validates the matcher and compiles its regex, if any, for Matches to use.
+func (matcher *QueryMatcher) Compile() error {
+	if err := matcher.Validate(); err != nil {
+		return err
+	}
+
+	if matcher.IsRegex() {
+		matcher.regex = regexp.MustCompile("^(?:" + matcher.Value + ")$")
+	}
+
+	return nil
+}
+
+// Matches returns whether the labels match the matcher. Regexes are anchored,
+// like in Prometheus and Alertmanager, and a missing label is treated as empty.
+func (matcher *QueryMatcher) Matches(labels map[string]string) bool {
+	value := labels[matcher.Key]
+
+	switch matcher.Operator {
+	case constants.SilenceMatcherEqual:
+		return value == matcher.Value
+	case constants.SilenceMatcherNotEqual:
+		return value != matcher.Value
+	case constants.SilenceMatcherRegexEqual, constants.SilenceMatcherRegexNotEqual:
+		compiled := matcher.regex
+		if compiled == nil {
+			var err error
+			if compiled, err = regexp.Compile("^(?:" + matcher.Value + ")$"); err != nil {
+				return false
+			}
+		}
+
+		return compiled.MatchString(value) == (matcher.Operator == constants.SilenceMatcherRegexEqual)
+	default:
+		return false
+	}
+}
+
 func QueryMatcherFromKeyValueString(source string) QueryMatchers {
 	lastQuote := rune(0)
 	f := func(c rune) bool {
@@ -99,6 +160,38 @@ func (q QueryMatchers) WithoutKey(key string) QueryMatchers {
 	return newQueryMatchers
 }
 
+func (q QueryMatchers) Validate() error {
+	for _, matcher := range q {
+		if err := matcher.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Compile compiles all the matchers, see QueryMatcher.Compile.
+func (q QueryMatchers) Compile() error {
+	for _, matcher := range q {
+		if err := matcher.Compile(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Matches returns whether the labels match all the matchers.
+func (q QueryMatchers) Matches(labels map[string]string) bool {
+	for _, matcher := range q {
+		if !matcher.Matches(labels) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (q QueryMatchers) GetHash() string {
 	hash := md5.Sum([]byte(q.ToQueryString()))
 	return hex.EncodeToString(hash[:])[0:8]
@@ -149,3 +242,8 @@ func MaybeRemoveQuotes(source string) string {
 
 	return source
 }
+
+type PrometheusLabelValuesResponse struct {
+	Status string   `json:"status"`
+	Data   []string `json:"data"`
+}
@@ -61,3 +61,54 @@ func TestMatcherFromQueryMatcher(t *testing.T) {
 		Value:   "value",
 	}, MatcherFromQueryMatcher(&QueryMatcher{Key: "key", Operator: "!=", Value: "value"}))
 }
+
+func TestQueryMatcherValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, QueryMatchers{{Key: "key", Operator: "=", Value: "("}}.Validate())
+	require.NoError(t, QueryMatchers{{Key: "key", Operator: "=~", Value: "a|b"}}.Validate())
+
+	err := QueryMatchers{{Key: "key", Operator: "!~", Value: "("}}.Validate()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "invalid regex in matcher key !~ (")
+}
+
+func TestQueryMatcherMatches(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"team": "payments", "severity": "critical"}
+
+	require.True(t, (&QueryMatcher{Key: "team", Operator: "=", Value: "payments"}).Matches(labels))
+	require.False(t, (&QueryMatcher{Key: "team", Operator: "=", Value: "infra"}).Matches(labels))
+	require.True(t, (&QueryMatcher{Key: "team", Operator: "!=", Value: "infra"}).Matches(labels))
+	require.False(t, (&QueryMatcher{Key: "team", Operator: "!=", Value: "payments"}).Matches(labels))
+	require.True(t, (&QueryMatcher{Key: "severity", Operator: "=~", Value: "critical|warning"}).Matches(labels))
+	require.False(t, (&QueryMatcher{Key: "severity", Operator: "=~", Value: "crit"}).Matches(labels))
+	require.True(t, (&QueryMatcher{Key: "severity", Operator: "!~", Value: "warning|info"}).Matches(labels))
+	require.False(t, (&QueryMatcher{Key: "severity", Operator: "!~", Value: "crit.*"}).Matches(labels))
+	require.False(t, (&QueryMatcher{Key: "severity", Operator: "=~", Value: "("}).Matches(labels))
+	require.False(t, (&QueryMatcher{Key: "severity", Operator: "<>", Value: "critical"}).Matches(labels))
+
+	// missing labels are treated as empty
+	require.True(t, (&QueryMatcher{Key: "missing", Operator: "=", Value: ""}).Matches(labels))
+	require.True(t, (&QueryMatcher{Key: "missing", Operator: "!=", Value: "value"}).Matches(labels))
+
+	require.True(t, QueryMatcherFromKeyValueString("team=payments severity=~critical|warning").Matches(labels))
+	require.False(t, QueryMatcherFromKeyValueString("team=payments severity=warning").Matches(labels))
+	require.True(t, QueryMatchers{}.Matches(labels))
+}
+
+func TestQueryMatcherCompile(t *testing.T) {
+	t.Parallel()
+
+	matchers := QueryMatcherFromKeyValueString("team=payments severity=~critical|warning")
+	require.NoError(t, matchers.Compile())
+	require.Nil(t, matchers[0].regex)
+	require.NotNil(t, matchers[1].regex)
+	require.True(t, matchers.Matches(map[string]string{"team": "payments", "severity": "warning"}))
+	require.False(t, matchers.Matches(map[string]string{"team": "payments", "severity": "critical1"}))
+
+	err := QueryMatchers{{Key: "key", Operator: "=~", Value: "("}}.Compile()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "invalid regex in matcher key =~ (")
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"main/pkg/utils/generic"
+	"time"
+)
+
+// AlertmanagerWebhook is a payload sent by Alertmanager webhook receiver,
+// Grafana webhook contact point uses the same format with a few extra fields.
+type AlertmanagerWebhook struct {
+	Version           string                     `json:"version"`
+	GroupKey          string                     `json:"groupKey"`
+	TruncatedAlerts   int                        `json:"truncatedAlerts"`
+	Status            string                     `json:"status"`
+	Receiver          string                     `json:"receiver"`
+	GroupLabels       map[string]string          `json:"groupLabels"`
+	CommonLabels      map[string]string          `json:"commonLabels"`
+	CommonAnnotations map[string]string          `json:"commonAnnotations"`
+	ExternalURL       string                     `json:"externalURL"`
+	Alerts            []AlertmanagerWebhookAlert `json:"alerts"`
+}
+
+func (w AlertmanagerWebhook) FiringAlerts() []AlertmanagerWebhookAlert {
+	return generic.Filter(w.Alerts, func(a AlertmanagerWebhookAlert) bool {
+		return a.Status == "firing"
+	})
+}
+
+type AlertmanagerWebhookAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+func (a AlertmanagerWebhookAlert) GetHash() string {
+	return GetLabelsHash(a.Labels)
+}
+
+func (a AlertmanagerWebhookAlert) SerializeLabels() string {
+	return SerializeLabels(a.Labels)
+}
+
+type WebhookNotificationStruct struct {
+	Webhook    AlertmanagerWebhook
+	RenderTime time.Time
+}
+
+func (w WebhookNotificationStruct) GetAlertFiringFor(alert AlertmanagerWebhookAlert) time.Duration {
+	if alert.Status == "resolved" && !alert.EndsAt.IsZero() {
+		return alert.EndsAt.Sub(alert.StartsAt)
+	}
+
+	return w.RenderTime.Sub(alert.StartsAt)
+}
+
+// GetAlertNumber returns the number of the alert shown in the notification,
+// so the buttons for it can refer to it.
+func (w WebhookNotificationStruct) GetAlertNumber(index int) int {
+	return index + 1
+}
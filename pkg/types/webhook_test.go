@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookFiringAlerts(t *testing.T) {
+	t.Parallel()
+
+	webhook := AlertmanagerWebhook{
+		Alerts: []AlertmanagerWebhookAlert{
+			{Status: "firing", Labels: map[string]string{"key": "value"}},
+			{Status: "resolved"},
+		},
+	}
+
+	alerts := webhook.FiringAlerts()
+	require.Len(t, alerts, 1)
+	require.Equal(t, "key=value", alerts[0].SerializeLabels())
+	require.Equal(t, GrafanaAlert{Labels: map[string]string{"key": "value"}}.GetHash(), alerts[0].GetHash())
+}
+
+func TestWebhookGetAlertFiringFor(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	notification := WebhookNotificationStruct{RenderTime: now}
+
+	require.Equal(t, time.Hour, notification.GetAlertFiringFor(AlertmanagerWebhookAlert{
+		Status:   "firing",
+		StartsAt: now.Add(-time.Hour),
+	}))
+	require.Equal(t, 30*time.Minute, notification.GetAlertFiringFor(AlertmanagerWebhookAlert{
+		Status:   "resolved",
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(-30 * time.Minute),
+	}))
+}
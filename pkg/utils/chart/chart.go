@@ -0,0 +1,284 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Point is a single value of a series at a given time.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+type Options struct {
+	Width    int
+	Height   int
+	Timezone *time.Location
+}
+
+const (
+	marginLeft   = 70
+	marginRight  = 20
+	marginTop    = 15
+	marginBottom = 25
+	lineHeight   = 15
+	ticksCount   = 5
+	// Only that many series are listed in the legend, so it does not take the whole image.
+	maxLegendItems = 10
+)
+
+var (
+	backgroundColor = color.RGBA{R: 0x18, G: 0x1b, B: 0x1f, A: 0xff}
+	gridColor       = color.RGBA{R: 0x2c, G: 0x32, B: 0x35, A: 0xff}
+	textColor       = color.RGBA{R: 0xcc, G: 0xcc, B: 0xdc, A: 0xff}
+	palette         = []color.RGBA{
+		{R: 0x73, G: 0xbf, B: 0x69, A: 0xff},
+		{R: 0xf2, G: 0xcc, B: 0x0c, A: 0xff},
+		{R: 0x8a, G: 0xb8, B: 0xff, A: 0xff},
+		{R: 0xff, G: 0x78, B: 0x0a, A: 0xff},
+		{R: 0xf2, G: 0x49, B: 0x5c, A: 0xff},
+		{R: 0x57, G: 0x94, B: 0xf2, A: 0xff},
+		{R: 0xb8, G: 0x77, B: 0xd9, A: 0xff},
+		{R: 0x70, G: 0x5d, B: 0xa0, A: 0xff},
+		{R: 0x37, G: 0x87, B: 0x2d, A: 0xff},
+		{R: 0xfa, G: 0xde, B: 0x2a, A: 0xff},
+	}
+)
+
+type bounds struct {
+	MinTime  time.Time
+	MaxTime  time.Time
+	MinValue float64
+	MaxValue float64
+}
+
+// Render draws series as a line chart, with the value and time axes and a legend,
+// and returns it encoded as PNG.
+func Render(series []Series, options Options) ([]byte, error) {
+	b, ok := getBounds(series)
+	if !ok {
+		return nil, errors.New("no data points to draw")
+	}
+
+	if options.Timezone == nil {
+		options.Timezone = time.UTC
+	}
+
+	legendItems := min(len(series), maxLegendItems)
+	if len(series) > maxLegendItems {
+		legendItems++ // for "and N more"
+	}
+
+	plotBottom := options.Height - marginBottom - legendItems*lineHeight
+	if options.Width-marginRight <= marginLeft || plotBottom <= marginTop {
+		return nil, errors.New("image is too small to draw the chart")
+	}
+
+	plot := image.Rect(marginLeft, marginTop, options.Width-marginRight, plotBottom)
+
+	img := image.NewRGBA(image.Rect(0, 0, options.Width, options.Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: backgroundColor}, image.Point{}, draw.Src)
+
+	timeSpan := b.MaxTime.Sub(b.MinTime)
+	timeFormat := "15:04"
+	if timeSpan > 24*time.Hour {
+		timeFormat = "Jan 02 15:04"
+	}
+
+	for tick := 0; tick <= ticksCount; tick++ {
+		y := plot.Max.Y - tick*plot.Dy()/ticksCount
+		drawHorizontalLine(img, plot.Min.X, plot.Max.X, y, gridColor)
+
+		value := b.MinValue + float64(tick)*(b.MaxValue-b.MinValue)/ticksCount
+		label := formatValue(value)
+		drawString(img, plot.Min.X-8-len(label)*7, y+4, label, textColor)
+
+		x := plot.Min.X + tick*plot.Dx()/ticksCount
+		drawVerticalLine(img, x, plot.Min.Y, plot.Max.Y, gridColor)
+
+		t := b.MinTime.Add(time.Duration(float64(timeSpan) * float64(tick) / ticksCount))
+		timeLabel := t.In(options.Timezone).Format(timeFormat)
+		drawString(img, x-len(timeLabel)*7/2, plot.Max.Y+17, timeLabel, textColor)
+	}
+
+	project := func(p Point) (int, int) {
+		x := plot.Min.X
+		if timeSpan > 0 {
+			x += int(float64(plot.Dx()) * float64(p.Time.Sub(b.MinTime)) / float64(timeSpan))
+		}
+
+		y := plot.Max.Y - int(float64(plot.Dy())*(p.Value-b.MinValue)/(b.MaxValue-b.MinValue))
+		return x, y
+	}
+
+	for index, s := range series {
+		seriesColor := palette[index%len(palette)]
+
+		var prevX, prevY int
+		hasPrev := false
+
+		for _, point := range s.Points {
+			if math.IsNaN(point.Value) || math.IsInf(point.Value, 0) {
+				hasPrev = false
+				continue
+			}
+
+			x, y := project(point)
+			if hasPrev {
+				drawLine(img, prevX, prevY, x, y, seriesColor)
+			} else {
+				drawLine(img, x, y, x, y, seriesColor)
+			}
+
+			prevX, prevY, hasPrev = x, y, true
+		}
+
+		if index < maxLegendItems {
+			legendY := plotBottom + marginBottom + index*lineHeight
+			draw.Draw(
+				img,
+				image.Rect(marginLeft, legendY-8, marginLeft+10, legendY+1),
+				&image.Uniform{C: seriesColor},
+				image.Point{},
+				draw.Src,
+			)
+			drawString(img, marginLeft+16, legendY+1, s.Name, textColor)
+		}
+	}
+
+	if len(series) > maxLegendItems {
+		legendY := plotBottom + marginBottom + maxLegendItems*lineHeight
+		drawString(img, marginLeft, legendY+1, fmt.Sprintf("and %d more", len(series)-maxLegendItems), textColor)
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func getBounds(series []Series) (bounds, bool) {
+	b := bounds{MinValue: math.Inf(1), MaxValue: math.Inf(-1)}
+	found := false
+
+	for _, s := range series {
+		for _, point := range s.Points {
+			if math.IsNaN(point.Value) || math.IsInf(point.Value, 0) {
+				continue
+			}
+
+			if !found || point.Time.Before(b.MinTime) {
+				b.MinTime = point.Time
+			}
+
+			if !found || point.Time.After(b.MaxTime) {
+				b.MaxTime = point.Time
+			}
+
+			b.MinValue = math.Min(b.MinValue, point.Value)
+			b.MaxValue = math.Max(b.MaxValue, point.Value)
+			found = true
+		}
+	}
+
+	// A flat line is drawn in the middle of the chart.
+	if found && b.MinValue == b.MaxValue {
+		delta := math.Max(math.Abs(b.MinValue)*0.1, 1)
+		b.MinValue -= delta
+		b.MaxValue += delta
+	}
+
+	return b, found
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', 4, 64)
+}
+
+func drawString(img *image.RGBA, x, y int, text string, c color.Color) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+func drawHorizontalLine(img *image.RGBA, x1, x2, y int, c color.Color) {
+	for x := x1; x <= x2; x++ {
+		img.Set(x, y, c)
+	}
+}
+
+func drawVerticalLine(img *image.RGBA, x, y1, y2 int, c color.Color) {
+	for y := y1; y <= y2; y++ {
+		img.Set(x, y, c)
+	}
+}
+
+// drawLine draws a 2px wide line using Bresenham's algorithm.
+func drawLine(img *image.RGBA, x1, y1, x2, y2 int, c color.Color) {
+	dx := abs(x2 - x1)
+	dy := -abs(y2 - y1)
+	sx, sy := 1, 1
+
+	if x1 > x2 {
+		sx = -1
+	}
+
+	if y1 > y2 {
+		sy = -1
+	}
+
+	err := dx + dy
+
+	for {
+		img.Set(x1, y1, c)
+		img.Set(x1+1, y1, c)
+		img.Set(x1, y1+1, c)
+
+		if x1 == x2 && y1 == y2 {
+			return
+		}
+
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x1 += sx
+		}
+
+		if e2 <= dx {
+			err += dx
+			y1 += sy
+		}
+	}
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
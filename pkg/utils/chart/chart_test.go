@@ -0,0 +1,71 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderNoPoints(t *testing.T) {
+	t.Parallel()
+
+	_, err := Render([]Series{{Name: "empty"}}, Options{Width: 1000, Height: 500})
+	require.Error(t, err)
+	require.ErrorContains(t, err, "no data points to draw")
+}
+
+func TestRenderTooSmall(t *testing.T) {
+	t.Parallel()
+
+	series := []Series{{Name: "series", Points: []Point{{Time: time.Unix(0, 0), Value: 1}}}}
+
+	_, err := Render(series, Options{Width: 50, Height: 50})
+	require.Error(t, err)
+	require.ErrorContains(t, err, "image is too small")
+}
+
+func TestRenderOk(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	series := make([]Series, 12)
+
+	for index := range series {
+		points := make([]Point, 0)
+		for minute := 0; minute < 60; minute++ {
+			points = append(points, Point{
+				Time:  start.Add(time.Duration(minute) * time.Minute),
+				Value: math.Sin(float64(minute+index) / 10),
+			})
+		}
+
+		points = append(points, Point{Time: start.Add(time.Hour), Value: math.NaN()})
+		series[index] = Series{Name: "series", Points: points}
+	}
+
+	data, err := Render(series, Options{Width: 1000, Height: 500, Timezone: time.UTC})
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 1000, img.Bounds().Dx())
+	require.Equal(t, 500, img.Bounds().Dy())
+}
+
+func TestGetBoundsFlatLine(t *testing.T) {
+	t.Parallel()
+
+	b, found := getBounds([]Series{{Points: []Point{
+		{Time: time.Unix(100, 0), Value: 5},
+		{Time: time.Unix(50, 0), Value: 5},
+	}}})
+	require.True(t, found)
+	require.Equal(t, time.Unix(50, 0), b.MinTime)
+	require.Equal(t, time.Unix(100, 0), b.MaxTime)
+	require.InDelta(t, 4, b.MinValue, 0.001)
+	require.InDelta(t, 6, b.MaxValue, 0.001)
+}
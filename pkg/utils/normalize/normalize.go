@@ -9,3 +9,10 @@ func NormalizeString(input string) string {
 	reg := regexp.MustCompile("[^a-zA-Z0-9]+")
 	return strings.ToLower(reg.ReplaceAllString(input, ""))
 }
+
+// NormalizeCommand converts a string into something that can be used as a part
+// of a Telegram command or callback prefix, like "EU West" -> "eu_west".
+func NormalizeCommand(input string) string {
+	reg := regexp.MustCompile("[^a-zA-Z0-9]+")
+	return strings.Trim(strings.ToLower(reg.ReplaceAllString(input, "_")), "_")
+}
@@ -10,3 +10,10 @@ func TestNormalizeString(t *testing.T) {
 	t.Parallel()
 	require.Equal(t, "abc", NormalizeString("abcабв"))
 }
+
+func TestNormalizeCommand(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "grafana", NormalizeCommand("Grafana"))
+	require.Equal(t, "eu_west_1", NormalizeCommand(" EU West-1 "))
+	require.Equal(t, "abc", NormalizeCommand("abcабв"))
+}
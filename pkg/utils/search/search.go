@@ -0,0 +1,229 @@
+package search
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MinScore is the min score for the item to be considered matching the query.
+	MinScore = 0.5
+	// AmbiguityMargin is how much the best match should score higher than the next one
+	// for it to be considered clearly the best, otherwise the user is asked to choose.
+	AmbiguityMargin = 0.05
+	// MinTypoTokenLength is the min length of the query word for it to match a word
+	// with a typo, so short words like "cpu" won't match unrelated ones like "gpu".
+	MinTypoTokenLength = 4
+	// MinTypoSimilarity is the min similarity of words, based on the edit distance
+	// between them, for them to be considered the same word with a typo.
+	MinTypoSimilarity = 0.7
+
+	// Weights of the score parts, adding up to 1.
+	overlapWeight  = 0.7
+	coverageWeight = 0.2
+	prefixWeight   = 0.1
+)
+
+type Result[T any] struct {
+	Item  T
+	Score float64
+}
+
+// Results are sorted by score, with the best match first.
+type Results[T any] []Result[T]
+
+// Search returns the items matching the query, best ones first. The item can have
+// multiple names (like a panel name with and without the dashboard name),
+// and it is scored by the best matching one. Items with the same score keep their order.
+func Search[T any](items []T, query string, getNames func(T) []string) Results[T] {
+	results := make(Results[T], 0)
+
+	for _, item := range items {
+		score := 0.0
+		for _, name := range getNames(item) {
+			score = max(score, Score(query, name))
+		}
+
+		if score >= MinScore {
+			results = append(results, Result[T]{Item: item, Score: score})
+		}
+	}
+
+	slices.SortStableFunc(results, func(first, second Result[T]) int {
+		switch {
+		case first.Score > second.Score:
+			return -1
+		case first.Score < second.Score:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return results
+}
+
+// Best returns the best matching item, if there is any.
+func (r Results[T]) Best() (*T, bool) {
+	if len(r) == 0 {
+		return nil, false
+	}
+
+	return &r[0].Item, true
+}
+
+// IsAmbiguous returns true if the best match does not score clearly higher than the next one.
+func (r Results[T]) IsAmbiguous() bool {
+	return len(r) > 1 && r[0].Score-r[1].Score < AmbiguityMargin
+}
+
+// Top returns at most count best matching items.
+func (r Results[T]) Top(count int) []T {
+	items := make([]T, 0, min(count, len(r)))
+	for index := 0; index < len(r) && index < count; index++ {
+		items = append(items, r[index].Item)
+	}
+
+	return items
+}
+
+// Score returns how well the name matches the query, from 0 to 1, combining
+// how well the query words match the name words (exactly, as a prefix, as a part
+// of the word, or with a typo), how many of the name words are matched, and whether
+// the name starts with the query. Exact matches ignoring case and punctuation score 1.
+func Score(query, name string) float64 {
+	queryTokens := Tokenize(query)
+	nameTokens := Tokenize(name)
+
+	if len(queryTokens) == 0 || len(nameTokens) == 0 {
+		return 0
+	}
+
+	joinedQuery := strings.Join(queryTokens, "")
+	joinedName := strings.Join(nameTokens, "")
+
+	if joinedQuery == joinedName {
+		return 1
+	}
+
+	matchedNameTokens := make([]bool, len(nameTokens))
+	similaritiesSum := 0.0
+
+	for _, queryToken := range queryTokens {
+		bestSimilarity := 0.0
+
+		for index, nameToken := range nameTokens {
+			similarity := TokenSimilarity(queryToken, nameToken)
+			if similarity > 0 {
+				matchedNameTokens[index] = true
+			}
+
+			bestSimilarity = max(bestSimilarity, similarity)
+		}
+
+		similaritiesSum += bestSimilarity
+	}
+
+	overlap := similaritiesSum / float64(len(queryTokens))
+
+	// The query written without spaces or split differently, like "nodecpu" for "Node CPU",
+	// is still matched, as it was matched before the search was scored.
+	if strings.Contains(joinedName, joinedQuery) {
+		overlap = max(overlap, 0.7)
+	}
+
+	matchedCount := 0
+	for _, matched := range matchedNameTokens {
+		if matched {
+			matchedCount++
+		}
+	}
+
+	coverage := float64(matchedCount) / float64(len(nameTokens))
+
+	prefix := 0.0
+	if strings.HasPrefix(joinedName, joinedQuery) {
+		prefix = 1
+	}
+
+	return min(overlapWeight*overlap+coverageWeight*coverage+prefixWeight*prefix, 1)
+}
+
+// TokenSimilarity returns how similar the query word is to the name word, from 0 to 1.
+func TokenSimilarity(queryToken, nameToken string) float64 {
+	switch {
+	case queryToken == nameToken:
+		return 1
+	case strings.HasPrefix(nameToken, queryToken):
+		return 0.9
+	case strings.Contains(nameToken, queryToken):
+		return 0.7
+	}
+
+	queryLength := utf8.RuneCountInString(queryToken)
+	if queryLength < MinTypoTokenLength {
+		return 0
+	}
+
+	maxLength := max(queryLength, utf8.RuneCountInString(nameToken))
+	similarity := 1 - float64(EditDistance(queryToken, nameToken))/float64(maxLength)
+
+	if similarity < MinTypoSimilarity {
+		return 0
+	}
+
+	return 0.8 * similarity
+}
+
+var tokenSeparatorRegex = regexp.MustCompile("[^a-z0-9]+")
+
+// Tokenize splits the string into lowercase words, ignoring punctuation.
+func Tokenize(input string) []string {
+	tokens := make([]string, 0)
+
+	for _, token := range tokenSeparatorRegex.Split(strings.ToLower(input), -1) {
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
+
+// EditDistance returns the number of insertions, deletions, substitutions and
+// transpositions of adjacent characters needed to turn one string into another.
+func EditDistance(first, second string) int {
+	a, b := []rune(first), []rune(second)
+
+	// Only the last three rows are needed to calculate the next one.
+	prevPrev := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(prev[j]+1, current[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				current[j] = min(current[j], prevPrev[j-2]+1)
+			}
+		}
+
+		prevPrev, prev, current = prev, current, prevPrev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getNames(name string) []string {
+	return []string{name}
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"cpu", "usage", "per", "core"}, Tokenize(" CPU usage (per-core)"))
+	require.Empty(t, Tokenize("абв"))
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 0, EditDistance("memory", "memory"))
+	require.Equal(t, 1, EditDistance("memroy", "memory"))
+	require.Equal(t, 1, EditDistance("memory", "memry"))
+	require.Equal(t, 1, EditDistance("cpu", "gpu"))
+	require.Equal(t, 3, EditDistance("", "cpu"))
+	require.Equal(t, 3, EditDistance("kitten", "sitting"))
+}
+
+func TestTokenSimilarity(t *testing.T) {
+	t.Parallel()
+
+	require.InDelta(t, 1, TokenSimilarity("cpu", "cpu"), 0.001)
+	require.InDelta(t, 0.9, TokenSimilarity("mem", "memory"), 0.001)
+	require.InDelta(t, 0.7, TokenSimilarity("manager", "alertmanager"), 0.001)
+	require.Greater(t, TokenSimilarity("memroy", "memory"), 0.0)
+	require.Zero(t, TokenSimilarity("cpu", "gpu"))
+	require.Zero(t, TokenSimilarity("memory", "network"))
+}
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+
+	require.InDelta(t, 1, Score("cpu usage", "CPU Usage"), 0.001)
+	require.InDelta(t, 1, Score("nodecpu", "Node CPU"), 0.001)
+	require.Zero(t, Score("", "CPU"))
+	require.Zero(t, Score("cpu", ""))
+	require.Zero(t, Score("cpu", "GPU"))
+
+	// Fewer extra words score higher.
+	require.Greater(t, Score("cpu", "CPU usage"), Score("cpu", "CPU usage per core"))
+	// Names starting with the query score higher.
+	require.Greater(t, Score("cpu", "CPU usage"), Score("cpu", "Usage of CPU"))
+	// Matching words score higher than matching parts of words.
+	require.Greater(t, Score("manager", "Manager"), Score("manager", "Alertmanager"))
+	// Typos are tolerated.
+	require.GreaterOrEqual(t, Score("memroy", "Memory"), MinScore)
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"Network traffic", "CPU usage per core", "GPU", "CPU usage", "Memory"}
+
+	results := Search(names, "cpu usage", getNames)
+	require.Equal(t, []string{"CPU usage", "CPU usage per core"}, results.Top(5))
+	require.Equal(t, []string{"CPU usage"}, results.Top(1))
+	require.False(t, results.IsAmbiguous())
+
+	best, found := results.Best()
+	require.True(t, found)
+	require.Equal(t, "CPU usage", *best)
+
+	results = Search(names, "unknown", getNames)
+	require.Empty(t, results)
+	require.False(t, results.IsAmbiguous())
+
+	best, found = results.Best()
+	require.False(t, found)
+	require.Nil(t, best)
+}
+
+func TestSearchMultipleNames(t *testing.T) {
+	t.Parallel()
+
+	results := Search([]string{"first", "second"}, "second", func(name string) []string {
+		return []string{"dashboard " + name, name}
+	})
+
+	require.Len(t, results, 1)
+	require.InDelta(t, 1, results[0].Score, 0.001)
+}
+
+func TestSearchAmbiguous(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"Memory available", "Memory usage", "Network"}
+
+	results := Search(names, "memory", getNames)
+	require.True(t, results.IsAmbiguous())
+	// Items with the same score keep their order.
+	require.Equal(t, []string{"Memory available"}, results.Top(1))
+
+	results = Search([]string{"CPU", "CPU"}, "cpu", getNames)
+	require.True(t, results.IsAmbiguous())
+}
@@ -2,13 +2,18 @@ package utils
 
 import (
 	"fmt"
+	"main/pkg/constants"
 	"main/pkg/logger"
 	"main/pkg/types"
 	"math"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 func ParseRenderOptions(query string) (types.RenderOptions, bool) {
@@ -61,12 +66,14 @@ func SerializeQueryString(qs map[string]string) string {
 
 func GetEmojiByStatus(state string) string {
 	switch strings.ToLower(state) {
-	case "inactive", "ok", "normal":
+	case "inactive", "ok", "normal", "resolved":
 		return "🟢"
 	case "pending":
 		return "🟡"
 	case "firing", "alerting":
 		return "🔴"
+	case "suppressed":
+		return "🔕"
 	default:
 		return "[" + state + "]"
 	}
@@ -157,6 +164,44 @@ func ParseSilenceWithDuration(
 	return silence, ""
 }
 
+// ApplySilenceEdit returns a copy of the silence with the changes from params applied.
+// Params are "duration=<duration>" for changing the silence end time (counting from now),
+// "comment=<comment>" for changing the comment, and matchers, which replace
+// the existing matchers if provided.
+func ApplySilenceEdit(cmd string, silence types.Silence, params string) (*types.Silence, string) {
+	edited := silence
+	edited.Status = types.SilenceStatus{}
+	matchers := types.SilenceMatchers{}
+
+	for _, matcher := range types.QueryMatcherFromKeyValueString(params) {
+		switch matcher.Key {
+		case "duration":
+			duration, err := time.ParseDuration(matcher.Value)
+			if err != nil || duration <= 0 || matcher.Operator != constants.SilenceMatcherEqual {
+				return nil, "Invalid duration provided!"
+			}
+
+			edited.EndsAt = time.Now().Add(duration)
+		case "comment":
+			edited.Comment = matcher.Value
+		default:
+			matchers = append(matchers, types.MatcherFromQueryMatcher(matcher))
+		}
+	}
+
+	if len(matchers) > 0 {
+		edited.Matchers = matchers
+	}
+
+	if edited.EndsAt.Equal(silence.EndsAt) &&
+		edited.Comment == silence.Comment &&
+		edited.Matchers.Equals(silence.Matchers) {
+		return nil, fmt.Sprintf("Usage: %s <silence ID> [duration=<duration>] [comment=<comment>] [params]", cmd)
+	}
+
+	return &edited, ""
+}
+
 func StrToFloat64(s string) float64 {
 	f, err := strconv.ParseFloat(s, 64)
 	if err != nil {
@@ -203,3 +248,179 @@ func FormatDate(timezone *time.Location) func(date time.Time) string {
 		return date.In(timezone).Format(time.RFC1123)
 	}
 }
+
+// SubstituteVariables replaces dashboard variables references in all the formats
+// Grafana supports ($var, ${var} and [[var]]) with their values.
+func SubstituteVariables(query string, values map[string]string) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	// Longer names go first, so $instance_name is not replaced as $instance.
+	sort.Slice(names, func(i, j int) bool {
+		return len(names[i]) > len(names[j])
+	})
+
+	for _, name := range names {
+		query = strings.ReplaceAll(query, "${"+name+"}", values[name])
+		query = strings.ReplaceAll(query, "[["+name+"]]", values[name])
+		query = strings.ReplaceAll(query, "$"+name, values[name])
+	}
+
+	return query
+}
+
+var labelValuesQueryRegex = regexp.MustCompile(`^\s*label_values\(\s*(?:(.+?)\s*,\s*)?([a-zA-Z_][a-zA-Z0-9_]*)\s*\)\s*$`)
+
+// ParseLabelValuesQuery parses a Prometheus variable query like label_values(metric, label)
+// or label_values(label), returning the series selector (if any) and the label name.
+func ParseLabelValuesQuery(query string) (string, string, bool) {
+	matches := labelValuesQueryRegex.FindStringSubmatch(query)
+	if matches == nil {
+		return "", "", false
+	}
+
+	return matches[1], matches[2], true
+}
+
+// ParseCustomVariableQuery parses a custom variable query, which is a comma-separated
+// list of values, each of them is either a value or "text : value".
+func ParseCustomVariableQuery(query string) []types.GrafanaTemplateVariableOption {
+	options := make([]types.GrafanaTemplateVariableOption, 0)
+
+	for _, item := range strings.Split(query, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		text, value := item, item
+		if split := strings.SplitN(item, " : ", 2); len(split) == 2 {
+			text, value = strings.TrimSpace(split[0]), strings.TrimSpace(split[1])
+		}
+
+		options = append(options, types.GrafanaTemplateVariableOption{
+			Text:  types.GrafanaTemplateVariableValue(text),
+			Value: types.GrafanaTemplateVariableValue(value),
+		})
+	}
+
+	return options
+}
+
+// ApplyVariableRegex filters variable values by the variable regex (like "/prod-.*/"),
+// taking the first capture group as a value if the regex has one.
+func ApplyVariableRegex(regex string, values []string) ([]string, error) {
+	if regex == "" {
+		return values, nil
+	}
+
+	pattern := strings.TrimPrefix(regex, "/")
+	if lastSlash := strings.LastIndex(pattern, "/"); lastSlash != -1 {
+		pattern = pattern[:lastSlash]
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]string, 0)
+	seen := map[string]bool{}
+
+	for _, value := range values {
+		matches := compiled.FindStringSubmatch(value)
+		if matches == nil {
+			continue
+		}
+
+		if len(matches) > 1 {
+			value = matches[1]
+		}
+
+		if !seen[value] {
+			seen[value] = true
+			filtered = append(filtered, value)
+		}
+	}
+
+	return filtered, nil
+}
+
+// ParseCommandOptions splits the command arguments into the options with the given
+// names, passed as key=value before anything else, and the rest of the text, which is
+// returned as is, keeping its whitespaces and newlines. Only the known options are parsed,
+// so queries containing "=" are left intact. Values with spaces can be double-quoted,
+// like dashboard="My dashboard".
+func ParseCommandOptions(text string, names ...string) (map[string]string, string) {
+	options := make(map[string]string)
+
+	// removing first argument as it's always the command
+	rest := strings.TrimLeftFunc(text, unicode.IsSpace)
+	rest = strings.TrimLeftFunc(strings.TrimLeftFunc(rest, isNotSpace), unicode.IsSpace)
+
+	for rest != "" {
+		arg := rest
+		if index := strings.IndexFunc(rest, unicode.IsSpace); index != -1 {
+			arg = rest[:index]
+		}
+
+		key, value, found := strings.Cut(arg, "=")
+		if !found || !slices.Contains(names, key) {
+			break
+		}
+
+		if quoted := rest[len(key)+1:]; strings.HasPrefix(quoted, `"`) {
+			if end := strings.Index(quoted[1:], `"`); end != -1 {
+				value = quoted[1 : end+1]
+				arg = rest[:len(key)+1+end+2]
+			}
+		}
+
+		options[key] = value
+		rest = strings.TrimLeftFunc(rest[len(arg):], unicode.IsSpace)
+	}
+
+	return options, rest
+}
+
+func isNotSpace(r rune) bool {
+	return !unicode.IsSpace(r)
+}
+
+// FormatTable formats the table as lines with columns aligned,
+// to be displayed with a monospace font.
+func FormatTable(headers []string, rows [][]string) []string {
+	widths := make([]int, len(headers))
+
+	for _, row := range append([][]string{headers}, rows...) {
+		for index, cell := range row {
+			if index < len(widths) {
+				widths[index] = max(widths[index], utf8.RuneCountInString(cell))
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+
+	for _, row := range append([][]string{headers}, rows...) {
+		var sb strings.Builder
+
+		for index, cell := range row {
+			if index >= len(widths) {
+				break
+			}
+
+			sb.WriteString(cell)
+
+			if index < len(widths)-1 {
+				sb.WriteString(strings.Repeat(" ", widths[index]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+
+		lines = append(lines, sb.String())
+	}
+
+	return lines
+}
@@ -49,9 +49,11 @@ func TestGetEmojiByStatus(t *testing.T) {
 	require.Equal(t, "🟢", GetEmojiByStatus("inactive"))
 	require.Equal(t, "🟢", GetEmojiByStatus("ok"))
 	require.Equal(t, "🟢", GetEmojiByStatus("normal"))
+	require.Equal(t, "🟢", GetEmojiByStatus("resolved"))
 	require.Equal(t, "🟡", GetEmojiByStatus("pending"))
 	require.Equal(t, "🔴", GetEmojiByStatus("firing"))
 	require.Equal(t, "🔴", GetEmojiByStatus("alerting"))
+	require.Equal(t, "🔕", GetEmojiByStatus("suppressed"))
 	require.Equal(t, "[unknown]", GetEmojiByStatus("unknown"))
 }
 
@@ -136,3 +138,145 @@ func TestFormatDate(t *testing.T) {
 	parsed := FormatDate(timezone)(date)
 	require.Equal(t, "Thu, 01 Jan 1970 00:00:00 GMT", parsed)
 }
+
+func TestApplySilenceEdit(t *testing.T) {
+	t.Parallel()
+
+	silence := types.Silence{
+		ID:      "silence",
+		Comment: "comment",
+		EndsAt:  time.Now().Add(time.Hour),
+		Matchers: types.SilenceMatchers{
+			{IsEqual: true, IsRegex: false, Name: "key1", Value: "value1"},
+		},
+		Status: types.SilenceStatus{State: "active"},
+	}
+
+	_, valid1 := ApplySilenceEdit("/edit_silence", silence, "")
+	require.Equal(t, "Usage: /edit_silence <silence ID> [duration=<duration>] [comment=<comment>] [params]", valid1)
+
+	_, valid2 := ApplySilenceEdit("/edit_silence", silence, "duration=invalid")
+	require.Equal(t, "Invalid duration provided!", valid2)
+
+	_, valid3 := ApplySilenceEdit("/edit_silence", silence, "key1=value1 comment=comment")
+	require.Equal(t, "Usage: /edit_silence <silence ID> [duration=<duration>] [comment=<comment>] [params]", valid3)
+
+	edited, valid4 := ApplySilenceEdit("/edit_silence", silence, "duration=2h comment=\"new comment\" key2!=value2")
+	require.Empty(t, valid4)
+	require.Equal(t, "silence", edited.ID)
+	require.Equal(t, "new comment", edited.Comment)
+	require.Empty(t, edited.Status.State)
+	require.Equal(t, time.Now().Add(2*time.Hour).Second(), edited.EndsAt.Second())
+	require.Equal(t, types.SilenceMatchers{
+		{IsEqual: false, IsRegex: false, Name: "key2", Value: "value2"},
+	}, edited.Matchers)
+
+	// The original silence is not modified.
+	require.Equal(t, "comment", silence.Comment)
+	require.Equal(t, "active", silence.Status.State)
+	require.Len(t, silence.Matchers, 1)
+}
+
+func TestSubstituteVariables(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]string{"instance": "host1", "instance_name": "name", "env": "prod"}
+
+	require.Equal(
+		t,
+		`up{instance="host1", name="name", env="prod", job="prod"}`,
+		SubstituteVariables(`up{instance="$instance", name="$instance_name", env="${env}", job="[[env]]"}`, values),
+	)
+}
+
+func TestParseLabelValuesQuery(t *testing.T) {
+	t.Parallel()
+
+	selector, label, ok := ParseLabelValuesQuery(`label_values(up{job="node"}, instance)`)
+	require.True(t, ok)
+	require.Equal(t, `up{job="node"}`, selector)
+	require.Equal(t, "instance", label)
+
+	selector, label, ok = ParseLabelValuesQuery("label_values(instance)")
+	require.True(t, ok)
+	require.Empty(t, selector)
+	require.Equal(t, "instance", label)
+
+	_, _, ok = ParseLabelValuesQuery("query_result(up)")
+	require.False(t, ok)
+}
+
+func TestParseCustomVariableQuery(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []types.GrafanaTemplateVariableOption{
+		{Text: "prod", Value: "prod"},
+		{Text: "Staging", Value: "staging"},
+	}, ParseCustomVariableQuery("prod, Staging : staging,"))
+}
+
+func TestApplyVariableRegex(t *testing.T) {
+	t.Parallel()
+
+	values := []string{"host1:9100", "host1:9200", "host2:9100", "other"}
+
+	filtered, err := ApplyVariableRegex("", values)
+	require.NoError(t, err)
+	require.Equal(t, values, filtered)
+
+	filtered, err = ApplyVariableRegex("/host.*/", values)
+	require.NoError(t, err)
+	require.Equal(t, []string{"host1:9100", "host1:9200", "host2:9100"}, filtered)
+
+	filtered, err = ApplyVariableRegex("/(host[0-9]+):.*/i", values)
+	require.NoError(t, err)
+	require.Equal(t, []string{"host1", "host2"}, filtered)
+
+	_, err = ApplyVariableRegex("/(/", values)
+	require.Error(t, err)
+}
+
+func TestParseCommandOptions(t *testing.T) {
+	t.Parallel()
+
+	options, rest := ParseCommandOptions("/query datasource=Prometheus up{job=\"test\"} step=1m", "datasource", "step")
+	require.Equal(t, map[string]string{"datasource": "Prometheus"}, options)
+	require.Equal(t, "up{job=\"test\"} step=1m", rest)
+
+	// whitespaces and newlines in the rest of the text are kept
+	options4, rest4 := ParseCommandOptions("/ds_query  from=now-1h\nto=now Postgres select *\n  from  users", "from", "to")
+	require.Equal(t, map[string]string{"from": "now-1h", "to": "now"}, options4)
+	require.Equal(t, "Postgres select *\n  from  users", rest4)
+
+	// quoted values can have spaces, unclosed quotes are kept
+	options6, rest6 := ParseCommandOptions("/annotate dashboard=\"My dashboard\" tags=\"api Deployed", "dashboard", "tags")
+	require.Equal(t, map[string]string{"dashboard": "My dashboard", "tags": "\"api"}, options6)
+	require.Equal(t, "Deployed", rest6)
+
+	// options after the first unknown argument are not parsed
+	options5, rest5 := ParseCommandOptions("/annotate Deployed tags=api", "tags")
+	require.Empty(t, options5)
+	require.Equal(t, "Deployed tags=api", rest5)
+
+	options2, rest2 := ParseCommandOptions("/query", "datasource")
+	require.Empty(t, options2)
+	require.Empty(t, rest2)
+
+	options3, rest3 := ParseCommandOptions("", "datasource")
+	require.Empty(t, options3)
+	require.Empty(t, rest3)
+}
+
+func TestFormatTable(t *testing.T) {
+	t.Parallel()
+
+	lines := FormatTable(
+		[]string{"name", "value"},
+		[][]string{{"first", "1"}, {"второй", "22", "ignored"}},
+	)
+	require.Equal(t, []string{
+		"name    value",
+		"first   1",
+		"второй  22",
+	}, lines)
+}